@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/deis/workflow-manager-api/config"
+	"github.com/deis/workflow-manager-api/pkg/data"
+)
+
+const (
+	compactCheckinsCommand = "compact-checkins"
+	compactCheckinsUsage   = `usage: %s compact-checkins [-retention-days <days>]
+
+  compact every cluster checkin from before the start of the UTC day <days> days ago into daily
+  summaries. -retention-days defaults to the CHECKIN_RETENTION_DAYS config value
+`
+)
+
+// runCompactCheckins runs the compact-checkins command with the given args (not including
+// "compact-checkins" itself), and returns the process exit code
+func runCompactCheckins(args []string) int {
+	flags := flag.NewFlagSet(compactCheckinsCommand, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintf(os.Stderr, compactCheckinsUsage, os.Args[0]) }
+	retentionDays := flags.Int(
+		"retention-days",
+		config.Spec.CheckinRetentionDays,
+		"the number of days to keep raw checkins for",
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *retentionDays < 1 || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	db, err := data.NewDB()
+	if err != nil {
+		log.Printf("unable to create connection to DB (%s)", err)
+		return 1
+	}
+	defer db.Close()
+	if err := data.VerifyPersistentStorage(db); err != nil {
+		log.Printf("unable to verify persistent storage (%s)", err)
+		return 1
+	}
+
+	cutoff := data.CompactionCutoff(time.Now(), *retentionDays)
+	n, err := data.NewGormStore(db).CompactCheckins(cutoff)
+	// days are compacted one at a time, so some may have been compacted even if there was an error
+	fmt.Printf("compacted %d checkins from before %s\n", n, cutoff.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("compact-checkins failed (%s)", err)
+		return 1
+	}
+	return 0
+}
@@ -10,6 +10,34 @@ type Specification struct {
 	DBPass         string `envconfig:"DBPASS" required:"true"`
 	DBURL          string `envconfig:"DBURL" required:"true"`
 	DBName         string `envconfig:"DBNAME" required:"true"`
+	// SigningKey is the base64 encoded Ed25519 private key (or 32 byte seed) that release data is
+	// signed with. If it's empty, release data isn't signed
+	SigningKey string `envconfig:"SIGNING_KEY"`
+	// CheckinRetentionDays is the number of days that raw cluster checkins are kept for. Older
+	// checkins are compacted into daily summaries. If it's 0, checkins are never compacted
+	CheckinRetentionDays int `envconfig:"CHECKIN_RETENTION_DAYS"`
+	// ClusterDormancyDays is the number of days a cluster can go without checking in before it's
+	// considered dormant. If it's 0, data.DefaultDormancyDays is used
+	ClusterDormancyDays int `envconfig:"CLUSTER_DORMANCY_DAYS"`
+	// SQLLogLevel is which database queries are logged. It's one of "none", "errors" or "all". If
+	// it's empty, only the errors of failed queries are logged
+	SQLLogLevel string `envconfig:"SQL_LOG_LEVEL"`
+	// CheckinClusterRatePerHour and CheckinClusterBurst limit how often each cluster ID can check in.
+	// If either is 0, a default is used. If the rate is negative, check-ins aren't limited by cluster
+	CheckinClusterRatePerHour int `envconfig:"CHECKIN_CLUSTER_RATE_PER_HOUR"`
+	CheckinClusterBurst       int `envconfig:"CHECKIN_CLUSTER_BURST"`
+	// CheckinIPRatePerHour and CheckinIPBurst limit how often each source IP can check in. If either
+	// is 0, a default is used. If the rate is negative, check-ins aren't limited by IP
+	CheckinIPRatePerHour int `envconfig:"CHECKIN_IP_RATE_PER_HOUR"`
+	CheckinIPBurst       int `envconfig:"CHECKIN_IP_BURST"`
+	// CheckinMinIntervalSeconds is the shortest time between a cluster's recorded check-ins. Sooner
+	// check-ins are acknowledged, but not recorded. If it's 0, a default is used, and if it's
+	// negative, every check-in is recorded
+	CheckinMinIntervalSeconds int `envconfig:"CHECKIN_MIN_INTERVAL_SECONDS"`
+	// CheckinTrustForwardedFor indicates that the source IP of a check-in is the last address in its
+	// X-Forwarded-For header, which is the one that the proxy appended. Only set it when the server
+	// is behind a single proxy that appends to the header
+	CheckinTrustForwardedFor bool `envconfig:"CHECKIN_TRUST_FORWARDED_FOR"`
 }
 
 // Spec is an exportable variable that contains workflow manager config data
@@ -15,6 +15,19 @@ import (
 // Make sure not to overwrite this file after you generated it because all your edits would be lost!
 
 func main() {
+	// the migrate, publisher-token and compact-checkins commands manage the database instead of
+	// running the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case migrateCommand:
+			os.Exit(runMigrate(os.Args[2:]))
+		case publisherTokenCommand:
+			os.Exit(runPublisherToken(os.Args[2:]))
+		case compactCheckinsCommand:
+			os.Exit(runCompactCheckins(os.Args[2:]))
+		}
+	}
+
 	swaggerSpec, err := spec.New(restapi.SwaggerJSON, "")
 	if err != nil {
 		log.Fatalln(err)
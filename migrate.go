@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	migrateCommand = "migrate"
+	migrateUsage   = `usage: %s migrate <up|down|status> [-to <version>]
+
+  up      apply all pending migrations, or only those up to and including -to
+  down    revert the newest applied migration, or all migrations newer than -to
+  status  list every migration and whether it has been applied
+`
+)
+
+// runMigrate runs the migrate command with the given args (not including "migrate" itself), and
+// returns the process exit code
+func runMigrate(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return 2
+	}
+	subcommand := args[0]
+	flags := flag.NewFlagSet(migrateCommand+" "+subcommand, flag.ContinueOnError)
+	to := flags.Int("to", -1, "the schema version to migrate to")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	db, err := data.NewDB()
+	if err != nil {
+		log.Printf("unable to create connection to DB (%s)", err)
+		return 1
+	}
+	defer db.Close()
+
+	switch subcommand {
+	case "up":
+		if *to < 0 {
+			*to = data.LatestSchemaVersion()
+		}
+		err = data.MigrateUp(db, *to)
+	case "down":
+		if *to < 0 {
+			*to, err = previousSchemaVersion(db)
+			if err != nil {
+				break
+			}
+		}
+		err = data.MigrateDown(db, *to)
+	case "status":
+		err = printMigrationStatus(db, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, migrateUsage, os.Args[0])
+		return 2
+	}
+	if err != nil {
+		log.Printf("migrate %s failed (%s)", subcommand, err)
+		return 1
+	}
+	return 0
+}
+
+// previousSchemaVersion returns the version of the newest applied migration before the current
+// one, or 0 if there's at most one applied migration
+func previousSchemaVersion(db *gorm.DB) (int, error) {
+	statuses, err := data.GetMigrationStatus(db)
+	if err != nil {
+		return 0, err
+	}
+	current, err := data.SchemaVersion(db)
+	if err != nil {
+		return 0, err
+	}
+	prev := 0
+	for _, status := range statuses {
+		if status.Applied && status.Version < current && status.Version > prev {
+			prev = status.Version
+		}
+	}
+	return prev, nil
+}
+
+func printMigrationStatus(db *gorm.DB, out io.Writer) error {
+	statuses, err := data.GetMigrationStatus(db)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tAPPLIED AT\tDESCRIPTION")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = data.Timestamp{Time: status.AppliedAt}.String()
+		}
+		desc := status.Description
+		if desc == "" {
+			desc = "(unknown to this version of the code)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, appliedAt, desc)
+	}
+	return w.Flush()
+}
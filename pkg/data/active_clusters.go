@@ -0,0 +1,174 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/rest"
+	"github.com/jinzhu/gorm"
+)
+
+// BucketInterval is the size of the time buckets that clusters are counted in
+type BucketInterval string
+
+const (
+	// BucketDay is a UTC day
+	BucketDay BucketInterval = "day"
+	// BucketWeek is a week that starts on Monday, UTC
+	BucketWeek BucketInterval = "week"
+	// BucketMonth is a calendar month, UTC
+	BucketMonth BucketInterval = "month"
+)
+
+type errInvalidBucketInterval struct {
+	interval string
+}
+
+func (e errInvalidBucketInterval) Error() string {
+	return fmt.Sprintf(
+		"invalid %s %q. must be one of %s, %s or %s",
+		rest.IntervalQueryStringKey,
+		e.interval,
+		BucketDay,
+		BucketWeek,
+		BucketMonth,
+	)
+}
+
+// ParseBucketInterval returns the BucketInterval named by str
+func ParseBucketInterval(str string) (BucketInterval, error) {
+	switch b := BucketInterval(str); b {
+	case BucketDay, BucketWeek, BucketMonth:
+		return b, nil
+	default:
+		return "", errInvalidBucketInterval{interval: str}
+	}
+}
+
+// truncate returns the start of the bucket that t is in
+func (b BucketInterval) truncate(t time.Time) time.Time {
+	day := truncateToDay(t)
+	switch b {
+	case BucketWeek:
+		// time.Weekday starts on Sunday, and Postgres' date_trunc('week', ...) starts on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// next returns the start of the bucket after the one that starts at start
+func (b BucketInterval) next(start time.Time) time.Time {
+	switch b {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// ActiveClustersQuery selects the checkins to count active clusters from. Create one with
+// NewActiveClustersQuery
+type ActiveClustersQuery struct {
+	// Since is the earliest checkin time to count
+	Since time.Time
+	// Until is the checkin time before which checkins are counted
+	Until time.Time
+	// Interval is the size of each bucket
+	Interval BucketInterval
+}
+
+// NewActiveClustersQuery returns a new ActiveClustersQuery. Returns ErrImpossibleFilter if since
+// isn't before until, an error if interval isn't a valid BucketInterval, or errTooManyBuckets if
+// more than MaxStatsBuckets buckets overlap since and until
+func NewActiveClustersQuery(since, until time.Time, interval string) (*ActiveClustersQuery, error) {
+	if !since.Before(until) {
+		return nil, ErrImpossibleFilter{
+			vals: []keyAndTime{
+				keyAndTime{key: rest.SinceQueryStringKey, time: since},
+				keyAndTime{key: rest.UntilQueryStringKey, time: until},
+			},
+			reason: fmt.Sprintf("%s needs to be greater than %s", rest.UntilQueryStringKey, rest.SinceQueryStringKey),
+		}
+	}
+	bucketInterval, err := ParseBucketInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBuckets(since, until, bucketInterval, MaxStatsBuckets); err != nil {
+		return nil, err
+	}
+	return &ActiveClustersQuery{Since: since, Until: until, Interval: bucketInterval}, nil
+}
+
+// activeClustersResponse type that represents a row of the active clusters query
+type activeClustersResponse struct {
+	Bucket   string `gorm:"column_name:bucket"`
+	Clusters int64  `gorm:"column_name:clusters"`
+}
+
+// makeActiveClusters returns a bucket for each of query's intervals that overlaps
+// [query.Since, query.Until). counts is the number of active clusters in each bucket, keyed by
+// the bucket's start formatted with statsDateFmt. Buckets that aren't in counts have a count of 0
+func makeActiveClusters(query *ActiveClustersQuery, counts map[string]int64) models.ActiveClusters {
+	ret := models.ActiveClusters{Interval: string(query.Interval), Data: []*models.ActiveClustersBucket{}}
+	for start := query.Interval.truncate(query.Since); start.Before(query.Until); start = query.Interval.next(start) {
+		date := start.Format(statsDateFmt)
+		count := counts[date]
+		ret.Data = append(ret.Data, &models.ActiveClustersBucket{Start: date, Clusters: &count})
+	}
+	return ret
+}
+
+// GetActiveClusters returns the number of distinct clusters that checked in during each of
+// query's buckets. The first and last buckets only count the checkins in
+// [query.Since, query.Until), so they may be partial. A day of compacted checkins is counted if
+// any part of it is in that range. This query uses Postgres' date_trunc function, so it doesn't
+// work on other databases
+func GetActiveClusters(db *gorm.DB, query *ActiveClustersQuery) (models.ActiveClusters, error) {
+	var rows []activeClustersResponse
+	execDB := db.Raw(checkinSpansSQL+`SELECT to_char(date_trunc(?, first_seen), 'YYYY-MM-DD') AS bucket,
+		COUNT(DISTINCT cluster_id) AS clusters
+		FROM checkin_spans
+		WHERE last_seen >= ? AND first_seen < ?
+		GROUP BY bucket`,
+		string(query.Interval),
+		Timestamp{Time: query.Since},
+		Timestamp{Time: query.Until},
+	).Find(&rows)
+	if execDB.Error != nil {
+		return models.ActiveClusters{}, execDB.Error
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] = row.Clusters
+	}
+	return makeActiveClusters(query, counts), nil
+}
+
+// activeClusterCountResponse type that represents a row of the active cluster count query
+type activeClusterCountResponse struct {
+	Clusters int `gorm:"column_name:clusters"`
+}
+
+// GetActiveClusterCount returns the number of distinct clusters that checked in at or after since
+func GetActiveClusterCount(db *gorm.DB, since time.Time) (int, error) {
+	var rows []activeClusterCountResponse
+	execDB := db.Raw(checkinSpansSQL+`SELECT COUNT(DISTINCT cluster_id) AS clusters
+		FROM checkin_spans
+		WHERE last_seen >= ?`,
+		Timestamp{Time: since},
+	).Find(&rows)
+	if execDB.Error != nil {
+		return 0, execDB.Error
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].Clusters, nil
+}
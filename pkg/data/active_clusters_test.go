@@ -0,0 +1,81 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestNewActiveClustersQuery(t *testing.T) {
+	now := time.Now()
+	type testCase struct {
+		since    time.Time
+		until    time.Time
+		interval string
+		valid    bool
+	}
+	testCases := []testCase{
+		{since: now.Add(-time.Hour), until: now, interval: "day", valid: true},
+		{since: now.Add(-time.Hour), until: now, interval: "week", valid: true},
+		{since: now.Add(-time.Hour), until: now, interval: "month", valid: true},
+		{since: now.Add(-time.Hour), until: now, interval: "year", valid: false},
+		{since: now.Add(-time.Hour), until: now, interval: "", valid: false},
+		{since: now, until: now, interval: "day", valid: false},
+		{since: now, until: now.Add(-time.Hour), interval: "day", valid: false},
+		// since and until are in different days, so MaxStatsBuckets days overlap them
+		{since: now.AddDate(0, 0, -MaxStatsBuckets+1), until: now, interval: "day", valid: true},
+		{since: now.AddDate(0, 0, -MaxStatsBuckets), until: now, interval: "day", valid: false},
+		{since: now.AddDate(0, 0, -MaxStatsBuckets), until: now, interval: "week", valid: true},
+		{since: time.Time{}, until: now, interval: "month", valid: false},
+	}
+	for i, tc := range testCases {
+		query, err := NewActiveClustersQuery(tc.since, tc.until, tc.interval)
+		if tc.valid {
+			assert.NoErr(t, err)
+			assert.Equal(t, string(query.Interval), tc.interval, "interval")
+		} else {
+			assert.True(t, err != nil, "test case %d: error not returned when expected", i)
+		}
+	}
+}
+
+func TestBucketIntervalTruncate(t *testing.T) {
+	// a Wednesday
+	tm := time.Date(2016, time.June, 15, 13, 14, 15, 0, time.UTC)
+	type testCase struct {
+		interval BucketInterval
+		start    string
+		next     string
+	}
+	testCases := []testCase{
+		{interval: BucketDay, start: "2016-06-15", next: "2016-06-16"},
+		{interval: BucketWeek, start: "2016-06-13", next: "2016-06-20"},
+		{interval: BucketMonth, start: "2016-06-01", next: "2016-07-01"},
+	}
+	for _, tc := range testCases {
+		start := tc.interval.truncate(tm)
+		assert.Equal(t, start.Format(statsDateFmt), tc.start, fmt.Sprintf("start of %s", tc.interval))
+		assert.Equal(t, tc.interval.next(start).Format(statsDateFmt), tc.next, fmt.Sprintf("start of the next %s", tc.interval))
+	}
+	// Sunday is the last day of the week that started on the previous Monday
+	sunday := time.Date(2016, time.June, 19, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, BucketWeek.truncate(sunday).Format(statsDateFmt), "2016-06-13", "start of week")
+}
+
+func TestMakeActiveClusters(t *testing.T) {
+	since := time.Date(2016, time.June, 15, 12, 0, 0, 0, time.UTC)
+	query, err := NewActiveClustersQuery(since, since.AddDate(0, 0, 15), "week")
+	assert.NoErr(t, err)
+	active := makeActiveClusters(query, map[string]int64{"2016-06-20": 4})
+	assert.Equal(t, active.Interval, "week", "interval")
+	starts := []string{}
+	counts := []int64{}
+	for _, bucket := range active.Data {
+		starts = append(starts, bucket.Start)
+		counts = append(counts, *bucket.Clusters)
+	}
+	assert.Equal(t, starts, []string{"2016-06-13", "2016-06-20", "2016-06-27"}, "bucket starts")
+	assert.Equal(t, counts, []int64{0, 4, 0}, "bucket counts")
+}
@@ -0,0 +1,39 @@
+package data
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// advisoryLockKey is the key of a Postgres advisory lock. Every replica of the API shares the same
+// database, so jobs that every replica runs, but that must not run concurrently, take one of these
+// first. The keys are only compared with each other, but they're offset from 0 so that they're
+// unlikely to collide with other applications that share the database
+type advisoryLockKey int64
+
+const (
+	migrationsLockKey advisoryLockKey = 0x776d61000001 + iota
+	checkinCompactionLockKey
+	dormancyDetectionLockKey
+	clusterSnapshotsLockKey
+)
+
+// lockTx takes the Postgres advisory lock with the given key, blocking until any other transaction
+// that holds it ends. The lock is held until tx commits or rolls back, so statements that tx runs
+// after lockTx returns see everything that the previous holder committed. It's a no-op on other
+// dialects (i.e. the in-memory sqlite DB used in tests), which only have a single process
+func lockTx(tx *gorm.DB, key advisoryLockKey) error {
+	if tx.Dialect().GetName() != postgresDialect {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", int64(key)).Error
+}
+
+// lockTxShared takes the Postgres advisory lock with the given key in shared mode. Any number of
+// transactions can hold it in shared mode at once, but not while another one holds it with lockTx.
+// Like lockTx, it's held until tx ends, and it's a no-op on other dialects
+func lockTxShared(tx *gorm.DB, key advisoryLockKey) error {
+	if tx.Dialect().GetName() != postgresDialect {
+		return nil
+	}
+	return tx.Exec("SELECT pg_advisory_xact_lock_shared(?)", int64(key)).Error
+}
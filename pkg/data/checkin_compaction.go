@@ -0,0 +1,319 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+// checkinSpansSQL is a WITH clause for the checkin_spans table expression. It has a
+// (cluster_id, first_seen, last_seen, checkins) row for each raw checkin in clusters_checkins, and
+// one for each daily summary of compacted checkins in clusters_checkins_daily. Queries that
+// aggregate checkins by cluster select from it so that they still count compacted checkins
+const checkinSpansSQL = `WITH checkin_spans AS (
+		SELECT cluster_id, created_at AS first_seen, created_at AS last_seen, 1 AS checkins
+		FROM clusters_checkins
+		UNION ALL
+		SELECT cluster_id, first_seen, last_seen, checkins
+		FROM clusters_checkins_daily
+	) `
+
+// checkinSnapshotsSQL is a WITH clause for the checkin_snapshots table expression. It has a
+// (cluster_id, created_at, data) row for each raw checkin in clusters_checkins, and one for each
+// daily summary of compacted checkins in clusters_checkins_daily. A summary's created_at is the
+// first time the cluster checked in that day, and its data holds the distinct component versions
+// the cluster reported that day
+const checkinSnapshotsSQL = `WITH checkin_snapshots AS (
+		SELECT cluster_id, created_at, data
+		FROM (` + checkinsWithDataSQL + `) AS clusters_checkins
+		UNION ALL
+		SELECT cluster_id, first_seen AS created_at, data
+		FROM clusters_checkins_daily
+	) `
+
+// checkinSnapshotsTableName is the name of the table expression that checkinSnapshotsSQL defines
+const checkinSnapshotsTableName = "checkin_snapshots"
+
+// CompactionCutoff returns the time before which checkins are compacted when raw checkins are
+// kept for retentionDays days. It's the start of the UTC day retentionDays days before now
+func CompactionCutoff(now time.Time, retentionDays int) time.Time {
+	return truncateToDay(now.AddDate(0, 0, -retentionDays))
+}
+
+// dailySummary is the parsed form of a clustersCheckinsDailyTable row
+type dailySummary struct {
+	clusterID  string
+	day        time.Time
+	firstSeen  time.Time
+	lastSeen   time.Time
+	checkins   int64
+	components map[string]*models.ComponentVersion
+}
+
+func newDailySummary(clusterID string, day time.Time) *dailySummary {
+	return &dailySummary{
+		clusterID:  clusterID,
+		day:        day,
+		components: make(map[string]*models.ComponentVersion),
+	}
+}
+
+// addComponents adds the distinct component versions in cluster to d. Only each component's name,
+// train and version are kept
+func (d *dailySummary) addComponents(cluster models.Cluster) {
+	for _, cv := range cluster.Components {
+		if cv == nil || cv.Component == nil || cv.Version == nil {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", cv.Component.Name, cv.Version.Train, cv.Version.Version)
+		d.components[key] = &models.ComponentVersion{
+			Component: &models.Component{Name: cv.Component.Name},
+			Version:   &models.Version{Train: cv.Version.Train, Version: cv.Version.Version},
+		}
+	}
+}
+
+// addSpan adds checkins checkins between firstSeen and lastSeen to d
+func (d *dailySummary) addSpan(firstSeen, lastSeen time.Time, checkins int64) {
+	if d.checkins == 0 || firstSeen.Before(d.firstSeen) {
+		d.firstSeen = firstSeen
+	}
+	if d.checkins == 0 || lastSeen.After(d.lastSeen) {
+		d.lastSeen = lastSeen
+	}
+	d.checkins += checkins
+}
+
+// addCheckin adds a single raw checkin to d
+func (d *dailySummary) addCheckin(row clustersCheckinsTable) error {
+	createdAt, err := row.createdAtTime()
+	if err != nil {
+		return err
+	}
+	cluster, err := parseJSONCluster([]byte(row.Data))
+	if err != nil {
+		return errParsingCluster{origErr: err}
+	}
+	d.addSpan(createdAt, createdAt, 1)
+	d.addComponents(cluster)
+	return nil
+}
+
+// addSummary merges an existing daily summary row for the same cluster and day into d
+func (d *dailySummary) addSummary(row clustersCheckinsDailyTable) error {
+	firstSeen, err := row.firstSeenTime()
+	if err != nil {
+		return err
+	}
+	lastSeen, err := row.lastSeenTime()
+	if err != nil {
+		return err
+	}
+	cluster, err := parseJSONCluster([]byte(row.Data))
+	if err != nil {
+		return errParsingCluster{origErr: err}
+	}
+	d.addSpan(firstSeen, lastSeen, row.Checkins)
+	d.addComponents(cluster)
+	return nil
+}
+
+// row returns the clusters_checkins_daily row for d. The components are sorted so that the same
+// summary always has the same data
+func (d *dailySummary) row() (clustersCheckinsDailyTable, error) {
+	keys := make([]string, 0, len(d.components))
+	for key := range d.components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	cluster := models.Cluster{ID: d.clusterID, Components: make([]*models.ComponentVersion, len(keys))}
+	for i, key := range keys {
+		cluster.Components[i] = d.components[key]
+	}
+	js, err := json.Marshal(cluster)
+	if err != nil {
+		return clustersCheckinsDailyTable{}, err
+	}
+	return clustersCheckinsDailyTable{
+		ClusterID: d.clusterID,
+		Day:       Timestamp{Time: d.day}.String(),
+		FirstSeen: Timestamp{Time: d.firstSeen}.String(),
+		LastSeen:  Timestamp{Time: d.lastSeen}.String(),
+		Checkins:  d.checkins,
+		Data:      string(js),
+	}, nil
+}
+
+// summarizeCheckins returns a daily summary for each cluster that has a checkin in rows, keyed on
+// cluster ID. All of the rows must be on the UTC day that starts at day
+func summarizeCheckins(day time.Time, rows []clustersCheckinsTable) (map[string]*dailySummary, error) {
+	ret := make(map[string]*dailySummary)
+	for _, row := range rows {
+		summary, ok := ret[row.ClusterID]
+		if !ok {
+			summary = newDailySummary(row.ClusterID, day)
+			ret[row.ClusterID] = summary
+		}
+		if err := summary.addCheckin(row); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// oldestCheckinDay returns the start of the UTC day of the oldest checkin before cutoff. Returns
+// ErrNotFound if there are no checkins before cutoff
+func oldestCheckinDay(db *gorm.DB, cutoff time.Time) (time.Time, error) {
+	var oldest clustersCheckinsTable
+	findDB := db.
+		Where(fmt.Sprintf("%s < ?", clustersCheckinsTableClusterCreatedAtKey), Timestamp{Time: cutoff}).
+		Order(clustersCheckinsTableClusterCreatedAtKey).
+		First(&oldest)
+	if findDB.Error != nil {
+		return time.Time{}, findDB.Error
+	}
+	createdAt, err := oldest.createdAtTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return truncateToDay(createdAt), nil
+}
+
+// compactCheckinDay replaces the raw checkins on the UTC day that starts at day with daily
+// summaries, and returns the number of checkins that were compacted. It must run in a transaction.
+// It takes the compaction advisory lock before it reads anything, so that when replicas compact
+// the same day at once, the later ones see the earlier one's summaries and deletes, instead of
+// summarizing the same checkins again
+func compactCheckinDay(tx *gorm.DB, day time.Time) (int, error) {
+	if err := lockTx(tx, checkinCompactionLockKey); err != nil {
+		return 0, err
+	}
+	var rows []clustersCheckinsTable
+	findDB := checkinsWithData(tx).Where(
+		fmt.Sprintf(
+			"%s >= ? AND %s < ?",
+			clustersCheckinsTableClusterCreatedAtKey,
+			clustersCheckinsTableClusterCreatedAtKey,
+		),
+		Timestamp{Time: day},
+		Timestamp{Time: day.AddDate(0, 0, 1)},
+	).Find(&rows)
+	if findDB.Error != nil {
+		return 0, findDB.Error
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	summaries, err := summarizeCheckins(day, rows)
+	if err != nil {
+		return 0, err
+	}
+	for clusterID, summary := range summaries {
+		where := clustersCheckinsDailyTable{ClusterID: clusterID, Day: Timestamp{Time: day}.String()}
+		var existing clustersCheckinsDailyTable
+		existingDB := tx.Where(where).First(&existing)
+		if existingDB.Error != nil && existingDB.Error != ErrNotFound {
+			return 0, existingDB.Error
+		}
+		found := existingDB.Error == nil
+		if found {
+			if err := summary.addSummary(existing); err != nil {
+				return 0, err
+			}
+		}
+		row, err := summary.row()
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			err = tx.Model(&existing).Updates(map[string]interface{}{
+				clustersCheckinsDailyTableFirstSeenKey: row.FirstSeen,
+				clustersCheckinsDailyTableLastSeenKey:  row.LastSeen,
+				clustersCheckinsDailyTableCheckinsKey:  row.Checkins,
+				clustersCheckinsDailyTableDataKey:      row.Data,
+			}).Error
+		} else {
+			err = tx.Create(&row).Error
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	// delete exactly the rows that were summarized, so that a checkin recorded for this day while
+	// the compaction runs isn't lost
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.CheckinsID
+	}
+	deleteDB := tx.Where(fmt.Sprintf("%s IN (?)", clustersCheckinsTableIDKey), ids).Delete(clustersCheckinsTable{})
+	if deleteDB.Error != nil {
+		return 0, deleteDB.Error
+	}
+	// the summaries have their own copy of the component versions, so snapshots that only the
+	// deleted checkins used aren't needed any more
+	if err := deleteOrphanedSnapshots(tx, rows); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// CompactCheckins replaces all of the raw checkins before the start of cutoff's UTC day with a
+// summary of each cluster's checkins on each day, and returns the number of checkins that were
+// compacted. Each day is compacted in its own transaction, so if an error is returned, the days
+// before the failed one stay compacted. It's safe to run on several replicas at once
+func CompactCheckins(db *gorm.DB, cutoff time.Time) (int, error) {
+	cutoff = truncateToDay(cutoff)
+	total := 0
+	for {
+		day, err := oldestCheckinDay(db, cutoff)
+		if err == ErrNotFound {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		op := fmt.Sprintf("compact checkins on %s", day.Format(statsDateFmt))
+		tx := db.Begin()
+		if tx.Error != nil {
+			return total, txErr{orig: nil, err: tx.Error, op: op}
+		}
+		n, err := compactCheckinDay(tx, day)
+		if err != nil {
+			if rbErr := tx.Rollback().Error; rbErr != nil {
+				return total, txErr{orig: err, err: rbErr, op: op}
+			}
+			return total, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return total, txErr{orig: nil, err: err, op: op}
+		}
+		total += n
+	}
+}
+
+// RunCheckinCompaction compacts the checkins in store that are older than retentionDays days
+// (see CompactionCutoff) once immediately, then once every interval until stop is closed. Errors
+// are logged, and the next run tries again
+func RunCheckinCompaction(store CheckinStore, retentionDays int, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cutoff := CompactionCutoff(time.Now(), retentionDays)
+		n, err := store.CompactCheckins(cutoff)
+		if err != nil {
+			log.Printf("data.CompactCheckins error (%s)", err)
+		} else if n > 0 {
+			log.Printf("compacted %d checkins from before %s", n, cutoff.Format(statsDateFmt))
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
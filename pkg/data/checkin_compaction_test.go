@@ -0,0 +1,112 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func TestCompactionCutoff(t *testing.T) {
+	now := time.Date(2016, time.June, 10, 18, 30, 0, 0, time.UTC)
+	assert.Equal(t, CompactionCutoff(now, 1), time.Date(2016, time.June, 9, 0, 0, 0, 0, time.UTC), "cutoff")
+	assert.Equal(t, CompactionCutoff(now, 30), time.Date(2016, time.May, 11, 0, 0, 0, 0, time.UTC), "cutoff")
+}
+
+func TestSummarizeCheckins(t *testing.T) {
+	day := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	checkins := []struct {
+		id       string
+		t        time.Time
+		versions []string
+	}{
+		{"cluster1", day.Add(3 * time.Hour), []string{"2.0.0"}},
+		{"cluster1", day.Add(time.Hour), []string{"2.0.0"}},
+		{"cluster1", day.Add(5 * time.Hour), []string{"2.1.0"}},
+		{"cluster2", day.Add(2 * time.Hour), nil},
+	}
+	rows := make([]clustersCheckinsTable, len(checkins))
+	for i, checkin := range checkins {
+		cluster := models.Cluster{ID: checkin.id}
+		for _, version := range checkin.versions {
+			cv := testComponentVersion()
+			cv.Version.Version = version
+			cluster.Components = append(cluster.Components, cv)
+		}
+		js, err := json.Marshal(cluster)
+		assert.NoErr(t, err)
+		rows[i] = newClustersCheckinsTable(fmt.Sprintf("%d", i+1), checkin.id, checkin.t, js)
+	}
+
+	summaries, err := summarizeCheckins(day, rows)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(summaries), 2, "number of summaries")
+	row, err := summaries["cluster1"].row()
+	assert.NoErr(t, err)
+	assert.Equal(t, row.Day, "2016-06-01T00:00:00Z", "day")
+	assert.Equal(t, row.FirstSeen, "2016-06-01T01:00:00Z", "first seen")
+	assert.Equal(t, row.LastSeen, "2016-06-01T05:00:00Z", "last seen")
+	assert.Equal(t, row.Checkins, int64(3), "number of checkins")
+	cluster, err := parseJSONCluster([]byte(row.Data))
+	assert.NoErr(t, err)
+	assert.Equal(t, cluster.ID, "cluster1", "cluster ID")
+	// each distinct component version is kept once, without its release data
+	assert.Equal(t, len(cluster.Components), 2, "number of components")
+	assert.Equal(t, cluster.Components[0].Version.Version, "2.0.0", "version")
+	assert.Equal(t, cluster.Components[1].Version.Version, "2.1.0", "version")
+	assert.True(t, cluster.Components[0].Version.Data == nil, "release data was kept")
+
+	// merging with an earlier summary of the same day widens the span and adds the counts
+	earlier := newDailySummary("cluster1", day)
+	earlier.addSpan(day.Add(30*time.Minute), day.Add(4*time.Hour), 2)
+	earlierRow, err := earlier.row()
+	assert.NoErr(t, err)
+	merged := summaries["cluster1"]
+	assert.NoErr(t, merged.addSummary(earlierRow))
+	row, err = merged.row()
+	assert.NoErr(t, err)
+	assert.Equal(t, row.FirstSeen, "2016-06-01T00:30:00Z", "first seen")
+	assert.Equal(t, row.LastSeen, "2016-06-01T05:00:00Z", "last seen")
+	assert.Equal(t, row.Checkins, int64(5), "number of checkins")
+
+	row, err = summaries["cluster2"].row()
+	assert.NoErr(t, err)
+	cluster, err = parseJSONCluster([]byte(row.Data))
+	assert.NoErr(t, err)
+	assert.Equal(t, len(cluster.Components), 0, "number of components")
+}
+
+func TestCompactCheckinsDeletesOrphanedSnapshots(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	today := truncateToDay(time.Now())
+	old := testCluster()
+	old.Components[0].Version.Version = "1.0.0"
+	current := testCluster()
+	// the old payload is only used by checkins that are compacted, and the current one is also used
+	// by a checkin that's kept
+	assert.NoErr(t, CheckInCluster(db, clusterID, today.AddDate(0, 0, -3), old))
+	assert.NoErr(t, CheckInCluster(db, clusterID, today.AddDate(0, 0, -2), current))
+	assert.NoErr(t, CheckInCluster(db, clusterID, today.Add(time.Minute), current))
+	count := 0
+	assert.NoErr(t, db.Model(&clusterSnapshotsTable{}).Count(&count).Error)
+	assert.Equal(t, count, 2, "number of snapshots")
+
+	n, err := CompactCheckins(db, today)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 2, "number of compacted checkins")
+	var snapshots []clusterSnapshotsTable
+	assert.NoErr(t, db.Find(&snapshots).Error)
+	assert.Equal(t, len(snapshots), 1, "number of snapshots")
+	js, err := json.Marshal(current)
+	assert.NoErr(t, err)
+	assert.Equal(t, snapshots[0].Hash, hashClusterSnapshot(js), "snapshot hash")
+
+	// the kept checkin still has its data
+	checkin, err := GetClusterCheckinAt(db, clusterID, today.Add(time.Hour))
+	assert.NoErr(t, err)
+	assert.Equal(t, checkin.Components[0].Version.Version, current.Components[0].Version.Version, "version")
+}
@@ -40,7 +40,7 @@ func GetCluster(db *gorm.DB, id string) (models.Cluster, error) {
 
 func upsertCluster(db *gorm.DB, id string, cluster models.Cluster) (models.Cluster, error) {
 	// Check in
-	if err := CheckInCluster(db, id, time.Now(), cluster); err != nil {
+	if err := checkInCluster(db, id, time.Now(), cluster); err != nil {
 		return models.Cluster{}, err
 	}
 	js, err := json.Marshal(cluster)
@@ -53,23 +53,23 @@ func upsertCluster(db *gorm.DB, id string, cluster models.Cluster) (models.Clust
 	if countDB.Error != nil {
 		return models.Cluster{}, countDB.Error
 	}
-	var resDB *gorm.DB
+	created := false
 	if numExisting == 0 {
-		// no existing clusters, so create one
-		createDB := db.Create(&clustersTable{ClusterID: id, Data: string(js)})
-		if createDB.Error != nil {
-			return models.Cluster{}, createDB.Error
+		// no existing clusters, so create one. a concurrent checkin from the same cluster may create
+		// it first, in which case it's updated instead
+		created, err = createUnlessExists(db, &clustersTable{ClusterID: id, Data: string(js)})
+		if err != nil {
+			return models.Cluster{}, err
 		}
-		resDB = createDB
-	} else {
+	}
+	if !created {
 		updateDB := db.Save(&clustersTable{ClusterID: id, Data: string(js)})
 		if updateDB.Error != nil {
 			return models.Cluster{}, updateDB.Error
 		}
-		resDB = updateDB
-	}
-	if resDB.RowsAffected != 1 {
-		return models.Cluster{}, fmt.Errorf("%d rows were affected, but expected only 1", resDB.RowsAffected)
+		if updateDB.RowsAffected != 1 {
+			return models.Cluster{}, fmt.Errorf("%d rows were affected, but expected only 1", updateDB.RowsAffected)
+		}
 	}
 	retCluster, err := GetCluster(db, id)
 	if err != nil {
@@ -99,14 +99,57 @@ func UpsertCluster(db *gorm.DB, id string, cluster models.Cluster) (models.Clust
 	return ret, nil
 }
 
-// CheckInCluster creates a new record in the cluster checkins DB to indicate that the cluster has checked in right now
+// CheckInCluster creates a new record in the cluster checkins DB to indicate that the cluster has checked in right now.
+// The cluster JSON is stored once per distinct payload in the cluster_snapshots table, and the
+// checkin references it by hash
 func CheckInCluster(db *gorm.DB, id string, checkinTime time.Time, cluster models.Cluster) error {
+	txn := db.Begin()
+	if txn.Error != nil {
+		return txErr{orig: nil, err: txn.Error, op: "begin"}
+	}
+	if err := checkInCluster(txn, id, checkinTime, cluster); err != nil {
+		rbDB := txn.Rollback()
+		if rbDB.Error != nil {
+			return txErr{orig: err, err: rbDB.Error, op: "rollback"}
+		}
+		return err
+	}
+	comDB := txn.Commit()
+	if comDB.Error != nil {
+		return txErr{orig: nil, err: comDB.Error, op: "commit"}
+	}
+	return nil
+}
+
+// checkInCluster is CheckInCluster in the transaction tx. It holds the cluster snapshots lock in
+// shared mode until tx ends, so that checkin compaction can't delete the checkin's snapshot as
+// orphaned before the checkin that references it is committed
+func checkInCluster(tx *gorm.DB, id string, checkinTime time.Time, cluster models.Cluster) error {
 	js, err := json.Marshal(cluster)
 	if err != nil {
-		fmt.Println("error marshaling data")
+		return err
+	}
+	if err := lockTxShared(tx, clusterSnapshotsLockKey); err != nil {
+		return err
+	}
+	hash := hashClusterSnapshot(js)
+	if err := saveClusterSnapshot(tx, hash, js); err != nil {
+		log.Println("cluster snapshot db record not created", err)
+		return err
 	}
-	record := newClustersCheckinsTable("", id, checkinTime, js)
-	createdDB := db.Create(&record)
+	// the data column is left null, since the checkin's JSON is in its snapshot
+	createdDB := tx.Exec(
+		fmt.Sprintf(
+			"INSERT INTO %s (%s, %s, %s) VALUES (?, ?, ?)",
+			clustersCheckinsTableName,
+			clustersCheckinsTableClusterIDKey,
+			clustersCheckinsTableClusterCreatedAtKey,
+			clustersCheckinsTableSnapshotHashKey,
+		),
+		id,
+		Timestamp{Time: checkinTime},
+		hash,
+	)
 	if createdDB.Error != nil {
 		log.Println("cluster checkin db record not created", createdDB.Error)
 		return createdDB.Error
@@ -118,89 +161,172 @@ func CheckInCluster(db *gorm.DB, id string, checkinTime time.Time, cluster model
 }
 
 // FilterClustersByAge returns a slice of clusters whose various time fields match the requirements
-// in the given filter. Note that the filter's requirements are a conjunction, not a disjunction
-func FilterClustersByAge(db *gorm.DB, filter *ClusterAgeFilter) ([]*models.Cluster, error) {
-	var rows []clustersTable
-	execDB := db.Raw(`SELECT clusters.*
-		FROM clusters, clusters_checkins
-		WHERE clusters_checkins.cluster_id = clusters.cluster_id
-		GROUP BY clusters_checkins.cluster_id, clusters.cluster_id
-		HAVING MIN(clusters_checkins.created_at) > ?
-		AND MIN(clusters_checkins.created_at) < ?
-		AND MIN(clusters_checkins.created_at) > ?
-		AND MAX(clusters_checkins.created_at) < ?`,
-		Timestamp{Time: filter.CreatedAfter},
-		Timestamp{Time: filter.CreatedBefore},
-		Timestamp{Time: filter.CheckedInAfter},
-		Timestamp{Time: filter.CheckedInBefore},
-	).Find(&rows)
+// in the given filter, ordered by the time they first checked in. Note that the filter's
+// requirements are a conjunction, not a disjunction. If page is non-nil, only the clusters in that
+// page are returned, along with the cursor for the next page. The cursor is empty on the last page
+func FilterClustersByAge(db *gorm.DB, filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error) {
+	var rows []clustersAgeFilterResponse
+	execDB := clustersByAgeQuery(db, filter, page).Find(&rows)
 	if execDB.Error != nil {
-		return nil, execDB.Error
+		return nil, "", execDB.Error
 	}
 
-	clusters, err := makeClusters(rows)
+	n, next, err := page.trim(len(rows), func(i int) (clusterPosition, error) {
+		return positionFromFirstSeen(rows[i].FirstSeen, rows[i].ClusterID)
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return clusters, nil
+	clusterRows := make([]clustersTable, n)
+	for i, row := range rows[:n] {
+		clusterRows[i] = clustersTable{ClusterID: row.ClusterID, Data: row.Data}
+	}
+	clusters, err := makeClusters(clusterRows)
+	if err != nil {
+		return nil, "", err
+	}
+	return clusters, next, nil
 }
 
-// FilterClusterCheckins returns a slice of clusters whose various time fields match the requirements
-// in the given filter.
-func FilterClusterCheckins(db *gorm.DB, filter *ClusterCheckinsFilter) ([]*models.ClusterCheckin, error) {
-	var rows []clustersCheckinsFilterResponse
-	execDB := db.Raw(`SELECT cluster_id,
-		MIN(created_at) AS first_seen,
-		MAX(created_at) AS last_seen,
-		AGE(MAX(created_at), MIN(created_at)) AS cluster_age,
-		AGE(MAX(created_at), NOW()) AS last_checkin,
-		COUNT(1) AS checkins
-		FROM   clusters_checkins
-		GROUP  BY cluster_id
-		HAVING MIN(created_at) > ? AND MIN(created_at) < ?
-		ORDER  BY first_seen DESC;`,
+// ExportClustersByAge returns the same clusters as FilterClustersByAge, in the same order, as
+// ClusterRows that are read from the database one at a time. page's limit is ignored, so every
+// cluster after page's cursor is returned
+func ExportClustersByAge(db *gorm.DB, filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error) {
+	return newClusterAgeRows(clustersByAgeQuery(db, filter, page.withoutLimit()))
+}
+
+// clustersByAgeQuery returns the query for the clustersAgeFilterResponse rows of the clusters in
+// page that match filter
+func clustersByAgeQuery(db *gorm.DB, filter *ClusterAgeFilter, page *ClusterPage) *gorm.DB {
+	having, havingArgs := page.havingClause("MIN(checkin_spans.first_seen)", "clusters.cluster_id", false)
+	limit, limitArgs := page.limitClause()
+	args := []interface{}{
 		Timestamp{Time: filter.CreatedAfter},
 		Timestamp{Time: filter.CreatedBefore},
-	).Find(&rows)
+		Timestamp{Time: filter.CheckedInAfter},
+		Timestamp{Time: filter.CheckedInBefore},
+	}
+	args = append(append(args, havingArgs...), limitArgs...)
+	return db.Raw(checkinSpansSQL+`SELECT clusters.cluster_id, clusters.data,
+		MIN(checkin_spans.first_seen) AS first_seen
+		FROM clusters, checkin_spans
+		WHERE checkin_spans.cluster_id = clusters.cluster_id
+		GROUP BY checkin_spans.cluster_id, clusters.cluster_id
+		HAVING MIN(checkin_spans.first_seen) > ?
+		AND MIN(checkin_spans.first_seen) < ?
+		AND MIN(checkin_spans.first_seen) > ?
+		AND MAX(checkin_spans.last_seen) < ?`+having+`
+		ORDER BY first_seen ASC, clusters.cluster_id ASC`+limit,
+		args...,
+	)
+}
 
+// FilterClusterCheckins returns a slice of clusters whose various time fields match the requirements
+// in the given filter, newest first. If page is non-nil, only the clusters in that page are
+// returned, along with the cursor for the next page. The cursor is empty on the last page
+func FilterClusterCheckins(db *gorm.DB, filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	var rows []clustersCheckinsFilterResponse
+	execDB := clusterCheckinsQuery(db, filter, page).Find(&rows)
 	if execDB.Error != nil {
-		return nil, execDB.Error
+		return nil, "", execDB.Error
 	}
 
-	checkins, err := makeClusterCheckins(rows)
-	if err != nil {
-		return nil, err
+	return makePagedClusterCheckins(rows, page)
+}
+
+// ExportClusterCheckins returns the same checkin summaries as FilterClusterCheckins, in the same
+// order, as ClusterRows that are read from the database one at a time. page's limit is ignored, so
+// every summary after page's cursor is returned
+func ExportClusterCheckins(db *gorm.DB, filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error) {
+	return newClusterCheckinRows(clusterCheckinsQuery(db, filter, page.withoutLimit()))
+}
+
+// clusterCheckinsQuery returns the query for the checkin summaries of the clusters in page that
+// match filter
+func clusterCheckinsQuery(db *gorm.DB, filter *ClusterCheckinsFilter, page *ClusterPage) *gorm.DB {
+	having, havingArgs := page.havingClause("MIN(first_seen)", "cluster_id", true)
+	limit, limitArgs := page.limitClause()
+	args := []interface{}{
+		Timestamp{Time: filter.CreatedAfter},
+		Timestamp{Time: filter.CreatedBefore},
 	}
-	return checkins, nil
+	args = append(append(args, havingArgs...), limitArgs...)
+	return db.Raw(checkinSpansSQL+`SELECT cluster_id,
+		MIN(first_seen) AS first_seen,
+		MAX(last_seen) AS last_seen,
+		AGE(MAX(last_seen), MIN(first_seen)) AS cluster_age,
+		AGE(MAX(last_seen), NOW()) AS last_checkin,
+		SUM(checkins) AS checkins
+		FROM   checkin_spans
+		GROUP  BY cluster_id
+		HAVING MIN(first_seen) > ? AND MIN(first_seen) < ?`+having+`
+		ORDER  BY first_seen DESC, cluster_id DESC`+limit,
+		args...,
+	)
 }
 
 // FilterPersistentClusters returns a slice of clusters whose various time fields match the requirements
-// in the given filter.
-func FilterPersistentClusters(db *gorm.DB, filter *PersistentClustersFilter) ([]*models.ClusterCheckin, error) {
+// in the given filter, oldest first. If page is non-nil, only the clusters in that page are
+// returned, along with the cursor for the next page. The cursor is empty on the last page
+func FilterPersistentClusters(db *gorm.DB, filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
 	var rows []clustersCheckinsFilterResponse
-	execDB := db.Raw(`SELECT cluster_id,
-        MIN(created_at) AS first_seen,
-        MAX(created_at) AS last_seen,
-        AGE(MAX(created_at), MIN(created_at)) AS cluster_age,
-        COUNT(1) AS checkins
-        FROM clusters_checkins
-        GROUP  BY cluster_id
-        HAVING MIN(created_at) > ? AND MIN(created_at) < ?
-        AND COUNT(1) > 1 AND MAX(created_at) > ?
-		ORDER  BY first_seen ASC`,
+	execDB := persistentClustersQuery(db, filter, page).Find(&rows)
+	if execDB.Error != nil {
+		return nil, "", execDB.Error
+	}
+
+	return makePagedClusterCheckins(rows, page)
+}
+
+// ExportPersistentClusters returns the same checkin summaries as FilterPersistentClusters, in the
+// same order, as ClusterRows that are read from the database one at a time. page's limit is
+// ignored, so every summary after page's cursor is returned
+func ExportPersistentClusters(db *gorm.DB, filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error) {
+	return newClusterCheckinRows(persistentClustersQuery(db, filter, page.withoutLimit()))
+}
+
+// persistentClustersQuery returns the query for the checkin summaries of the persistent clusters
+// in page that match filter
+func persistentClustersQuery(db *gorm.DB, filter *PersistentClustersFilter, page *ClusterPage) *gorm.DB {
+	having, havingArgs := page.havingClause("MIN(first_seen)", "cluster_id", false)
+	limit, limitArgs := page.limitClause()
+	args := []interface{}{
 		Timestamp{Time: filter.Epoch},
 		Timestamp{Time: filter.Timestamp},
 		Timestamp{Time: filter.RelativeYesterday},
-	).Find(&rows)
-	if execDB.Error != nil {
-		return nil, execDB.Error
 	}
+	args = append(append(args, havingArgs...), limitArgs...)
+	return db.Raw(checkinSpansSQL+`SELECT cluster_id,
+        MIN(first_seen) AS first_seen,
+        MAX(last_seen) AS last_seen,
+        AGE(MAX(last_seen), MIN(first_seen)) AS cluster_age,
+        SUM(checkins) AS checkins
+        FROM checkin_spans
+        GROUP  BY cluster_id
+        HAVING MIN(first_seen) > ? AND MIN(first_seen) < ?
+        AND SUM(checkins) > 1 AND MAX(last_seen) > ?`+having+`
+		ORDER  BY first_seen ASC, cluster_id ASC`+limit,
+		args...,
+	)
+}
 
-	checkins, err := makeClusterCheckins(rows)
+// makePagedClusterCheckins converts rows, which may contain one more row than page's limit, into
+// the checkins in page and the cursor for the next page
+func makePagedClusterCheckins(
+	rows []clustersCheckinsFilterResponse,
+	page *ClusterPage,
+) ([]*models.ClusterCheckin, string, error) {
+	n, next, err := page.trim(len(rows), func(i int) (clusterPosition, error) {
+		return positionFromFirstSeen(rows[i].FirstSeen, rows[i].ClusterID)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	checkins, err := makeClusterCheckins(rows[:n])
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return checkins, nil
+	return checkins, next, nil
 }
 
 func makeClusters(rows []clustersTable) ([]*models.Cluster, error) {
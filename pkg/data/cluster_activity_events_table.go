@@ -0,0 +1,63 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	clusterActivityEventsTableName          = "cluster_activity_events"
+	clusterActivityEventsTableIDKey         = "event_id"
+	clusterActivityEventsTableClusterIDKey  = "cluster_id"
+	clusterActivityEventsTableEventKey      = "event"
+	clusterActivityEventsTableOccurredAtKey = "occurred_at"
+)
+
+// clusterActivityEventsTable type that expresses the `cluster_activity_events` postgres table
+// schema. Each row records a cluster going dormant or coming back from dormancy
+type clusterActivityEventsTable struct {
+	EventID    string `gorm:"primary_key;type:bigserial;column_name:event_id"`
+	ClusterID  string `gorm:"type:uuid;column_name:cluster_id"`
+	Event      string `gorm:"type:varchar(16);column_name:event"`
+	OccurredAt string `gorm:"type:timestamp;column_name:occurred_at"`
+}
+
+func newClusterActivityEventsTable(clusterID, event string, occurredAt time.Time) clusterActivityEventsTable {
+	return clusterActivityEventsTable{
+		ClusterID:  clusterID,
+		Event:      event,
+		OccurredAt: Timestamp{Time: occurredAt}.String(),
+	}
+}
+
+// BeforeSave is the gorm callback for saving a new cluster activity event
+func (c clusterActivityEventsTable) BeforeSave() error {
+	_, err := newTimestampFromStr(c.OccurredAt)
+	return err
+}
+
+func (c clusterActivityEventsTable) occurredAtTime() (time.Time, error) {
+	return time.Parse(StdTimestampFmt, c.OccurredAt)
+}
+
+func (c clusterActivityEventsTable) TableName() string {
+	return clusterActivityEventsTableName
+}
+
+func createClusterActivityEventsTable(db execer, dialect string) (sql.Result, error) {
+	// see createClustersCheckinsTable for why the ID type depends on the dialect
+	idType := "bigserial"
+	if dialect != postgresDialect {
+		idType = "integer"
+	}
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s %s PRIMARY KEY, %s uuid, %s varchar(16), %s timestamp )",
+		clusterActivityEventsTableName,
+		clusterActivityEventsTableIDKey,
+		idType,
+		clusterActivityEventsTableClusterIDKey,
+		clusterActivityEventsTableEventKey,
+		clusterActivityEventsTableOccurredAtKey,
+	))
+}
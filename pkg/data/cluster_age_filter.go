@@ -53,6 +53,14 @@ type ClusterAgeFilter struct {
 	CreatedAfter    time.Time
 }
 
+// clustersAgeFilterResponse type that represents a row of the cluster age filter query. It's a
+// row of the `clusters` table and the time that the cluster first checked in
+type clustersAgeFilterResponse struct {
+	ClusterID string `gorm:"type:uuid;column_name:cluster_id"`
+	Data      string `gorm:"type:json;column_name:data"`
+	FirstSeen string `gorm:"type:timestamp;column_name:first_seen"`
+}
+
 // NewClusterAgeFilter returns a new ClusterAgeFilter if the given times can result in a valid
 // query that would return clusters. If not, returns nil and an ErrImpossibleFilter error
 func NewClusterAgeFilter(
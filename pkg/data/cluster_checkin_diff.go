@@ -0,0 +1,160 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+// GetClusterCheckinByID returns the checkin of the given cluster with the given checkin ID.
+// Returns ErrNotFound if there's no such checkin
+func GetClusterCheckinByID(db *gorm.DB, clusterID, checkinID string) (models.ClusterCheckinRecord, error) {
+	id, err := strconv.ParseInt(checkinID, 10, 64)
+	if err != nil {
+		// checkin IDs are always integers, so there can't be a checkin with this ID
+		return models.ClusterCheckinRecord{}, ErrNotFound
+	}
+	row := clustersCheckinsTable{}
+	firstDB := checkinsWithData(db).Where(
+		fmt.Sprintf("%s = ? AND %s = ?", clustersCheckinsTableClusterIDKey, clustersCheckinsTableIDKey),
+		clusterID,
+		id,
+	).Limit(1).Find(&row)
+	if firstDB.Error != nil {
+		return models.ClusterCheckinRecord{}, firstDB.Error
+	}
+	return makeClusterCheckinRecord(row)
+}
+
+// GetClusterCheckinAt returns the given cluster's latest checkin at or before t. Returns
+// ErrNotFound if the cluster didn't check in at or before t
+func GetClusterCheckinAt(db *gorm.DB, clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	row := clustersCheckinsTable{}
+	firstDB := checkinsWithData(db).Where(
+		fmt.Sprintf("%s = ? AND %s <= ?", clustersCheckinsTableClusterIDKey, clustersCheckinsTableClusterCreatedAtKey),
+		clusterID,
+		Timestamp{Time: t},
+	).Order(fmt.Sprintf(
+		"%s DESC, %s DESC",
+		clustersCheckinsTableClusterCreatedAtKey,
+		clustersCheckinsTableIDKey,
+	)).Limit(1).Find(&row)
+	if firstDB.Error != nil {
+		return models.ClusterCheckinRecord{}, firstDB.Error
+	}
+	return makeClusterCheckinRecord(row)
+}
+
+// GetLastCheckinTime returns the time of the given cluster's latest raw checkin. Unlike
+// GetClusterCheckinAt, it only reads the checkin's created_at, and not its data. Returns
+// ErrNotFound if the cluster has no raw checkins, because it never checked in or all of its
+// checkins have been compacted
+func GetLastCheckinTime(db *gorm.DB, clusterID string) (time.Time, error) {
+	var createdAts []string
+	pluckDB := db.Model(&clustersCheckinsTable{}).
+		Where(fmt.Sprintf("%s = ?", clustersCheckinsTableClusterIDKey), clusterID).
+		Order(fmt.Sprintf("%s DESC", clustersCheckinsTableClusterCreatedAtKey)).
+		Limit(1).
+		Pluck(clustersCheckinsTableClusterCreatedAtKey, &createdAts)
+	if pluckDB.Error != nil {
+		return time.Time{}, pluckDB.Error
+	}
+	if len(createdAts) == 0 {
+		return time.Time{}, ErrNotFound
+	}
+	return clustersCheckinsTable{CreatedAt: createdAts[0]}.createdAtTime()
+}
+
+// componentsByName returns the components in record that have a name and a version, keyed on
+// component name
+func componentsByName(record models.ClusterCheckinRecord) map[string]*models.ComponentVersion {
+	ret := make(map[string]*models.ComponentVersion, len(record.Components))
+	for _, cv := range record.Components {
+		if cv == nil || cv.Component == nil || cv.Version == nil {
+			continue
+		}
+		ret[cv.Component.Name] = cv
+	}
+	return ret
+}
+
+// compareComponentVersions compares the versions of the same component in two checkins. It
+// returns 0 if they're the same release, and 1 or -1 if to is an upgrade or downgrade from from.
+// ok is false if they're different releases that can't be ordered, because they're on different
+// trains or either version string isn't a semantic version
+func compareComponentVersions(from, to *models.Version) (cmp int, ok bool) {
+	if from.Train == to.Train && from.Version == to.Version {
+		return 0, true
+	}
+	if from.Train != to.Train {
+		return 0, false
+	}
+	fromSemVer, fromErr := parseSemVer(from.Version)
+	toSemVer, toErr := parseSemVer(to.Version)
+	if fromErr != nil || toErr != nil {
+		return 0, false
+	}
+	cmp = toSemVer.compare(fromSemVer)
+	// versions like 2.0.0+build1 and 2.0.0+build2 have the same precedence, but aren't the same
+	// release
+	return cmp, cmp != 0
+}
+
+// DiffClusterCheckins returns the components that were added, removed, upgraded, downgraded or
+// otherwise changed between the from and to checkins of the cluster with the given ID. Components
+// are matched by name, and each list in the diff is sorted by component name
+func DiffClusterCheckins(clusterID string, from, to models.ClusterCheckinRecord) models.ClusterCheckinDiff {
+	diff := models.ClusterCheckinDiff{
+		ClusterID:  clusterID,
+		From:       &models.ClusterCheckinRef{ID: from.ID, CheckedInAt: from.CheckedInAt},
+		To:         &models.ClusterCheckinRef{ID: to.ID, CheckedInAt: to.CheckedInAt},
+		Added:      []*models.ComponentChange{},
+		Removed:    []*models.ComponentChange{},
+		Upgraded:   []*models.ComponentChange{},
+		Downgraded: []*models.ComponentChange{},
+		Changed:    []*models.ComponentChange{},
+	}
+	fromComponents := componentsByName(from)
+	toComponents := componentsByName(to)
+	names := make([]string, 0, len(fromComponents)+len(toComponents))
+	for name := range fromComponents {
+		names = append(names, name)
+	}
+	for name := range toComponents {
+		if _, ok := fromComponents[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fromCV, inFrom := fromComponents[name]
+		toCV, inTo := toComponents[name]
+		change := &models.ComponentChange{Name: name}
+		switch {
+		case !inFrom:
+			change.To = toCV.Version
+			diff.Added = append(diff.Added, change)
+		case !inTo:
+			change.From = fromCV.Version
+			diff.Removed = append(diff.Removed, change)
+		default:
+			change.From = fromCV.Version
+			change.To = toCV.Version
+			cmp, ok := compareComponentVersions(fromCV.Version, toCV.Version)
+			switch {
+			case !ok:
+				diff.Changed = append(diff.Changed, change)
+			case cmp > 0:
+				diff.Upgraded = append(diff.Upgraded, change)
+			case cmp < 0:
+				diff.Downgraded = append(diff.Downgraded, change)
+			}
+		}
+	}
+	return diff
+}
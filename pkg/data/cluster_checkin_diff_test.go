@@ -0,0 +1,69 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func checkinRecord(id string, components map[string]models.Version) models.ClusterCheckinRecord {
+	record := models.ClusterCheckinRecord{ID: id}
+	for name, version := range components {
+		vsn := version
+		record.Components = append(record.Components, &models.ComponentVersion{
+			Component: &models.Component{Name: name},
+			Version:   &vsn,
+		})
+	}
+	return record
+}
+
+func changeNames(changes []*models.ComponentChange) []string {
+	ret := []string{}
+	for _, change := range changes {
+		ret = append(ret, change.Name)
+	}
+	return ret
+}
+
+func TestDiffClusterCheckins(t *testing.T) {
+	from := checkinRecord("1", map[string]models.Version{
+		"deis-builder":    {Train: "stable", Version: "2.0.0"},
+		"deis-controller": {Train: "stable", Version: "2.1.0"},
+		"deis-database":   {Train: "stable", Version: "2.0.0"},
+		"deis-logger":     {Train: "stable", Version: "git-abc1234"},
+		"deis-minio":      {Train: "stable", Version: "2.0.0"},
+		"deis-registry":   {Train: "stable", Version: "2.0.0"},
+		"deis-router":     {Train: "stable", Version: "2.0.0-rc1"},
+	})
+	to := checkinRecord("2", map[string]models.Version{
+		"deis-builder":    {Train: "stable", Version: "2.0.0"},
+		"deis-controller": {Train: "stable", Version: "2.0.1"},
+		"deis-database":   {Train: "beta", Version: "2.0.0"},
+		"deis-logger":     {Train: "stable", Version: "git-def5678"},
+		"deis-monitor":    {Train: "stable", Version: "2.0.0"},
+		"deis-registry":   {Train: "stable", Version: "v2.0.0"},
+		"deis-router":     {Train: "stable", Version: "2.0.0"},
+	})
+	diff := DiffClusterCheckins(clusterID, from, to)
+	assert.Equal(t, diff.ClusterID, clusterID, "cluster ID")
+	assert.Equal(t, diff.From.ID, "1", "from checkin ID")
+	assert.Equal(t, diff.To.ID, "2", "to checkin ID")
+	assert.Equal(t, changeNames(diff.Added), []string{"deis-monitor"}, "added components")
+	assert.Equal(t, changeNames(diff.Removed), []string{"deis-minio"}, "removed components")
+	assert.Equal(t, changeNames(diff.Upgraded), []string{"deis-router"}, "upgraded components")
+	assert.Equal(t, changeNames(diff.Downgraded), []string{"deis-controller"}, "downgraded components")
+	assert.Equal(t, changeNames(diff.Changed), []string{"deis-database", "deis-logger", "deis-registry"}, "changed components")
+
+	assert.True(t, diff.Added[0].From == nil, "added component had a from version")
+	assert.Equal(t, diff.Added[0].To.Version, "2.0.0", "added component version")
+	assert.True(t, diff.Removed[0].To == nil, "removed component had a to version")
+	assert.Equal(t, diff.Upgraded[0].From.Version, "2.0.0-rc1", "upgraded component from version")
+	assert.Equal(t, diff.Upgraded[0].To.Version, "2.0.0", "upgraded component to version")
+
+	diff = DiffClusterCheckins(clusterID, from, from)
+	for _, changes := range [][]*models.ComponentChange{diff.Added, diff.Removed, diff.Upgraded, diff.Downgraded, diff.Changed} {
+		assert.Equal(t, len(changes), 0, "number of changes between identical checkins")
+	}
+}
@@ -0,0 +1,203 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/rest"
+	"github.com/go-swagger/go-swagger/strfmt"
+	"github.com/jinzhu/gorm"
+)
+
+// checkinCursor is the position of a checkin in the (created_at, checkins_id) ordering of the
+// clusters_checkins table. It's encoded into the opaque cursor strings that clients see
+type checkinCursor struct {
+	CreatedAt string `json:"t"`
+	ID        int64  `json:"id"`
+}
+
+func (c checkinCursor) String() string {
+	return encodeCursor(c)
+}
+
+// after returns true if row comes after c in the (created_at, checkins_id) ordering
+func (c checkinCursor) after(row clustersCheckinsTable, rowCreatedAt time.Time) bool {
+	cursorCreatedAt, err := time.Parse(StdTimestampFmt, c.CreatedAt)
+	if err != nil {
+		return false
+	}
+	if rowCreatedAt.Equal(cursorCreatedAt) {
+		id, err := strconv.ParseInt(row.CheckinsID, 10, 64)
+		return err == nil && id > c.ID
+	}
+	return rowCreatedAt.After(cursorCreatedAt)
+}
+
+func parseCheckinCursor(str string) (*checkinCursor, error) {
+	cursor := checkinCursor{}
+	if err := decodeCursor(str, &cursor); err != nil {
+		return nil, err
+	}
+	if _, err := time.Parse(StdTimestampFmt, cursor.CreatedAt); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// ClusterCheckinHistoryQuery selects a single page of a cluster's checkins, ordered from oldest
+// to newest. Create one with NewClusterCheckinHistoryQuery
+type ClusterCheckinHistoryQuery struct {
+	ClusterID string
+	// Since is the earliest checkin time to include. The zero time means there's no lower bound
+	Since time.Time
+	// Until is the checkin time before which checkins are included. The zero time means there's no
+	// upper bound
+	Until time.Time
+	// Limit is the maximum number of checkins in the page
+	Limit int
+	// after is the position of the last checkin of the previous page, or nil for the first page
+	after *checkinCursor
+}
+
+// NewClusterCheckinHistoryQuery returns a new ClusterCheckinHistoryQuery. cursor is the NextCursor
+// of the previous page, or the empty string for the first page. Returns ErrImpossibleFilter if
+// since isn't before until, or ErrInvalidCursor if cursor can't be decoded
+func NewClusterCheckinHistoryQuery(
+	clusterID string,
+	since,
+	until time.Time,
+	limit int,
+	cursor string,
+) (*ClusterCheckinHistoryQuery, error) {
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return nil, ErrImpossibleFilter{
+			vals: []keyAndTime{
+				keyAndTime{key: rest.SinceQueryStringKey, time: since},
+				keyAndTime{key: rest.UntilQueryStringKey, time: until},
+			},
+			reason: fmt.Sprintf("%s needs to be greater than %s", rest.UntilQueryStringKey, rest.SinceQueryStringKey),
+		}
+	}
+	if limit < 1 {
+		return nil, errInvalidLimit
+	}
+	query := ClusterCheckinHistoryQuery{ClusterID: clusterID, Since: since, Until: until, Limit: limit}
+	if cursor != "" {
+		after, err := parseCheckinCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.after = after
+	}
+	return &query, nil
+}
+
+// includes returns true if row matches all of q's requirements except its limit
+func (q ClusterCheckinHistoryQuery) includes(row clustersCheckinsTable) (bool, error) {
+	if row.ClusterID != q.ClusterID {
+		return false, nil
+	}
+	createdAt, err := row.createdAtTime()
+	if err != nil {
+		return false, err
+	}
+	if !q.Since.IsZero() && createdAt.Before(q.Since) {
+		return false, nil
+	}
+	if !q.Until.IsZero() && !createdAt.Before(q.Until) {
+		return false, nil
+	}
+	if q.after != nil && !q.after.after(row, createdAt) {
+		return false, nil
+	}
+	return true, nil
+}
+
+func makeClusterCheckinRecord(row clustersCheckinsTable) (models.ClusterCheckinRecord, error) {
+	createdAt, err := row.createdAtTime()
+	if err != nil {
+		return models.ClusterCheckinRecord{}, err
+	}
+	cluster, err := parseJSONCluster([]byte(row.Data))
+	if err != nil {
+		return models.ClusterCheckinRecord{}, errParsingCluster{origErr: err}
+	}
+	return models.ClusterCheckinRecord{
+		ID:          row.CheckinsID,
+		CheckedInAt: strfmt.DateTime(createdAt),
+		Components:  cluster.Components,
+	}, nil
+}
+
+// makeClusterCheckinHistory converts rows, which must be ordered by (created_at, checkins_id) and
+// may contain up to limit+1 rows, into a page of checkins. If there are more than limit rows, the
+// extra row is dropped and the page gets a cursor to the next page
+func makeClusterCheckinHistory(rows []clustersCheckinsTable, limit int) (models.ClusterCheckinHistory, error) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	ret := models.ClusterCheckinHistory{Data: make([]*models.ClusterCheckinRecord, len(rows))}
+	for i, row := range rows {
+		record, err := makeClusterCheckinRecord(row)
+		if err != nil {
+			return models.ClusterCheckinHistory{}, err
+		}
+		ret.Data[i] = &record
+	}
+	if hasMore && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		id, err := strconv.ParseInt(last.CheckinsID, 10, 64)
+		if err != nil {
+			return models.ClusterCheckinHistory{}, err
+		}
+		createdAt, err := last.createdAtTime()
+		if err != nil {
+			return models.ClusterCheckinHistory{}, err
+		}
+		ret.NextCursor = checkinCursor{CreatedAt: Timestamp{Time: createdAt}.String(), ID: id}.String()
+	}
+	return ret, nil
+}
+
+// GetClusterCheckinHistory returns the page of checkins for query.ClusterID that query selects,
+// ordered from oldest to newest
+func GetClusterCheckinHistory(db *gorm.DB, query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error) {
+	queryDB := checkinsWithData(db).Where(fmt.Sprintf("%s = ?", clustersCheckinsTableClusterIDKey), query.ClusterID)
+	if !query.Since.IsZero() {
+		queryDB = queryDB.Where(
+			fmt.Sprintf("%s >= ?", clustersCheckinsTableClusterCreatedAtKey),
+			Timestamp{Time: query.Since},
+		)
+	}
+	if !query.Until.IsZero() {
+		queryDB = queryDB.Where(
+			fmt.Sprintf("%s < ?", clustersCheckinsTableClusterCreatedAtKey),
+			Timestamp{Time: query.Until},
+		)
+	}
+	if query.after != nil {
+		queryDB = queryDB.Where(
+			fmt.Sprintf(
+				"%s > ? OR (%s = ? AND %s > ?)",
+				clustersCheckinsTableClusterCreatedAtKey,
+				clustersCheckinsTableClusterCreatedAtKey,
+				clustersCheckinsTableIDKey,
+			),
+			query.after.CreatedAt,
+			query.after.CreatedAt,
+			query.after.ID,
+		)
+	}
+	var rows []clustersCheckinsTable
+	findDB := queryDB.
+		Order(fmt.Sprintf("%s, %s", clustersCheckinsTableClusterCreatedAtKey, clustersCheckinsTableIDKey)).
+		Limit(query.Limit + 1).
+		Find(&rows)
+	if findDB.Error != nil {
+		return models.ClusterCheckinHistory{}, findDB.Error
+	}
+	return makeClusterCheckinHistory(rows, query.Limit)
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestNewClusterCheckinHistoryQuery(t *testing.T) {
+	now := time.Now()
+	validCursor := checkinCursor{CreatedAt: Timestamp{Time: now}.String(), ID: 3}.String()
+	type testCase struct {
+		since  time.Time
+		until  time.Time
+		limit  int
+		cursor string
+		valid  bool
+	}
+	testCases := []testCase{
+		{limit: 10, valid: true},
+		{since: now, limit: 10, valid: true},
+		{until: now, limit: 10, valid: true},
+		{since: now.Add(-time.Hour), until: now, limit: 10, valid: true},
+		{since: now, until: now, limit: 10, valid: false},
+		{since: now, until: now.Add(-time.Hour), limit: 10, valid: false},
+		{limit: 0, valid: false},
+		{limit: 10, cursor: validCursor, valid: true},
+		{limit: 10, cursor: "not a cursor", valid: false},
+		{limit: 10, cursor: "bm90IGpzb24=", valid: false},
+	}
+	for i, tc := range testCases {
+		query, err := NewClusterCheckinHistoryQuery(clusterID, tc.since, tc.until, tc.limit, tc.cursor)
+		if tc.valid {
+			assert.NoErr(t, err)
+			assert.True(t, query != nil, "test case %d: nil query returned", i)
+		} else {
+			assert.True(t, err != nil, "test case %d: error not returned when expected", i)
+		}
+	}
+	_, err := NewClusterCheckinHistoryQuery(clusterID, time.Time{}, time.Time{}, 10, "not a cursor")
+	assert.Err(t, ErrInvalidCursor, err)
+}
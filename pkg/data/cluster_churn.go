@@ -0,0 +1,473 @@
+package data
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// DefaultDormancyDays is the number of days a cluster can go without checking in before it's
+	// considered dormant, if no other value is configured
+	DefaultDormancyDays = 30
+
+	clusterEventDormant     = "dormant"
+	clusterEventReactivated = "reactivated"
+
+	// dormancyDetectionBatchSize is the number of clusters that each of DetectDormantClusters'
+	// transactions processes
+	dormancyDetectionBatchSize = 500
+	// dormancyDetectionOverlap is how far before the previous run's now DetectDormantClusters starts
+	// walking checkins. A checkin is recorded with the time that its request arrived, but isn't
+	// visible until its transaction commits, so a run can miss the checkins from just before its
+	// now. Walking a checkin again is harmless, since each cluster's state skips the ones it's seen
+	dormancyDetectionOverlap = time.Minute
+)
+
+// DormancyWindow returns the duration of days days
+func DormancyWindow(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// clusterSeenResponse type that represents a row of the queries for the first or last time each
+// cluster checked in
+type clusterSeenResponse struct {
+	ClusterID string `gorm:"column_name:cluster_id"`
+	FirstSeen string `gorm:"column_name:first_seen"`
+	LastSeen  string `gorm:"column_name:last_seen"`
+}
+
+// getActivityEvents returns all of the activity events that occurred before until, oldest first
+func getActivityEvents(db *gorm.DB, until time.Time) ([]clusterActivityEventsTable, error) {
+	var events []clusterActivityEventsTable
+	findDB := db.
+		Where(fmt.Sprintf("%s < ?", clusterActivityEventsTableOccurredAtKey), Timestamp{Time: until}).
+		Order(fmt.Sprintf("%s, %s", clusterActivityEventsTableOccurredAtKey, clusterActivityEventsTableIDKey)).
+		Find(&events)
+	if findDB.Error != nil {
+		return nil, findDB.Error
+	}
+	return events, nil
+}
+
+// walkCheckinSpans moves the dormancy state in states of each cluster in spans forward through the
+// checkins in them, and returns the activity events for the gaps between those checkins. A cluster
+// that isn't in states yet is added at its first checkin. Compacted checkins are only known by each
+// day's first and last checkin, which is enough, since a cluster can't go dormant within a day.
+// Checkins after now are left for a later run
+func walkCheckinSpans(
+	states map[string]clusterDormancyTable,
+	spans []checkinSpan,
+	now time.Time,
+	window time.Duration,
+) []clusterActivityEventsTable {
+	sorted := make([]checkinSpan, len(spans))
+	copy(sorted, spans)
+	sort.Stable(checkinSpansByFirstSeen(sorted))
+	ret := []clusterActivityEventsTable{}
+	for _, span := range sorted {
+		for _, t := range []time.Time{span.firstSeen, span.lastSeen} {
+			if t.After(now) {
+				break
+			}
+			state, ok := states[span.clusterID]
+			if !ok {
+				states[span.clusterID] = newClusterDormancyTable(span.clusterID, t)
+				continue
+			}
+			ret = append(ret, state.checkIn(t, window)...)
+			states[span.clusterID] = state
+		}
+	}
+	return ret
+}
+
+// checkinSpansByFirstSeen sorts checkin spans by the time of their first checkin
+type checkinSpansByFirstSeen []checkinSpan
+
+func (c checkinSpansByFirstSeen) Len() int           { return len(c) }
+func (c checkinSpansByFirstSeen) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c checkinSpansByFirstSeen) Less(i, j int) bool { return c[i].firstSeen.Before(c[j].firstSeen) }
+
+// getDormancyWatermark returns the time before which DetectDormantClusters has walked every checkin
+func getDormancyWatermark(db *gorm.DB) (time.Time, error) {
+	var rows []dormancyWatermarkTable
+	if err := db.Find(&rows).Error; err != nil {
+		return time.Time{}, err
+	}
+	if len(rows) == 0 {
+		return time.Time{}, nil
+	}
+	return rows[0].ProcessedUntil.Time, nil
+}
+
+// setDormancyWatermark moves the dormancy detection watermark forward to t. It's never moved back,
+// so that a run with an older now doesn't make the next run walk checkins again
+func setDormancyWatermark(db *gorm.DB, t time.Time) error {
+	return db.Exec(
+		fmt.Sprintf(
+			"UPDATE %s SET %s = ? WHERE %s < ?",
+			dormancyWatermarkTableName,
+			dormancyWatermarkTableProcessedUntilKey,
+			dormancyWatermarkTableProcessedUntilKey,
+		),
+		Timestamp{Time: t},
+		Timestamp{Time: t},
+	).Error
+}
+
+// walkDormancyBatch walks the checkins between since and now of the first
+// dormancyDetectionBatchSize clusters after the cluster ID after (or the first clusters, if it's
+// empty) that checked in in that time, records the activity events for the gaps between them, and
+// saves the clusters' dormancy state. Returns the number of events that were recorded, and the ID
+// to pass as after for the next batch, which is empty after the last batch. It must run in a
+// transaction, and takes the dormancy detection advisory lock before it reads anything, so that
+// when replicas walk the same clusters at once, the later ones see the state that the earlier one
+// saved, and skip the checkins that it walked
+func walkDormancyBatch(tx *gorm.DB, since, now time.Time, window time.Duration, after string) (int, string, error) {
+	if err := lockTx(tx, dormancyDetectionLockKey); err != nil {
+		return 0, "", err
+	}
+	idsSQL := checkinSpansSQL + `SELECT DISTINCT cluster_id
+		FROM checkin_spans
+		WHERE last_seen >= ? AND first_seen <= ?`
+	args := []interface{}{Timestamp{Time: since}, Timestamp{Time: now}}
+	if after != "" {
+		idsSQL += " AND cluster_id > ?"
+		args = append(args, after)
+	}
+	idsSQL += " ORDER BY cluster_id LIMIT ?"
+	args = append(args, dormancyDetectionBatchSize)
+	var idRows []clusterSeenResponse
+	if err := tx.Raw(idsSQL, args...).Find(&idRows).Error; err != nil {
+		return 0, "", err
+	}
+	if len(idRows) == 0 {
+		return 0, "", nil
+	}
+	ids := make([]string, len(idRows))
+	for i, row := range idRows {
+		ids[i] = row.ClusterID
+	}
+
+	var spanRows []clusterSeenResponse
+	execDB := tx.Raw(checkinSpansSQL+`SELECT cluster_id, first_seen, last_seen
+		FROM checkin_spans
+		WHERE cluster_id IN (?) AND last_seen >= ? AND first_seen <= ?`,
+		ids,
+		Timestamp{Time: since},
+		Timestamp{Time: now},
+	).Find(&spanRows)
+	if execDB.Error != nil {
+		return 0, "", execDB.Error
+	}
+	spans := make([]checkinSpan, len(spanRows))
+	for i, row := range spanRows {
+		firstSeen, err := time.Parse(StdTimestampFmt, row.FirstSeen)
+		if err != nil {
+			return 0, "", err
+		}
+		lastSeen, err := time.Parse(StdTimestampFmt, row.LastSeen)
+		if err != nil {
+			return 0, "", err
+		}
+		spans[i] = checkinSpan{clusterID: row.ClusterID, firstSeen: firstSeen, lastSeen: lastSeen}
+	}
+
+	var stateRows []clusterDormancyTable
+	findDB := tx.Where(fmt.Sprintf("%s IN (?)", clusterDormancyTableClusterIDKey), ids).Find(&stateRows)
+	if findDB.Error != nil {
+		return 0, "", findDB.Error
+	}
+	saved := make(map[string]clusterDormancyTable, len(stateRows))
+	states := make(map[string]clusterDormancyTable, len(ids))
+	for _, row := range stateRows {
+		saved[row.ClusterID] = row
+		states[row.ClusterID] = row
+	}
+	events := walkCheckinSpans(states, spans, now, window)
+	for _, event := range events {
+		if err := tx.Create(&event).Error; err != nil {
+			return 0, "", err
+		}
+	}
+	for clusterID, state := range states {
+		existing, found := saved[clusterID]
+		var err error
+		switch {
+		case !found:
+			err = tx.Create(&state).Error
+		case existing != state:
+			err = tx.Model(&existing).Updates(map[string]interface{}{
+				clusterDormancyTableLastSeenKey: state.LastSeen,
+				clusterDormancyTableDormantKey:  state.Dormant,
+			}).Error
+		}
+		if err != nil {
+			return 0, "", err
+		}
+	}
+	if len(ids) < dormancyDetectionBatchSize {
+		return len(events), "", nil
+	}
+	return len(events), ids[len(ids)-1], nil
+}
+
+// expireDormancyBatch records that up to dormancyDetectionBatchSize clusters that haven't checked
+// in for window before now went dormant, and returns the number that were. It must run in a
+// transaction, and takes the dormancy detection advisory lock, like walkDormancyBatch
+func expireDormancyBatch(tx *gorm.DB, now time.Time, window time.Duration) (int, error) {
+	if err := lockTx(tx, dormancyDetectionLockKey); err != nil {
+		return 0, err
+	}
+	var rows []clusterDormancyTable
+	findDB := tx.
+		Where(
+			fmt.Sprintf("%s = ? AND %s <= ?", clusterDormancyTableDormantKey, clusterDormancyTableLastSeenKey),
+			false,
+			Timestamp{Time: now.Add(-window)},
+		).
+		Order(clusterDormancyTableClusterIDKey).
+		Limit(dormancyDetectionBatchSize).
+		Find(&rows)
+	if findDB.Error != nil {
+		return 0, findDB.Error
+	}
+	for _, row := range rows {
+		event := row.dormantEvent(window)
+		if err := tx.Create(&event).Error; err != nil {
+			return 0, err
+		}
+		if err := tx.Model(&row).Update(clusterDormancyTableDormantKey, true).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+// inDormancyTx runs fn in a new transaction, which is committed if fn succeeds, and rolled back if
+// it fails
+func inDormancyTx(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	const op = "detect dormant clusters"
+	tx := db.Begin()
+	if tx.Error != nil {
+		return txErr{orig: nil, err: tx.Error, op: op}
+	}
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback().Error; rbErr != nil {
+			return txErr{orig: err, err: rbErr, op: op}
+		}
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return txErr{orig: nil, err: err, op: op}
+	}
+	return nil
+}
+
+// DetectDormantClusters walks the checkins since the last run, and records every time a cluster
+// went window without checking in as it going dormant, and its next checkin as it being
+// reactivated. Then it records that each cluster that hasn't checked in for window before now went
+// dormant. Returns the number of events that were recorded. The first run walks every checkin.
+// Clusters are processed dormancyDetectionBatchSize at a time, each batch in its own transaction,
+// so if an error is returned, the batches before the failed one stay recorded, and the next run
+// picks up where this one stopped. It's safe to run on several replicas at once
+func DetectDormantClusters(db *gorm.DB, now time.Time, window time.Duration) (int, error) {
+	since, err := getDormancyWatermark(db)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	after := ""
+	for {
+		var n int
+		err := inDormancyTx(db, func(tx *gorm.DB) error {
+			var err error
+			n, after, err = walkDormancyBatch(tx, since, now, window, after)
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if after == "" {
+			break
+		}
+	}
+	for {
+		var n int
+		err := inDormancyTx(db, func(tx *gorm.DB) error {
+			var err error
+			n, err = expireDormancyBatch(tx, now, window)
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < dormancyDetectionBatchSize {
+			break
+		}
+	}
+	return total, setDormancyWatermark(db, now.Add(-dormancyDetectionOverlap))
+}
+
+// RunDormancyDetection runs DetectDormantClusters on store once immediately, then once every
+// interval until stop is closed. Errors are logged, and the next run tries again
+func RunDormancyDetection(store ClusterStore, window, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := store.DetectDormantClusters(time.Now(), window)
+		if err != nil {
+			log.Printf("data.DetectDormantClusters error (%s)", err)
+		} else if n > 0 {
+			log.Printf("recorded %d cluster activity events", n)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// ClusterChurnQuery selects the periods to report cluster churn for. Create one with
+// NewClusterChurnQuery
+type ClusterChurnQuery struct {
+	// Since is the time that the first period starts in
+	Since time.Time
+	// Until is the time before which the last period ends
+	Until time.Time
+	// Interval is the size of each period
+	Interval BucketInterval
+}
+
+// NewClusterChurnQuery returns a new ClusterChurnQuery. Returns ErrImpossibleFilter if since isn't
+// before until, an error if interval isn't a valid BucketInterval, or errTooManyBuckets if more
+// than MaxStatsBuckets periods overlap since and until
+func NewClusterChurnQuery(since, until time.Time, interval string) (*ClusterChurnQuery, error) {
+	// the periods are validated the same way as the active clusters buckets
+	query, err := NewActiveClustersQuery(since, until, interval)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterChurnQuery{Since: query.Since, Until: query.Until, Interval: query.Interval}, nil
+}
+
+// clusterHistory is the first checkin time and the activity events of a single cluster
+type clusterHistory struct {
+	firstSeen time.Time
+	// eventTimes and eventTypes are ordered from oldest to newest
+	eventTimes []time.Time
+	eventTypes []string
+}
+
+// activeAt returns true if the cluster had checked in before t and wasn't dormant at t
+func (c clusterHistory) activeAt(t time.Time) bool {
+	if !c.firstSeen.Before(t) {
+		return false
+	}
+	active := true
+	for i, eventTime := range c.eventTimes {
+		if !eventTime.Before(t) {
+			break
+		}
+		active = c.eventTypes[i] != clusterEventDormant
+	}
+	return active
+}
+
+// countEvents returns the number of the cluster's events of the given type in [start, end)
+func (c clusterHistory) countEvents(event string, start, end time.Time) int64 {
+	var ret int64
+	for i, eventTime := range c.eventTimes {
+		if c.eventTypes[i] == event && !eventTime.Before(start) && eventTime.Before(end) {
+			ret++
+		}
+	}
+	return ret
+}
+
+// makeClusterChurn returns the churn in each of query's periods. firstSeen is the first checkin
+// time of each cluster, keyed on cluster ID, and events are the activity events, oldest first
+func makeClusterChurn(
+	query *ClusterChurnQuery,
+	firstSeen map[string]time.Time,
+	events []clusterActivityEventsTable,
+) (models.ClusterChurn, error) {
+	histories := make(map[string]*clusterHistory, len(firstSeen))
+	for clusterID, t := range firstSeen {
+		histories[clusterID] = &clusterHistory{firstSeen: t}
+	}
+	for _, event := range events {
+		history, ok := histories[event.ClusterID]
+		if !ok {
+			continue
+		}
+		occurredAt, err := event.occurredAtTime()
+		if err != nil {
+			return models.ClusterChurn{}, err
+		}
+		history.eventTimes = append(history.eventTimes, occurredAt)
+		history.eventTypes = append(history.eventTypes, event.Event)
+	}
+
+	ret := models.ClusterChurn{Interval: string(query.Interval), Data: []*models.ClusterChurnPeriod{}}
+	for start := query.Interval.truncate(query.Since); start.Before(query.Until); start = query.Interval.next(start) {
+		end := query.Interval.next(start)
+		var newClusters, retained, reactivated, lost int64
+		for _, history := range histories {
+			if !history.firstSeen.Before(start) && history.firstSeen.Before(end) {
+				newClusters++
+			}
+			lostInPeriod := history.countEvents(clusterEventDormant, start, end)
+			if lostInPeriod == 0 && history.activeAt(start) {
+				retained++
+			}
+			lost += lostInPeriod
+			reactivated += history.countEvents(clusterEventReactivated, start, end)
+		}
+		ret.Data = append(ret.Data, &models.ClusterChurnPeriod{
+			Start:       start.Format(statsDateFmt),
+			New:         &newClusters,
+			Retained:    &retained,
+			Reactivated: &reactivated,
+			Lost:        &lost,
+		})
+	}
+	return ret, nil
+}
+
+// GetClusterChurn returns the number of new, retained, reactivated and lost clusters in each of
+// query's periods. Clusters are only lost or reactivated by the events that DetectDormantClusters
+// records, so the report is only as up to date as its last run
+func GetClusterChurn(db *gorm.DB, query *ClusterChurnQuery) (models.ClusterChurn, error) {
+	var rows []clusterSeenResponse
+	execDB := db.Raw(checkinSpansSQL+`SELECT cluster_id, MIN(first_seen) AS first_seen
+		FROM checkin_spans
+		GROUP BY cluster_id
+		HAVING MIN(first_seen) < ?`,
+		Timestamp{Time: query.Until},
+	).Find(&rows)
+	if execDB.Error != nil {
+		return models.ClusterChurn{}, execDB.Error
+	}
+	firstSeen := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		t, err := time.Parse(StdTimestampFmt, row.FirstSeen)
+		if err != nil {
+			return models.ClusterChurn{}, err
+		}
+		firstSeen[row.ClusterID] = t
+	}
+	events, err := getActivityEvents(db, query.Until)
+	if err != nil {
+		return models.ClusterChurn{}, err
+	}
+	return makeClusterChurn(query, firstSeen, events)
+}
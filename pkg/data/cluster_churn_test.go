@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func TestWalkCheckinSpans(t *testing.T) {
+	start := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	window := DormancyWindow(30)
+	day := func(days int) time.Time { return start.AddDate(0, 0, days) }
+	spans := []checkinSpan{
+		{clusterID: "cluster", firstSeen: day(50), lastSeen: day(50)},
+		{clusterID: "cluster", firstSeen: day(0), lastSeen: day(0)},
+		{clusterID: "cluster", firstSeen: day(10), lastSeen: day(10)},
+		// a compacted day
+		{clusterID: "cluster", firstSeen: day(100), lastSeen: day(100).Add(12 * time.Hour)},
+		// after now, so it's left for the next walk
+		{clusterID: "cluster", firstSeen: day(130), lastSeen: day(130)},
+	}
+	states := map[string]clusterDormancyTable{}
+	events := walkCheckinSpans(states, spans, day(120), window)
+	type event struct {
+		name string
+		at   time.Time
+	}
+	actual := []event{}
+	for _, e := range events {
+		occurredAt, err := e.occurredAtTime()
+		assert.NoErr(t, err)
+		actual = append(actual, event{name: e.Event, at: occurredAt})
+	}
+	// both gaps are recorded. the cluster hasn't gone dormant since its last checkin yet, since
+	// walking the checkins doesn't look at the time after them
+	expected := []event{
+		{name: clusterEventDormant, at: day(40)},
+		{name: clusterEventReactivated, at: day(50)},
+		{name: clusterEventDormant, at: day(80)},
+		{name: clusterEventReactivated, at: day(100)},
+	}
+	assert.Equal(t, actual, expected, "events")
+	assert.Equal(t, states["cluster"], clusterDormancyTable{
+		ClusterID: "cluster",
+		LastSeen:  Timestamp{Time: day(100).Add(12 * time.Hour)},
+	}, "cluster state")
+
+	// walking the same checkins again records nothing
+	events = walkCheckinSpans(states, spans[:4], day(120), window)
+	assert.Equal(t, len(events), 0, "number of events")
+
+	// a cluster that was already recorded as dormant is only reactivated
+	states["cluster"] = clusterDormancyTable{ClusterID: "cluster", LastSeen: Timestamp{Time: day(0)}, Dormant: true}
+	events = walkCheckinSpans(states, spans[:1], day(120), window)
+	assert.Equal(t, len(events), 1, "number of events")
+	assert.Equal(t, events[0].Event, clusterEventReactivated, "event")
+	assert.False(t, states["cluster"].Dormant, "cluster is still dormant")
+}
+
+func TestDetectDormantClustersBatches(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	start := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	window := DormancyWindow(30)
+	numClusters := dormancyDetectionBatchSize + 1
+	for i := 0; i < numClusters; i++ {
+		id := fmt.Sprintf("cluster%04d", i)
+		assert.NoErr(t, CheckInCluster(db, id, start, models.Cluster{ID: id}))
+	}
+	n, err := DetectDormantClusters(db, start.AddDate(0, 0, 10), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 0, "number of events")
+	var states []clusterDormancyTable
+	assert.NoErr(t, db.Find(&states).Error)
+	assert.Equal(t, len(states), numClusters, "number of cluster states")
+	watermark, err := getDormancyWatermark(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, watermark, start.AddDate(0, 0, 10).Add(-dormancyDetectionOverlap), "watermark")
+
+	n, err = DetectDormantClusters(db, start.AddDate(0, 0, 40), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, numClusters, "number of events")
+	n, err = DetectDormantClusters(db, start.AddDate(0, 0, 40), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 0, "number of events")
+}
+
+func TestMakeClusterChurn(t *testing.T) {
+	since := time.Date(2016, time.June, 6, 0, 0, 0, 0, time.UTC)
+	query, err := NewClusterChurnQuery(since, since.AddDate(0, 0, 21), "week")
+	assert.NoErr(t, err)
+	firstSeen := map[string]time.Time{
+		"old":      since.AddDate(0, -1, 0),
+		"new":      since.AddDate(0, 0, 8),
+		"returner": since.AddDate(0, -2, 0),
+	}
+	events := []clusterActivityEventsTable{
+		newClusterActivityEventsTable("returner", clusterEventDormant, since.AddDate(0, -1, 0)),
+		newClusterActivityEventsTable("old", clusterEventDormant, since.AddDate(0, 0, 10)),
+		newClusterActivityEventsTable("returner", clusterEventReactivated, since.AddDate(0, 0, 16)),
+		// events for clusters that aren't in firstSeen are ignored
+		newClusterActivityEventsTable("unknown", clusterEventDormant, since),
+	}
+	churn, err := makeClusterChurn(query, firstSeen, events)
+	assert.NoErr(t, err)
+	assert.Equal(t, churn.Interval, "week", "interval")
+	type period struct {
+		start                              string
+		newClusters, retained, react, lost int64
+	}
+	expected := []period{
+		{start: "2016-06-06", newClusters: 0, retained: 1, react: 0, lost: 0},
+		{start: "2016-06-13", newClusters: 1, retained: 0, react: 0, lost: 1},
+		{start: "2016-06-20", newClusters: 0, retained: 1, react: 1, lost: 0},
+	}
+	assert.Equal(t, len(churn.Data), len(expected), "number of periods")
+	for i, p := range churn.Data {
+		actual := period{start: p.Start, newClusters: *p.New, retained: *p.Retained, react: *p.Reactivated, lost: *p.Lost}
+		assert.Equal(t, actual, expected[i], fmt.Sprintf("period %d", i))
+	}
+}
@@ -0,0 +1,174 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/rest"
+	"github.com/jinzhu/gorm"
+)
+
+// MaxCohortPeriods is the maximum number of periods that a cohorts query can report on. Every
+// cohort has a retention entry for each later period, so the response grows with the square of
+// the number of periods, and this is much lower than MaxStatsBuckets
+const MaxCohortPeriods = 104
+
+type errInvalidCohortInterval struct {
+	interval string
+}
+
+func (e errInvalidCohortInterval) Error() string {
+	return fmt.Sprintf(
+		"invalid %s %q. must be one of %s or %s",
+		rest.IntervalQueryStringKey,
+		e.interval,
+		BucketWeek,
+		BucketMonth,
+	)
+}
+
+// ClusterCohortsQuery selects the cohorts to report retention for. Create one with
+// NewClusterCohortsQuery
+type ClusterCohortsQuery struct {
+	// Since is the time that the first cohort's period starts in
+	Since time.Time
+	// Until is the time before which the last cohort's period starts. Only checkins before Until
+	// count towards retention
+	Until time.Time
+	// Interval is the size of each cohort's period. It's either BucketWeek or BucketMonth
+	Interval BucketInterval
+}
+
+// NewClusterCohortsQuery returns a new ClusterCohortsQuery. Returns ErrImpossibleFilter if since
+// isn't before until, an error if interval isn't week or month, or errTooManyBuckets if more than
+// MaxCohortPeriods periods overlap since and until
+func NewClusterCohortsQuery(since, until time.Time, interval string) (*ClusterCohortsQuery, error) {
+	query, err := NewActiveClustersQuery(since, until, interval)
+	if err != nil {
+		return nil, err
+	}
+	if query.Interval == BucketDay {
+		return nil, errInvalidCohortInterval{interval: interval}
+	}
+	if err := checkBuckets(since, until, query.Interval, MaxCohortPeriods); err != nil {
+		return nil, err
+	}
+	return &ClusterCohortsQuery{Since: query.Since, Until: query.Until, Interval: query.Interval}, nil
+}
+
+// cohortStart returns the start of the first cohort's period
+func (q ClusterCohortsQuery) cohortStart() time.Time {
+	return q.Interval.truncate(q.Since)
+}
+
+// makeClusterCohorts returns a cohort for each of query's periods that overlaps
+// [query.Since, query.Until). periods is the start of each of query's periods before query.Until in
+// which a cluster checked in, keyed on cluster ID. It must only have the clusters that first checked
+// in during one of the reported periods, and each cluster's earliest period is its cohort
+func makeClusterCohorts(query *ClusterCohortsQuery, periods map[string][]time.Time) models.ClusterCohorts {
+	// the clusters in each cohort, and the set of periods in which each cluster checked in, keyed on
+	// period start formatted with statsDateFmt
+	cohorts := make(map[string][]string)
+	activePeriods := make(map[string]map[string]bool, len(periods))
+	for clusterID, starts := range periods {
+		if len(starts) == 0 {
+			continue
+		}
+		first := starts[0]
+		active := make(map[string]bool, len(starts))
+		for _, start := range starts {
+			if start.Before(first) {
+				first = start
+			}
+			active[start.Format(statsDateFmt)] = true
+		}
+		cohort := first.Format(statsDateFmt)
+		cohorts[cohort] = append(cohorts[cohort], clusterID)
+		activePeriods[clusterID] = active
+	}
+
+	ret := models.ClusterCohorts{Interval: string(query.Interval), Data: []*models.ClusterCohort{}}
+	for start := query.cohortStart(); start.Before(query.Until); start = query.Interval.next(start) {
+		members := cohorts[start.Format(statsDateFmt)]
+		size := int64(len(members))
+		retention := []float64{}
+		for period := start; period.Before(query.Until); period = query.Interval.next(period) {
+			key := period.Format(statsDateFmt)
+			active := 0
+			for _, clusterID := range members {
+				if activePeriods[clusterID][key] {
+					active++
+				}
+			}
+			fraction := 0.0
+			if size > 0 {
+				fraction = float64(active) / float64(size)
+			}
+			retention = append(retention, fraction)
+		}
+		ret.Data = append(ret.Data, &models.ClusterCohort{
+			Start:     start.Format(statsDateFmt),
+			Clusters:  &size,
+			Retention: retention,
+		})
+	}
+	return ret
+}
+
+// periodStartSQL returns an SQL expression for the start of the period of the given interval that
+// column is in, formatted with statsDateFmt. Postgres' date_trunc starts weeks on Monday, like
+// BucketInterval.truncate. sqlite doesn't have date_trunc, so it's emulated there, so that the
+// cohorts query can be tested against the in-memory DB
+func periodStartSQL(dialect string, interval BucketInterval, column string) string {
+	if dialect == postgresDialect {
+		return fmt.Sprintf("to_char(date_trunc('%s', %s), 'YYYY-MM-DD')", interval, column)
+	}
+	switch interval {
+	case BucketWeek:
+		// 'weekday 0' moves forward to the next Sunday, unless the date is already a Sunday
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	case BucketMonth:
+		return fmt.Sprintf("date(%s, 'start of month')", column)
+	default:
+		return fmt.Sprintf("date(%s)", column)
+	}
+}
+
+// clusterPeriodResponse type that represents a row of the cluster cohorts query
+type clusterPeriodResponse struct {
+	ClusterID string `gorm:"column_name:cluster_id"`
+	Period    string `gorm:"column_name:period"`
+}
+
+// GetClusterCohorts groups the clusters that first checked in during each of query's periods into
+// cohorts, and returns the fraction of each cohort that checked in during each period from then on.
+// The database returns each distinct (cluster, period) pair, rather than every checkin
+func GetClusterCohorts(db *gorm.DB, query *ClusterCohortsQuery) (models.ClusterCohorts, error) {
+	var rows []clusterPeriodResponse
+	execDB := db.Raw(checkinSpansSQL+`SELECT DISTINCT cluster_id, `+
+		periodStartSQL(db.Dialect().GetName(), query.Interval, "first_seen")+` AS period
+		FROM checkin_spans
+		WHERE first_seen < ? AND cluster_id IN (
+			SELECT cluster_id
+			FROM checkin_spans
+			GROUP BY cluster_id
+			HAVING MIN(first_seen) >= ? AND MIN(first_seen) < ?
+		)`,
+		Timestamp{Time: query.Until},
+		Timestamp{Time: query.cohortStart()},
+		Timestamp{Time: query.Until},
+	).Find(&rows)
+	if execDB.Error != nil {
+		return models.ClusterCohorts{}, execDB.Error
+	}
+	periods := make(map[string][]time.Time)
+	for _, row := range rows {
+		start, err := time.Parse(statsDateFmt, row.Period)
+		if err != nil {
+			return models.ClusterCohorts{}, err
+		}
+		periods[row.ClusterID] = append(periods[row.ClusterID], start)
+	}
+	return makeClusterCohorts(query, periods), nil
+}
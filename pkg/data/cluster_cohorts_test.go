@@ -0,0 +1,54 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestNewClusterCohortsQuery(t *testing.T) {
+	since := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	query, err := NewClusterCohortsQuery(since, since.AddDate(0, 1, 0), "week")
+	assert.NoErr(t, err)
+	assert.Equal(t, query.Interval, BucketWeek, "interval")
+	_, err = NewClusterCohortsQuery(since, since.AddDate(0, 1, 0), "day")
+	assert.Err(t, errInvalidCohortInterval{interval: "day"}, err)
+	_, err = NewClusterCohortsQuery(since, since, "week")
+	assert.True(t, err != nil, "expected an error when since isn't before until")
+	_, err = NewClusterCohortsQuery(since, since.AddDate(0, MaxCohortPeriods, 0), "month")
+	assert.NoErr(t, err)
+	_, err = NewClusterCohortsQuery(since, since.AddDate(0, MaxCohortPeriods, 1), "month")
+	assert.Err(t, errTooManyBuckets{
+		since:    since,
+		until:    since.AddDate(0, MaxCohortPeriods, 1),
+		interval: BucketMonth,
+		max:      MaxCohortPeriods,
+	}, err)
+}
+
+func TestMakeClusterCohorts(t *testing.T) {
+	since := time.Date(2016, time.June, 15, 0, 0, 0, 0, time.UTC)
+	query, err := NewClusterCohortsQuery(since, since.AddDate(0, 2, 0), "month")
+	assert.NoErr(t, err)
+	june := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	periods := map[string][]time.Time{
+		// periods don't have to be in order
+		"cluster1": {june.AddDate(0, 1, 0), june},
+		"cluster2": {june, june.AddDate(0, 2, 0)},
+		"cluster3": {june.AddDate(0, 1, 0)},
+	}
+	cohorts := makeClusterCohorts(query, periods)
+	assert.Equal(t, cohorts.Interval, "month", "interval")
+	starts := []string{}
+	sizes := []int64{}
+	retention := [][]float64{}
+	for _, cohort := range cohorts.Data {
+		starts = append(starts, cohort.Start)
+		sizes = append(sizes, *cohort.Clusters)
+		retention = append(retention, cohort.Retention)
+	}
+	assert.Equal(t, starts, []string{"2016-06-01", "2016-07-01", "2016-08-01"}, "cohort starts")
+	assert.Equal(t, sizes, []int64{2, 1, 0}, "cohort sizes")
+	assert.Equal(t, retention, [][]float64{{1, 0.5, 0.5}, {1, 0}, {0}}, "cohort retention")
+}
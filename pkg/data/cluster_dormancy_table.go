@@ -0,0 +1,99 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	clusterDormancyTableName         = "cluster_dormancy"
+	clusterDormancyTableClusterIDKey = "cluster_id"
+	clusterDormancyTableLastSeenKey  = "last_seen"
+	clusterDormancyTableDormantKey   = "dormant"
+
+	dormancyWatermarkTableName              = "dormancy_watermark"
+	dormancyWatermarkTableProcessedUntilKey = "processed_until"
+)
+
+// clusterDormancyTable type that expresses the `cluster_dormancy` postgres table schema. Each row
+// is how far dormancy detection has walked a single cluster's checkins
+type clusterDormancyTable struct {
+	ClusterID string    `gorm:"primary_key;type:uuid;column:cluster_id"`
+	LastSeen  Timestamp `gorm:"type:timestamp;column:last_seen"`
+	Dormant   bool      `gorm:"column:dormant"`
+}
+
+func newClusterDormancyTable(clusterID string, firstSeen time.Time) clusterDormancyTable {
+	return clusterDormancyTable{ClusterID: clusterID, LastSeen: Timestamp{Time: firstSeen}}
+}
+
+func (c clusterDormancyTable) TableName() string {
+	return clusterDormancyTableName
+}
+
+// checkIn moves c forward to a checkin at t, and returns the activity events that the gap before it
+// implies. A cluster goes dormant window after a checkin, and is reactivated by its next checkin
+// after that. Checkins must be walked in order, and ones at or before c.LastSeen were already
+// walked, so they're ignored
+func (c *clusterDormancyTable) checkIn(t time.Time, window time.Duration) []clusterActivityEventsTable {
+	ret := []clusterActivityEventsTable{}
+	if !t.After(c.LastSeen.Time) {
+		return ret
+	}
+	if !c.Dormant && t.After(c.LastSeen.Time.Add(window)) {
+		ret = append(ret, c.dormantEvent(window))
+		c.Dormant = true
+	}
+	if c.Dormant {
+		ret = append(ret, newClusterActivityEventsTable(c.ClusterID, clusterEventReactivated, t))
+		c.Dormant = false
+	}
+	c.LastSeen = Timestamp{Time: t}
+	return ret
+}
+
+// dormantEvent returns the event for c going dormant after its last checkin
+func (c clusterDormancyTable) dormantEvent(window time.Duration) clusterActivityEventsTable {
+	return newClusterActivityEventsTable(c.ClusterID, clusterEventDormant, c.LastSeen.Time.Add(window))
+}
+
+// dormancyWatermarkTable type that expresses the `dormancy_watermark` postgres table schema. It has
+// a single row, with the time before which dormancy detection has walked every checkin
+type dormancyWatermarkTable struct {
+	ProcessedUntil Timestamp `gorm:"type:timestamp;column:processed_until"`
+}
+
+func (d dormancyWatermarkTable) TableName() string {
+	return dormancyWatermarkTableName
+}
+
+func createClusterDormancyTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s uuid PRIMARY KEY, %s timestamp, %s boolean )",
+		clusterDormancyTableName,
+		clusterDormancyTableClusterIDKey,
+		clusterDormancyTableLastSeenKey,
+		clusterDormancyTableDormantKey,
+	))
+}
+
+// createDormancyWatermarkTable creates the dormancy_watermark table, with a watermark before every
+// checkin, so that the first detection run walks all of them
+func createDormancyWatermarkTable(db execer) error {
+	if _, err := db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s timestamp )",
+		dormancyWatermarkTableName,
+		dormancyWatermarkTableProcessedUntilKey,
+	)); err != nil {
+		return err
+	}
+	// the placeholder syntax depends on the driver, so the (constant) time is inlined
+	_, err := db.Exec(fmt.Sprintf(
+		"INSERT INTO %s ( %s ) VALUES ( '%s' )",
+		dormancyWatermarkTableName,
+		dormancyWatermarkTableProcessedUntilKey,
+		Timestamp{}.String(),
+	))
+	return err
+}
@@ -171,7 +171,7 @@ func TestClusterFilterByAge(t *testing.T) {
 			}
 
 			// filter the cluster & test results
-			filteredClusters, filterErr := FilterClustersByAge(db, &fca.filter)
+			filteredClusters, _, filterErr := FilterClustersByAge(db, &fca.filter, nil)
 			if filterErr != nil {
 				t.Errorf("error filtering for case %d (%s)", i, filterErr)
 				return
@@ -189,7 +189,7 @@ func TestClusterFilterByAge(t *testing.T) {
 				t.Errorf("error creating new DB in case %d (%s)", i, err)
 				return
 			}
-			filteredClusters, filterErr = FilterClustersByAge(db, &fca.filter)
+			filteredClusters, _, filterErr = FilterClustersByAge(db, &fca.filter, nil)
 			if filterErr != nil {
 				t.Errorf("error filtering for case %d (%s)", i, filterErr)
 				return
@@ -0,0 +1,153 @@
+package data
+
+import (
+	"fmt"
+	"time"
+)
+
+// clusterCursor is the position of a cluster in the (first_seen, cluster_id) ordering that the
+// cluster list queries use. It's encoded into the opaque cursor strings that clients see
+type clusterCursor struct {
+	FirstSeen string `json:"t"`
+	ClusterID string `json:"id"`
+}
+
+func (c clusterCursor) String() string {
+	return encodeCursor(c)
+}
+
+// clusterPosition is the decoded form of a clusterCursor
+type clusterPosition struct {
+	firstSeen time.Time
+	clusterID string
+}
+
+func (p clusterPosition) cursor() clusterCursor {
+	return clusterCursor{FirstSeen: Timestamp{Time: p.firstSeen}.String(), ClusterID: p.clusterID}
+}
+
+// before returns true if p comes before other in the (first_seen, cluster_id) ordering
+func (p clusterPosition) before(other clusterPosition) bool {
+	if p.firstSeen.Equal(other.firstSeen) {
+		return p.clusterID < other.clusterID
+	}
+	return p.firstSeen.Before(other.firstSeen)
+}
+
+func parseClusterCursor(str string) (*clusterPosition, error) {
+	cursor := clusterCursor{}
+	if err := decodeCursor(str, &cursor); err != nil {
+		return nil, err
+	}
+	firstSeen, err := time.Parse(StdTimestampFmt, cursor.FirstSeen)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &clusterPosition{firstSeen: firstSeen, clusterID: cursor.ClusterID}, nil
+}
+
+// ClusterPage selects a single page of the results of FilterClustersByAge, FilterClusterCheckins
+// or FilterPersistentClusters. Create one with NewClusterPage. A nil *ClusterPage selects all
+// results.
+//
+// Each of those queries orders its results by (first_seen, cluster_id), so a page starts
+// immediately after the last cluster of the previous page even if new clusters check in between
+// requests
+type ClusterPage struct {
+	// Limit is the maximum number of clusters in the page, or 0 for every cluster after the cursor
+	Limit int
+	// after is the position of the last cluster of the previous page, or nil for the first page
+	after *clusterPosition
+}
+
+// NewClusterPage returns a new ClusterPage. cursor is the next cursor returned with the previous
+// page, or the empty string for the first page. Returns ErrInvalidCursor if cursor can't be decoded
+func NewClusterPage(limit int, cursor string) (*ClusterPage, error) {
+	if limit < 1 {
+		return nil, errInvalidLimit
+	}
+	page := ClusterPage{Limit: limit}
+	if cursor != "" {
+		after, err := parseClusterCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		page.after = after
+	}
+	return &page, nil
+}
+
+// havingClause returns a HAVING condition, starting with AND, that selects the rows after p's
+// cursor, and its arguments. firstSeenExpr and clusterIDExpr are the SQL expressions for the first
+// seen time and cluster ID, and desc is true if the query is in descending order
+func (p *ClusterPage) havingClause(firstSeenExpr, clusterIDExpr string, desc bool) (string, []interface{}) {
+	if p == nil || p.after == nil {
+		return "", nil
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	clause := fmt.Sprintf(
+		" AND (%s %s ? OR (%s = ? AND %s %s ?))",
+		firstSeenExpr,
+		op,
+		firstSeenExpr,
+		clusterIDExpr,
+		op,
+	)
+	firstSeen := Timestamp{Time: p.after.firstSeen}
+	return clause, []interface{}{firstSeen, firstSeen, p.after.clusterID}
+}
+
+// limitClause returns a LIMIT clause and its arguments. The limit is one more than p.Limit so
+// that the caller can tell whether there's another page
+func (p *ClusterPage) limitClause() (string, []interface{}) {
+	if p == nil || p.Limit == 0 {
+		return "", nil
+	}
+	return " LIMIT ?", []interface{}{p.Limit + 1}
+}
+
+// withoutLimit returns a page that selects every result after p's cursor
+func (p *ClusterPage) withoutLimit() *ClusterPage {
+	if p == nil || p.after == nil {
+		return nil
+	}
+	return &ClusterPage{after: p.after}
+}
+
+// includes returns true if pos comes after p's cursor in the query's ordering
+func (p *ClusterPage) includes(pos clusterPosition, desc bool) bool {
+	if p == nil || p.after == nil {
+		return true
+	}
+	if desc {
+		return pos.before(*p.after)
+	}
+	return p.after.before(pos)
+}
+
+// trim returns the number of the n fetched results that belong in the page, and the cursor for
+// the next page or the empty string if there isn't one. position returns the position of the ith
+// result
+func (p *ClusterPage) trim(n int, position func(i int) (clusterPosition, error)) (int, string, error) {
+	if p == nil || p.Limit == 0 || n <= p.Limit {
+		return n, "", nil
+	}
+	last, err := position(p.Limit - 1)
+	if err != nil {
+		return 0, "", err
+	}
+	return p.Limit, last.cursor().String(), nil
+}
+
+// positionFromFirstSeen returns the position of a cluster from the first_seen string that a
+// query returned
+func positionFromFirstSeen(firstSeen, clusterID string) (clusterPosition, error) {
+	t, err := time.Parse(StdTimestampFmt, firstSeen)
+	if err != nil {
+		return clusterPosition{}, err
+	}
+	return clusterPosition{firstSeen: t, clusterID: clusterID}, nil
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestNewClusterPage(t *testing.T) {
+	validCursor := clusterCursor{FirstSeen: Timestamp{Time: time.Now()}.String(), ClusterID: clusterID}.String()
+	badTimeCursor := clusterCursor{FirstSeen: "yesterday", ClusterID: clusterID}.String()
+	type testCase struct {
+		limit  int
+		cursor string
+		valid  bool
+	}
+	testCases := []testCase{
+		{limit: 1, valid: true},
+		{limit: 10, cursor: validCursor, valid: true},
+		{limit: 0, valid: false},
+		{limit: -1, cursor: validCursor, valid: false},
+		{limit: 10, cursor: "not a cursor", valid: false},
+		{limit: 10, cursor: badTimeCursor, valid: false},
+	}
+	for i, tc := range testCases {
+		page, err := NewClusterPage(tc.limit, tc.cursor)
+		if tc.valid {
+			assert.NoErr(t, err)
+			assert.True(t, page != nil, "test case %d: nil page returned", i)
+		} else {
+			assert.True(t, err != nil, "test case %d: error not returned when expected", i)
+		}
+	}
+	_, err := NewClusterPage(10, badTimeCursor)
+	assert.Err(t, ErrInvalidCursor, err)
+}
+
+func TestClusterPageIncludes(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	page, err := NewClusterPage(10, clusterPosition{firstSeen: now, clusterID: "b"}.cursor().String())
+	assert.NoErr(t, err)
+	type testCase struct {
+		pos  clusterPosition
+		asc  bool
+		desc bool
+	}
+	testCases := []testCase{
+		{pos: clusterPosition{firstSeen: now.Add(-time.Second), clusterID: "c"}, asc: false, desc: true},
+		{pos: clusterPosition{firstSeen: now, clusterID: "a"}, asc: false, desc: true},
+		{pos: clusterPosition{firstSeen: now, clusterID: "b"}, asc: false, desc: false},
+		{pos: clusterPosition{firstSeen: now, clusterID: "c"}, asc: true, desc: false},
+		{pos: clusterPosition{firstSeen: now.Add(time.Second), clusterID: "a"}, asc: true, desc: false},
+	}
+	for i, tc := range testCases {
+		assert.Equal(t, page.includes(tc.pos, false), tc.asc, fmt.Sprintf("ascending includes for test case %d", i))
+		assert.Equal(t, page.includes(tc.pos, true), tc.desc, fmt.Sprintf("descending includes for test case %d", i))
+	}
+	var nilPage *ClusterPage
+	assert.True(t, nilPage.includes(testCases[0].pos, false), "nil page didn't include a position")
+}
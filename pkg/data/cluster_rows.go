@@ -0,0 +1,144 @@
+package data
+
+import (
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ClusterRows iterates over the results of ExportClustersByAge, ExportClusterCheckins or
+// ExportPersistentClusters. The gorm implementation reads each row from the database as Next is
+// called, so the results are never all in memory at once. Callers must call Close when they're done
+type ClusterRows interface {
+	// Next advances to the next row. It returns false when there are no more rows, or if reading the
+	// next row failed. Check Err to tell the two apart
+	Next() bool
+	// Row returns the current row. It's a *models.Cluster for ExportClustersByAge, and a
+	// *models.ClusterCheckin for the others
+	Row() (interface{}, error)
+	// Err returns the error, if any, that stopped Next
+	Err() error
+	// Close releases the rows' database connection
+	Close() error
+}
+
+// sqlClusterRows is a ClusterRows that scans each row of rows into a new value from newRow, and
+// converts it to its model with makeRow
+type sqlClusterRows struct {
+	db      *gorm.DB
+	rows    *sql.Rows
+	newRow  func() interface{}
+	makeRow func(row interface{}) (interface{}, error)
+}
+
+// newSQLClusterRows runs query and returns a ClusterRows over its results
+func newSQLClusterRows(
+	query *gorm.DB,
+	newRow func() interface{},
+	makeRow func(row interface{}) (interface{}, error),
+) (ClusterRows, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlClusterRows{db: query, rows: rows, newRow: newRow, makeRow: makeRow}, nil
+}
+
+func (s *sqlClusterRows) Next() bool {
+	return s.rows.Next()
+}
+
+func (s *sqlClusterRows) Row() (interface{}, error) {
+	row := s.newRow()
+	if err := s.db.ScanRows(s.rows, row); err != nil {
+		return nil, err
+	}
+	return s.makeRow(row)
+}
+
+func (s *sqlClusterRows) Err() error {
+	return s.rows.Err()
+}
+
+func (s *sqlClusterRows) Close() error {
+	return s.rows.Close()
+}
+
+// newClusterAgeRows returns a ClusterRows over the results of a clustersByAgeQuery
+func newClusterAgeRows(query *gorm.DB) (ClusterRows, error) {
+	return newSQLClusterRows(
+		query,
+		func() interface{} { return &clustersAgeFilterResponse{} },
+		func(row interface{}) (interface{}, error) {
+			r := row.(*clustersAgeFilterResponse)
+			clusters, err := makeClusters([]clustersTable{{ClusterID: r.ClusterID, Data: r.Data}})
+			if err != nil {
+				return nil, err
+			}
+			return clusters[0], nil
+		},
+	)
+}
+
+// newClusterCheckinRows returns a ClusterRows over the results of a clusterCheckinsQuery or a
+// persistentClustersQuery
+func newClusterCheckinRows(query *gorm.DB) (ClusterRows, error) {
+	return newSQLClusterRows(
+		query,
+		func() interface{} { return &clustersCheckinsFilterResponse{} },
+		func(row interface{}) (interface{}, error) {
+			checkins, err := makeClusterCheckins([]clustersCheckinsFilterResponse{*row.(*clustersCheckinsFilterResponse)})
+			if err != nil {
+				return nil, err
+			}
+			return checkins[0], nil
+		},
+	)
+}
+
+// sliceClusterRows is a ClusterRows over rows that are already in memory
+type sliceClusterRows struct {
+	rows []interface{}
+	cur  int
+}
+
+// NewSliceClusterRows returns a ClusterRows over rows, which are already in memory
+func NewSliceClusterRows(rows []interface{}) ClusterRows {
+	return &sliceClusterRows{rows: rows, cur: -1}
+}
+
+func (s *sliceClusterRows) Next() bool {
+	if s.cur+1 >= len(s.rows) {
+		return false
+	}
+	s.cur++
+	return true
+}
+
+func (s *sliceClusterRows) Row() (interface{}, error) {
+	return s.rows[s.cur], nil
+}
+
+func (s *sliceClusterRows) Err() error {
+	return nil
+}
+
+func (s *sliceClusterRows) Close() error {
+	return nil
+}
+
+// requestClusterRows is a ClusterRows that wraps the errors of another ClusterRows in a
+// RequestError, like requestStore does
+type requestClusterRows struct {
+	ClusterRows
+	wrap func(error) error
+}
+
+func (r requestClusterRows) Row() (interface{}, error) {
+	row, err := r.ClusterRows.Row()
+	return row, r.wrap(err)
+}
+
+func (r requestClusterRows) Err() error {
+	return r.wrap(r.ClusterRows.Err())
+}
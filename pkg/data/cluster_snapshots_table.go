@@ -0,0 +1,132 @@
+package data
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	clusterSnapshotsTableName    = "cluster_snapshots"
+	clusterSnapshotsTableHashKey = "hash"
+	clusterSnapshotsTableDataKey = "data"
+)
+
+// clusterSnapshotsTable type that expresses the `cluster_snapshots` postgres table schema. Each
+// row is a distinct cluster JSON payload that one or more checkins reported, keyed on its hash
+type clusterSnapshotsTable struct {
+	Hash string `gorm:"primary_key;type:char(64);column_name:hash"`
+	Data string `gorm:"type:json;column_name:data"`
+}
+
+func (c clusterSnapshotsTable) TableName() string {
+	return clusterSnapshotsTableName
+}
+
+// hashClusterSnapshot returns the hex encoded SHA-256 hash of a cluster JSON payload
+func hashClusterSnapshot(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// saveClusterSnapshot stores data as the snapshot with the given hash, unless that snapshot
+// already exists. The payload includes the cluster ID, so only checkins from the same cluster
+// share a snapshot. Concurrent identical checkins can both find that the snapshot doesn't exist,
+// and the one that inserts it second leaves it as it is
+func saveClusterSnapshot(db *gorm.DB, hash string, data []byte) error {
+	count := 0
+	countDB := db.Model(&clusterSnapshotsTable{}).
+		Where(fmt.Sprintf("%s = ?", clusterSnapshotsTableHashKey), hash).
+		Count(&count)
+	if countDB.Error != nil {
+		return countDB.Error
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err := createUnlessExists(db, &clusterSnapshotsTable{Hash: hash, Data: string(data)})
+	return err
+}
+
+// deleteOrphanedSnapshots deletes the snapshots that rows referenced, and that no checkin in the
+// clusters_checkins table references any more. It must run in the transaction that deleted rows.
+// It takes the cluster snapshots lock first, so that it waits for checkins that are about to
+// reference one of the snapshots to commit, and checkins that start after it wait for it to commit
+// and save their snapshot again
+func deleteOrphanedSnapshots(tx *gorm.DB, rows []clustersCheckinsTable) error {
+	seen := make(map[string]bool)
+	hashes := []string{}
+	for _, row := range rows {
+		if row.SnapshotHash == "" || seen[row.SnapshotHash] {
+			continue
+		}
+		seen[row.SnapshotHash] = true
+		hashes = append(hashes, row.SnapshotHash)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	if err := lockTx(tx, clusterSnapshotsLockKey); err != nil {
+		return err
+	}
+	return tx.Exec(
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE %s IN (?) AND NOT EXISTS (SELECT 1 FROM %s WHERE %s.%s = %s.%s)",
+			clusterSnapshotsTableName,
+			clusterSnapshotsTableHashKey,
+			clustersCheckinsTableName,
+			clustersCheckinsTableName,
+			clustersCheckinsTableSnapshotHashKey,
+			clusterSnapshotsTableName,
+			clusterSnapshotsTableHashKey,
+		),
+		hashes,
+	).Error
+}
+
+func createClusterSnapshotsTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s char(64) PRIMARY KEY, %s json )",
+		clusterSnapshotsTableName,
+		clusterSnapshotsTableHashKey,
+		clusterSnapshotsTableDataKey,
+	))
+}
+
+func addClustersCheckinsSnapshotHashColumn(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"ALTER TABLE %s ADD COLUMN %s char(64)",
+		clustersCheckinsTableName,
+		clustersCheckinsTableSnapshotHashKey,
+	))
+}
+
+// dropClustersCheckinsSnapshotHashColumn copies each checkin's snapshot back into its data column,
+// then drops the snapshot_hash column. It only works on Postgres
+func dropClustersCheckinsSnapshotHashColumn(db execer) error {
+	if _, err := db.Exec(fmt.Sprintf(
+		"UPDATE %s SET %s = %s.%s FROM %s WHERE %s.%s = %s.%s AND %s.%s IS NULL",
+		clustersCheckinsTableName,
+		clustersCheckinsTableDataKey,
+		clusterSnapshotsTableName,
+		clusterSnapshotsTableDataKey,
+		clusterSnapshotsTableName,
+		clustersCheckinsTableName,
+		clustersCheckinsTableSnapshotHashKey,
+		clusterSnapshotsTableName,
+		clusterSnapshotsTableHashKey,
+		clustersCheckinsTableName,
+		clustersCheckinsTableDataKey,
+	)); err != nil {
+		return err
+	}
+	_, err := db.Exec(fmt.Sprintf(
+		"ALTER TABLE %s DROP COLUMN %s",
+		clustersCheckinsTableName,
+		clustersCheckinsTableSnapshotHashKey,
+	))
+	return err
+}
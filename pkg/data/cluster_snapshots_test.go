@@ -0,0 +1,78 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestCheckInClusterSharesSnapshots(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	start := time.Now().UTC().Truncate(time.Second)
+	cluster := testCluster()
+	// a checkin from before snapshots existed, with its JSON inline
+	js, err := json.Marshal(cluster)
+	assert.NoErr(t, err)
+	legacy := newClustersCheckinsTable("", clusterID, start, js)
+	assert.NoErr(t, db.Create(&legacy).Error)
+
+	for i := 1; i <= 3; i++ {
+		assert.NoErr(t, CheckInCluster(db, clusterID, start.Add(time.Duration(i)*time.Hour), cluster))
+	}
+	count := 0
+	assert.NoErr(t, db.Model(&clusterSnapshotsTable{}).Count(&count).Error)
+	assert.Equal(t, count, 1, "number of snapshots")
+
+	cluster.Components[0].Version.Version = "3.0.0"
+	assert.NoErr(t, CheckInCluster(db, clusterID, start.Add(4*time.Hour), cluster))
+	assert.NoErr(t, db.Model(&clusterSnapshotsTable{}).Count(&count).Error)
+	assert.Equal(t, count, 2, "number of snapshots")
+
+	// every checkin is still recorded, and reports the components it was sent with
+	query, err := NewClusterCheckinHistoryQuery(clusterID, time.Time{}, time.Time{}, 100, "")
+	assert.NoErr(t, err)
+	history, err := GetClusterCheckinHistory(db, query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(history.Data), 5, "number of checkins")
+	for i, checkin := range history.Data {
+		assert.Equal(t, time.Time(checkin.CheckedInAt).Unix(), start.Add(time.Duration(i)*time.Hour).Unix(), "checkin time")
+		expected := testComponentVersion().Version.Version
+		if i == 4 {
+			expected = "3.0.0"
+		}
+		assert.Equal(t, checkin.Components[0].Version.Version, expected, "checkin version")
+	}
+
+	first, err := GetClusterCheckinByID(db, clusterID, history.Data[0].ID)
+	assert.NoErr(t, err)
+	assert.Equal(t, first, *history.Data[0], "legacy checkin")
+	last, err := GetClusterCheckinAt(db, clusterID, start.Add(10*time.Hour))
+	assert.NoErr(t, err)
+	assert.Equal(t, last, *history.Data[4], "latest checkin")
+}
+
+func TestCreateUnlessExists(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	tx := db.Begin()
+	assert.NoErr(t, tx.Error)
+	// the second insert is what a checkin that lost a race with an identical one does
+	for i, expected := range []bool{true, false} {
+		created, err := createUnlessExists(tx, &clusterSnapshotsTable{Hash: "testhash", Data: "{}"})
+		assert.NoErr(t, err)
+		assert.Equal(t, created, expected, fmt.Sprintf("insert %d created the snapshot", i))
+	}
+	// the transaction can still be used and committed
+	count := 0
+	assert.NoErr(t, tx.Model(&clusterSnapshotsTable{}).Count(&count).Error)
+	assert.Equal(t, count, 1, "number of snapshots")
+	assert.NoErr(t, tx.Commit().Error)
+
+	created, err := createUnlessExists(db, &clusterSnapshotsTable{Hash: "testhash", Data: "{}"})
+	assert.NoErr(t, err)
+	assert.False(t, created, "snapshot was created outside of a transaction")
+}
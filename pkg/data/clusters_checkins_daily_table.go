@@ -0,0 +1,67 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	clustersCheckinsDailyTableName         = "clusters_checkins_daily"
+	clustersCheckinsDailyTableClusterIDKey = "cluster_id"
+	clustersCheckinsDailyTableDayKey       = "day"
+	clustersCheckinsDailyTableFirstSeenKey = "first_seen"
+	clustersCheckinsDailyTableLastSeenKey  = "last_seen"
+	clustersCheckinsDailyTableCheckinsKey  = "checkins"
+	clustersCheckinsDailyTableDataKey      = "data"
+)
+
+// clustersCheckinsDailyTable type that expresses the `clusters_checkins_daily` postgres table
+// schema. Each row summarizes all of a single cluster's compacted checkins on a single UTC day.
+// Data holds a models.Cluster with the cluster's ID and the distinct component versions that it
+// reported that day
+type clustersCheckinsDailyTable struct {
+	ClusterID string `gorm:"primary_key;type:uuid;column_name:cluster_id"`
+	Day       string `gorm:"primary_key;type:timestamp;column_name:day"`
+	FirstSeen string `gorm:"type:timestamp;column_name:first_seen"`
+	LastSeen  string `gorm:"type:timestamp;column_name:last_seen"`
+	Checkins  int64  `gorm:"column_name:checkins"`
+	Data      string `gorm:"type:json;column_name:data"`
+}
+
+// BeforeSave is the gorm callback for saving a new daily checkin summary
+func (c clustersCheckinsDailyTable) BeforeSave() error {
+	for _, str := range []string{c.Day, c.FirstSeen, c.LastSeen} {
+		if _, err := newTimestampFromStr(str); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c clustersCheckinsDailyTable) firstSeenTime() (time.Time, error) {
+	return time.Parse(StdTimestampFmt, c.FirstSeen)
+}
+
+func (c clustersCheckinsDailyTable) lastSeenTime() (time.Time, error) {
+	return time.Parse(StdTimestampFmt, c.LastSeen)
+}
+
+func (c clustersCheckinsDailyTable) TableName() string {
+	return clustersCheckinsDailyTableName
+}
+
+func createClustersCheckinsDailyTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s uuid, %s timestamp, %s timestamp, %s timestamp, %s integer, %s json, PRIMARY KEY (%s, %s) )",
+		clustersCheckinsDailyTableName,
+		clustersCheckinsDailyTableClusterIDKey,
+		clustersCheckinsDailyTableDayKey,
+		clustersCheckinsDailyTableFirstSeenKey,
+		clustersCheckinsDailyTableLastSeenKey,
+		clustersCheckinsDailyTableCheckinsKey,
+		clustersCheckinsDailyTableDataKey,
+		clustersCheckinsDailyTableClusterIDKey,
+		clustersCheckinsDailyTableDayKey,
+	))
+}
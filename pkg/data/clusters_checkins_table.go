@@ -3,8 +3,9 @@ package data
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"time"
+
+	"github.com/jinzhu/gorm"
 )
 
 const (
@@ -13,14 +14,26 @@ const (
 	clustersCheckinsTableClusterIDKey        = "cluster_id"
 	clustersCheckinsTableClusterCreatedAtKey = "created_at"
 	clustersCheckinsTableDataKey             = "data"
+	clustersCheckinsTableSnapshotHashKey     = "snapshot_hash"
 )
 
-// ClustersCheckinsTable type that expresses the `clusters_checkins` postgres table schema
+// checkinsWithDataSQL selects every column of clusters_checkins, with each checkin's cluster JSON
+// as data. Checkins recorded before snapshots existed store their JSON inline in the data column,
+// and newer ones leave it null and reference a shared row in cluster_snapshots instead
+const checkinsWithDataSQL = `SELECT clusters_checkins.checkins_id, clusters_checkins.cluster_id,
+		clusters_checkins.created_at, clusters_checkins.snapshot_hash,
+		COALESCE(clusters_checkins.data, cluster_snapshots.data) AS data
+		FROM clusters_checkins
+		LEFT JOIN cluster_snapshots ON cluster_snapshots.hash = clusters_checkins.snapshot_hash`
+
+// ClustersCheckinsTable type that expresses the `clusters_checkins` postgres table schema. Data is
+// empty for checkins that reference a snapshot, unless the row was read with checkinsWithData
 type clustersCheckinsTable struct {
-	CheckinsID string `gorm:"primary_key;type:bigserial;column_name:checkins_id"`
-	ClusterID  string `gorm:"type:uuid;column_name:cluster_id;index"`
-	CreatedAt  string `gorm:"type:timestamp;column_name:created_at;index"`
-	Data       string `gorm:"type:json;column_name:data"`
+	CheckinsID   string `gorm:"primary_key;type:bigserial;column_name:checkins_id"`
+	ClusterID    string `gorm:"type:uuid;column_name:cluster_id;index"`
+	CreatedAt    string `gorm:"type:timestamp;column_name:created_at;index"`
+	Data         string `gorm:"type:json;column_name:data"`
+	SnapshotHash string `gorm:"type:char(64);column_name:snapshot_hash"`
 }
 
 func newClustersCheckinsTable(checkinID, clusterID string, createdAt time.Time, clusterData []byte) clustersCheckinsTable {
@@ -52,21 +65,28 @@ func (c clustersCheckinsTable) TableName() string {
 	return clustersCheckinsTableName
 }
 
-func createClustersCheckinsTable(db *sql.DB) (sql.Result, error) {
+// checkinsWithData returns a query on the checkins table that resolves each checkin's cluster JSON
+// into its Data field (see checkinsWithDataSQL). Chain conditions onto it the same way as a query
+// on the table itself, except that First and Last don't work on it, since they order by the
+// quoted table name. Use Limit(1) and Find instead
+func checkinsWithData(db *gorm.DB) *gorm.DB {
+	return db.Table("(" + checkinsWithDataSQL + ") AS " + clustersCheckinsTableName)
+}
+
+func createClustersCheckinsTable(db execer, dialect string) (sql.Result, error) {
+	// bigserial only exists in Postgres. elsewhere (i.e. the in-memory sqlite DB used in tests),
+	// an integer primary key is the auto-incrementing equivalent
+	idType := "bigserial"
+	if dialect != postgresDialect {
+		idType = "integer"
+	}
 	return db.Exec(fmt.Sprintf(
-		"CREATE TABLE IF NOT EXISTS %s ( %s bigserial PRIMARY KEY, %s uuid, %s timestamp, %s json )",
+		"CREATE TABLE IF NOT EXISTS %s ( %s %s PRIMARY KEY, %s uuid, %s timestamp, %s json )",
 		clustersCheckinsTableName,
 		clustersCheckinsTableIDKey,
+		idType,
 		clustersTableIDKey,
 		clustersCheckinsTableClusterCreatedAtKey,
 		clustersCheckinsTableDataKey,
 	))
 }
-
-func verifyClustersCheckinsTable(db *sql.DB) error {
-	if _, err := createClustersCheckinsTable(db); err != nil {
-		log.Println("unable to verify clusters table exists")
-		return err
-	}
-	return nil
-}
@@ -3,7 +3,6 @@ package data
 import (
 	"database/sql"
 	"fmt"
-	"log"
 )
 
 const (
@@ -22,7 +21,7 @@ func (c clustersTable) TableName() string {
 	return clustersTableName
 }
 
-func createClustersTable(db *sql.DB) (sql.Result, error) {
+func createClustersTable(db execer) (sql.Result, error) {
 	return db.Exec(fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %s ( %s uuid PRIMARY KEY, %s json )",
 		clustersTableName,
@@ -30,11 +29,3 @@ func createClustersTable(db *sql.DB) (sql.Result, error) {
 		clustersTableDataKey,
 	))
 }
-
-func verifyClustersTable(db *sql.DB) error {
-	if _, err := createClustersTable(db); err != nil {
-		log.Println("unable to verify clusters table exists")
-		return err
-	}
-	return nil
-}
@@ -0,0 +1,120 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+// componentAdoptionResponse type that represents a row of the component adoption query
+type componentAdoptionResponse struct {
+	Version  string `gorm:"column_name:version"`
+	Clusters int64  `gorm:"column_name:clusters"`
+}
+
+// componentsFromSQL returns a Postgres FROM clause that joins each row of table, which must have a
+// JSON data column that holds a models.Cluster, with each of the cluster's components. Each
+// component is available as the JSON value "component"
+func componentsFromSQL(table string) string {
+	// clusters that have never sent components have a null components field, which
+	// json_array_elements can't expand
+	return fmt.Sprintf(`FROM %s, json_array_elements(
+			CASE WHEN json_typeof(%s.data->'components') = 'array'
+			THEN %s.data->'components'
+			ELSE '[]'::json END
+		) AS component`, table, table, table)
+}
+
+// clusterComponentVersions returns the distinct versions of the given component on the given train
+// that cluster reported
+func clusterComponentVersions(cluster models.Cluster, train, component string) []string {
+	seen := make(map[string]bool)
+	ret := []string{}
+	for _, cv := range cluster.Components {
+		if cv == nil || cv.Component == nil || cv.Version == nil {
+			continue
+		}
+		if cv.Component.Name != component || cv.Version.Train != train || seen[cv.Version.Version] {
+			continue
+		}
+		seen[cv.Version.Version] = true
+		ret = append(ret, cv.Version.Version)
+	}
+	return ret
+}
+
+// makeComponentAdoption returns the adoption of each of versions, newest first. counts is the
+// number of clusters running each version string, and total is the number of clusters running any
+// version of the component. Versions that no cluster runs have a count of 0, and counts for
+// versions that were never released aren't included
+func makeComponentAdoption(
+	train,
+	component string,
+	versions []versionsTable,
+	counts map[string]int64,
+	total int64,
+) models.ComponentAdoption {
+	sorted := make([]versionsTable, len(versions))
+	copy(sorted, versions)
+	sort.Stable(versionsNewestFirst(sorted))
+	ret := models.ComponentAdoption{
+		Component: component,
+		Train:     train,
+		Clusters:  &total,
+		Versions:  make([]*models.VersionAdoption, len(sorted)),
+	}
+	for i, version := range sorted {
+		count := counts[version.Version]
+		ret.Versions[i] = &models.VersionAdoption{
+			Version:  version.Version,
+			Released: version.ReleaseTimestamp.String(),
+			Clusters: &count,
+		}
+	}
+	return ret
+}
+
+// GetComponentAdoption returns the number of clusters running each release of the given component
+// on the given train, according to each cluster's latest checkin. This query uses Postgres' JSON
+// functions, so it doesn't work on other databases
+func GetComponentAdoption(db *gorm.DB, train, component string) (models.ComponentAdoption, error) {
+	var versions []versionsTable
+	versionsDB := db.Where(&versionsTable{Train: train, ComponentName: component}).Find(&versions)
+	if versionsDB.Error != nil {
+		return models.ComponentAdoption{}, versionsDB.Error
+	}
+
+	componentsFrom := componentsFromSQL(clustersTableName) + `
+		WHERE component->'component'->>'name' = ?
+		AND component->'version'->>'train' = ?`
+	var rows []componentAdoptionResponse
+	countsDB := db.Raw(`SELECT component->'version'->>'version' AS version,
+		COUNT(DISTINCT clusters.cluster_id) AS clusters
+		`+componentsFrom+`
+		GROUP BY version`,
+		component,
+		train,
+	).Find(&rows)
+	if countsDB.Error != nil {
+		return models.ComponentAdoption{}, countsDB.Error
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Version] = row.Clusters
+	}
+
+	var total struct {
+		Clusters int64 `gorm:"column_name:clusters"`
+	}
+	totalDB := db.Raw(`SELECT COUNT(DISTINCT clusters.cluster_id) AS clusters
+		`+componentsFrom,
+		component,
+		train,
+	).Scan(&total)
+	if totalDB.Error != nil {
+		return models.ComponentAdoption{}, totalDB.Error
+	}
+	return makeComponentAdoption(train, component, versions, counts, total.Clusters), nil
+}
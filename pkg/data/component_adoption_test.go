@@ -0,0 +1,57 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func TestClusterComponentVersions(t *testing.T) {
+	cluster := models.Cluster{
+		ID: clusterID,
+		Components: []*models.ComponentVersion{
+			{Component: &models.Component{Name: componentName}, Version: &models.Version{Train: train, Version: "2.0.0"}},
+			// reported twice, so it should only be counted once
+			{Component: &models.Component{Name: componentName}, Version: &models.Version{Train: train, Version: "2.0.0"}},
+			{Component: &models.Component{Name: componentName}, Version: &models.Version{Train: "beta", Version: "2.1.0"}},
+			{Component: &models.Component{Name: "othercomponent"}, Version: &models.Version{Train: train, Version: "2.2.0"}},
+			{Component: &models.Component{Name: componentName}},
+			nil,
+		},
+	}
+	versions := clusterComponentVersions(cluster, train, componentName)
+	assert.Equal(t, versions, []string{"2.0.0"}, "versions")
+	assert.Equal(t, len(clusterComponentVersions(models.Cluster{ID: clusterID}, train, componentName)), 0, "number of versions")
+}
+
+func TestMakeComponentAdoption(t *testing.T) {
+	now := time.Now()
+	versions := []versionsTable{
+		{ComponentName: componentName, Train: train, Version: "2.0.0", ReleaseTimestamp: Timestamp{Time: now}},
+		{ComponentName: componentName, Train: train, Version: "2.1.0", ReleaseTimestamp: Timestamp{Time: now.Add(-time.Hour)}},
+		{ComponentName: componentName, Train: train, Version: "2.0.1", ReleaseTimestamp: Timestamp{Time: now.Add(time.Hour)}},
+	}
+	// 1.0.0 was never released, so it isn't in the result
+	counts := map[string]int64{"2.0.0": 3, "2.1.0": 1, "1.0.0": 2}
+	adoption := makeComponentAdoption(train, componentName, versions, counts, 6)
+	assert.Equal(t, adoption.Component, componentName, "component")
+	assert.Equal(t, adoption.Train, train, "train")
+	assert.Equal(t, *adoption.Clusters, int64(6), "number of clusters")
+	assert.Equal(t, len(adoption.Versions), 3, "number of versions")
+	expected := []struct {
+		version  string
+		clusters int64
+	}{
+		{"2.1.0", 1},
+		{"2.0.1", 0},
+		{"2.0.0", 3},
+	}
+	for i, exp := range expected {
+		assert.Equal(t, adoption.Versions[i].Version, exp.version, "version")
+		assert.Equal(t, *adoption.Versions[i].Clusters, exp.clusters, "number of clusters")
+	}
+	// the input shouldn't be reordered
+	assert.Equal(t, versions[0].Version, "2.0.0", "first version")
+}
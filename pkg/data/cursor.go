@@ -0,0 +1,38 @@
+package data
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor can't be decoded. Cursors are opaque, so
+	// this usually means the client changed or truncated one
+	ErrInvalidCursor = errors.New("invalid cursor")
+	errInvalidLimit  = errors.New("limit must be greater than 0")
+)
+
+// encodeCursor encodes v into an opaque cursor string. v must be a struct that can always be
+// marshaled to JSON
+func encodeCursor(v interface{}) string {
+	js, err := json.Marshal(v)
+	if err != nil {
+		// all cursor structs are made of strings and ints, so they can always be marshaled
+		panic(err)
+	}
+	return base64.URLEncoding.EncodeToString(js)
+}
+
+// decodeCursor decodes str, which was created by encodeCursor, into v. Returns ErrInvalidCursor if
+// str can't be decoded
+func decodeCursor(str string, v interface{}) error {
+	js, err := base64.URLEncoding.DecodeString(str)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(js, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -16,6 +16,10 @@ var (
 	dBName = config.Spec.DBName
 )
 
+// verifiedTableNames are the tables that VerifyPersistentStorage and CheckHealth check that they
+// can read from
+var verifiedTableNames = []string{versionsTableName, clustersTableName, clustersCheckinsTableName, doctorTableName}
+
 type errNoMoreRows struct {
 	tableName string
 }
@@ -24,49 +28,21 @@ func (e errNoMoreRows) Error() string {
 	return fmt.Sprintf("no more rows available in the '%s' table", e.tableName)
 }
 
-// VerifyPersistentStorage is a high level interace for verifying storage abstractions
+// VerifyPersistentStorage is a high level interace for verifying storage abstractions. It applies
+// all pending schema migrations (see MigrateUp), and returns ErrSchemaTooNew if the database has a
+// newer schema than this code knows about
 func VerifyPersistentStorage(db *gorm.DB) error {
-	if _, err := createOrUpdateVersionsTable(db); err != nil {
-		log.Println("unable to verify " + versionsTableName + " table")
-		return err
-	}
-	count, err := getTableCount(db.DB(), versionsTableName)
-	if err != nil {
-		log.Println("unable to get record count for " + versionsTableName + " table")
-		return err
-	}
-	log.Println("counted " + strconv.Itoa(count) + " records for " + versionsTableName + " table")
-	err = verifyClustersTable(db.DB())
-	if err != nil {
-		log.Println("unable to verify " + clustersTableName + " table")
-		return err
-	}
-	count, err = getTableCount(db.DB(), clustersTableName)
-	if err != nil {
-		log.Println("unable to get record count for " + clustersTableName + " table")
+	if err := MigrateUp(db, LatestSchemaVersion()); err != nil {
+		log.Println("unable to migrate the database schema")
 		return err
 	}
-	log.Println("counted " + strconv.Itoa(count) + " records for " + clustersTableName + " table")
-	err = verifyClustersCheckinsTable(db.DB())
-	if err != nil {
-		log.Println("unable to verify " + clustersCheckinsTableName + " table")
-		return err
-	}
-	count, err = getTableCount(db.DB(), clustersCheckinsTableName)
-	if err != nil {
-		log.Println("unable to get record count for " + clustersCheckinsTableName + " table")
-		return err
-	}
-	err = verifyDoctorTable(db.DB())
-	if err != nil {
-		log.Println("unable to verify " + doctorTableName + " table")
-		return err
-	}
-	count, err = getTableCount(db.DB(), doctorTableName)
-	if err != nil {
-		log.Println("unable to get record count for " + doctorTableName + " table")
-		return err
+	for _, tableName := range verifiedTableNames {
+		count, err := getTableCount(db.DB(), tableName)
+		if err != nil {
+			log.Println("unable to get record count for " + tableName + " table")
+			return err
+		}
+		log.Println("counted " + strconv.Itoa(count) + " records for " + tableName + " table")
 	}
-	log.Println("counted " + strconv.Itoa(count) + " records for " + clustersCheckinsTableName + " table")
 	return nil
 }
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/jinzhu/gorm"
@@ -21,3 +22,37 @@ func NewDB() (*gorm.DB, error) {
 	}
 	return db, nil
 }
+
+// The SQL log levels that SetSQLLogLevel accepts
+const (
+	// SQLLogNone logs nothing
+	SQLLogNone = "none"
+	// SQLLogErrors logs the errors of failed queries. It's the default
+	SQLLogErrors = "errors"
+	// SQLLogAll logs every query, with its arguments
+	SQLLogAll = "all"
+)
+
+type errInvalidSQLLogLevel struct {
+	level string
+}
+
+func (e errInvalidSQLLogLevel) Error() string {
+	return fmt.Sprintf("invalid SQL log level %q. must be one of %s, %s or %s", e.level, SQLLogNone, SQLLogErrors, SQLLogAll)
+}
+
+// SetSQLLogLevel sets which of db's queries are logged. An empty level is the same as
+// SQLLogErrors
+func SetSQLLogLevel(db *gorm.DB, level string) error {
+	switch level {
+	case SQLLogNone:
+		db.LogMode(false)
+	case SQLLogAll:
+		db.LogMode(true)
+	case SQLLogErrors, "":
+		// gorm only logs errors until LogMode is called
+	default:
+		return errInvalidSQLLogLevel{level: level}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func TestSetSQLLogLevel(t *testing.T) {
+	db, err := NewMemDB()
+	assert.NoErr(t, err)
+	for _, level := range []string{"", SQLLogNone, SQLLogErrors, SQLLogAll} {
+		assert.NoErr(t, SetSQLLogLevel(db, level))
+	}
+	assert.Err(t, errInvalidSQLLogLevel{level: "verbose"}, SetSQLLogLevel(db, "verbose"))
+}
@@ -3,7 +3,6 @@ package data
 import (
 	"database/sql"
 	"fmt"
-	"log"
 )
 
 const (
@@ -23,7 +22,7 @@ func (d doctorTable) TableName() string {
 	return doctorTableName
 }
 
-func createDoctorTable(db *sql.DB) (sql.Result, error) {
+func createDoctorTable(db execer) (sql.Result, error) {
 	return db.Exec(fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %s ( %s uuid PRIMARY KEY, %s json )",
 		doctorTableName,
@@ -31,11 +30,3 @@ func createDoctorTable(db *sql.DB) (sql.Result, error) {
 		doctorTableDataKey,
 	))
 }
-
-func verifyDoctorTable(db *sql.DB) error {
-	if _, err := createDoctorTable(db); err != nil {
-		log.Println("unable to verify clusters table exists")
-		return err
-	}
-	return nil
-}
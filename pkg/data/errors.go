@@ -0,0 +1,107 @@
+package data
+
+import (
+	"database/sql/driver"
+	"net"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Reason is a stable, machine readable code for a class of errors that this package returns. API
+// clients can switch on it, instead of parsing error messages
+type Reason string
+
+const (
+	// ReasonNotFound is the reason of errors for records that don't exist
+	ReasonNotFound Reason = "not_found"
+	// ReasonConflict is the reason of errors for writes that conflict with existing records
+	ReasonConflict Reason = "conflict"
+	// ReasonInvalidFilter is the reason of errors for queries that can't be run, like an
+	// ErrImpossibleFilter or an invalid pagination cursor
+	ReasonInvalidFilter Reason = "invalid_filter"
+	// ReasonUnavailable is the reason of transient database errors, like failed transactions or
+	// lost connections. The same request may succeed if it's retried
+	ReasonUnavailable Reason = "database_unavailable"
+	// ReasonInternal is the reason of all other errors
+	ReasonInternal Reason = "internal_error"
+)
+
+// ErrNotFound is returned when a record doesn't exist. It's the same error as
+// gorm.ErrRecordNotFound, so that queries can return gorm's errors as is, but callers should compare
+// errors to ErrNotFound instead. Every other error is a real failure
+var ErrNotFound = gorm.ErrRecordNotFound
+
+// reasoner is implemented by errors that know their own reason
+type reasoner interface {
+	Reason() Reason
+}
+
+// ErrConflict is the error returned when a write conflicts with a record that's already stored
+type ErrConflict struct {
+	msg string
+}
+
+// Error is the error interface implementation
+func (e ErrConflict) Error() string {
+	return e.msg
+}
+
+// Reason returns ReasonConflict
+func (e ErrConflict) Reason() Reason {
+	return ReasonConflict
+}
+
+// Reason returns ReasonInvalidFilter
+func (e ErrImpossibleFilter) Reason() Reason {
+	return ReasonInvalidFilter
+}
+
+// Reason returns ReasonInvalidFilter
+func (e errInvalidBucketInterval) Reason() Reason {
+	return ReasonInvalidFilter
+}
+
+// Reason returns ReasonInvalidFilter
+func (e errInvalidCohortInterval) Reason() Reason {
+	return ReasonInvalidFilter
+}
+
+// Reason returns ReasonInvalidFilter
+func (e errTooManyBuckets) Reason() Reason {
+	return ReasonInvalidFilter
+}
+
+// Reason returns ReasonUnavailable, since transactions usually fail because the database is
+// unavailable or overloaded
+func (t txErr) Reason() Reason {
+	return ReasonUnavailable
+}
+
+// Cause returns the error that err wraps, if it's a RequestError, and err otherwise
+func Cause(err error) error {
+	if reqErr, ok := err.(RequestError); ok {
+		return reqErr.Err
+	}
+	return err
+}
+
+// ReasonOf returns the reason of err, which was returned by this package. Errors that don't have
+// a more specific reason, like unexpected database errors, are ReasonInternal
+func ReasonOf(err error) Reason {
+	err = Cause(err)
+	if r, ok := err.(reasoner); ok {
+		return r.Reason()
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+		return ReasonUnavailable
+	}
+	switch err {
+	case ErrNotFound:
+		return ReasonNotFound
+	case ErrInvalidCursor, errInvalidLimit:
+		return ReasonInvalidFilter
+	case driver.ErrBadConn:
+		return ReasonUnavailable
+	}
+	return ReasonInternal
+}
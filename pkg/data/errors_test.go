@@ -0,0 +1,36 @@
+package data
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestReasonOf(t *testing.T) {
+	_, impossibleFilter := NewClusterCheckinsFilter(time.Now(), time.Now().Add(-time.Hour))
+	_, invalidInterval := ParseBucketInterval("fortnight")
+	tests := []struct {
+		err    error
+		reason Reason
+	}{
+		{err: ErrNotFound, reason: ReasonNotFound},
+		{err: ErrPublisherTokenExists, reason: ReasonConflict},
+		{err: impossibleFilter, reason: ReasonInvalidFilter},
+		{err: invalidInterval, reason: ReasonInvalidFilter},
+		{err: ErrInvalidCursor, reason: ReasonInvalidFilter},
+		{err: txErr{op: "commit", err: errors.New("connection reset")}, reason: ReasonUnavailable},
+		{err: driver.ErrBadConn, reason: ReasonUnavailable},
+		{err: errors.New(`pq: invalid input syntax for uuid: "testcluster"`), reason: ReasonInternal},
+		{err: RequestError{RequestID: "testrequest", Err: ErrNotFound}, reason: ReasonNotFound},
+	}
+	for i, test := range tests {
+		assert.Equal(t, ReasonOf(test.err), test.reason, fmt.Sprintf("reason of error %d (%s)", i, test.err))
+	}
+	reqErr := RequestError{RequestID: "testrequest", Err: ErrInvalidCursor}
+	assert.Err(t, ErrInvalidCursor, Cause(reqErr))
+	assert.Err(t, ErrInvalidCursor, Cause(ErrInvalidCursor))
+}
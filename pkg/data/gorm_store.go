@@ -0,0 +1,170 @@
+package data
+
+import (
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a Store backed by db. The caller should call VerifyPersistentStorage on db
+// before using the returned Store
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (g *gormStore) GetCluster(id string) (models.Cluster, error) {
+	return GetCluster(g.db, id)
+}
+
+func (g *gormStore) UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error) {
+	return UpsertCluster(g.db, id, cluster)
+}
+
+func (g *gormStore) GetClusterCount() (int, error) {
+	return GetClusterCount(g.db)
+}
+
+func (g *gormStore) FilterClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error) {
+	return FilterClustersByAge(g.db, filter, page)
+}
+
+func (g *gormStore) ExportClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error) {
+	return ExportClustersByAge(g.db, filter, page)
+}
+
+func (g *gormStore) SetUpdatesAvailable(cluster *models.Cluster) error {
+	return SetUpdatesAvailable(g.db, cluster)
+}
+
+func (g *gormStore) DetectDormantClusters(now time.Time, window time.Duration) (int, error) {
+	return DetectDormantClusters(g.db, now, window)
+}
+
+func (g *gormStore) CheckInCluster(id string, checkinTime time.Time, cluster models.Cluster) error {
+	return CheckInCluster(g.db, id, checkinTime, cluster)
+}
+
+func (g *gormStore) FilterClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	return FilterClusterCheckins(g.db, filter, page)
+}
+
+func (g *gormStore) ExportClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error) {
+	return ExportClusterCheckins(g.db, filter, page)
+}
+
+func (g *gormStore) FilterPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	return FilterPersistentClusters(g.db, filter, page)
+}
+
+func (g *gormStore) ExportPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error) {
+	return ExportPersistentClusters(g.db, filter, page)
+}
+
+func (g *gormStore) GetClusterCheckinHistory(query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error) {
+	return GetClusterCheckinHistory(g.db, query)
+}
+
+func (g *gormStore) GetClusterCheckinByID(clusterID, checkinID string) (models.ClusterCheckinRecord, error) {
+	return GetClusterCheckinByID(g.db, clusterID, checkinID)
+}
+
+func (g *gormStore) GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	return GetClusterCheckinAt(g.db, clusterID, t)
+}
+
+func (g *gormStore) GetLastCheckinTime(clusterID string) (time.Time, error) {
+	return GetLastCheckinTime(g.db, clusterID)
+}
+
+func (g *gormStore) CompactCheckins(cutoff time.Time) (int, error) {
+	return CompactCheckins(g.db, cutoff)
+}
+
+func (g *gormStore) GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	return GetVersion(g.db, cv)
+}
+
+func (g *gormStore) GetLatestVersion(train, component string) (models.ComponentVersion, error) {
+	return GetLatestVersion(g.db, train, component)
+}
+
+func (g *gormStore) GetLatestVersions(ct []ComponentAndTrain) ([]*models.ComponentVersion, error) {
+	return GetLatestVersions(g.db, ct)
+}
+
+func (g *gormStore) GetVersionsList(train, component string) ([]*models.ComponentVersion, error) {
+	return GetVersionsList(g.db, train, component)
+}
+
+func (g *gormStore) UpsertVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	return UpsertVersion(g.db, cv)
+}
+
+func (g *gormStore) GetVersionCountsByTrain() (map[string]int, error) {
+	return GetVersionCountsByTrain(g.db)
+}
+
+func (g *gormStore) SetVersionDeprecation(train, component, version string, deprecation *models.Deprecation) (models.ComponentVersion, error) {
+	return SetVersionDeprecation(g.db, train, component, version, deprecation)
+}
+
+func (g *gormStore) GetDoctor(id string) (models.DoctorInfo, error) {
+	return GetDoctor(g.db, id)
+}
+
+func (g *gormStore) UpsertDoctor(id string, doctor models.DoctorInfo) (models.DoctorInfo, error) {
+	return UpsertDoctor(g.db, id, doctor)
+}
+
+func (g *gormStore) GetDoctorCount() (int, error) {
+	return GetDoctorCount(g.db)
+}
+
+func (g *gormStore) CreatePublisherToken(name string, components, trains []string) (string, PublisherToken, error) {
+	return CreatePublisherToken(g.db, name, components, trains)
+}
+
+func (g *gormStore) GetPublisherToken(token string) (PublisherToken, error) {
+	return GetPublisherToken(g.db, token)
+}
+
+func (g *gormStore) ListPublisherTokens() ([]PublisherToken, error) {
+	return ListPublisherTokens(g.db)
+}
+
+func (g *gormStore) RevokePublisherToken(name string) error {
+	return RevokePublisherToken(g.db, name)
+}
+
+func (g *gormStore) GetComponentAdoption(train, component string) (models.ComponentAdoption, error) {
+	return GetComponentAdoption(g.db, train, component)
+}
+
+func (g *gormStore) GetReleaseAdoption(train, component, version string, until time.Time) (models.ReleaseAdoption, error) {
+	return GetReleaseAdoption(g.db, train, component, version, until)
+}
+
+func (g *gormStore) GetActiveClusters(query *ActiveClustersQuery) (models.ActiveClusters, error) {
+	return GetActiveClusters(g.db, query)
+}
+
+func (g *gormStore) GetActiveClusterCount(since time.Time) (int, error) {
+	return GetActiveClusterCount(g.db, since)
+}
+
+func (g *gormStore) GetClusterChurn(query *ClusterChurnQuery) (models.ClusterChurn, error) {
+	return GetClusterChurn(g.db, query)
+}
+
+func (g *gormStore) GetClusterCohorts(query *ClusterCohortsQuery) (models.ClusterCohorts, error) {
+	return GetClusterCohorts(g.db, query)
+}
+
+func (g *gormStore) CheckHealth(timeout time.Duration) []HealthCheck {
+	return CheckHealth(g.db, timeout)
+}
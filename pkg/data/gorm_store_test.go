@@ -0,0 +1,77 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func newGormStore(t *testing.T) Store {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	return NewGormStore(db)
+}
+
+// FilterClusterCheckins, FilterPersistentClusters and the adoption and active clusters queries use
+// Postgres-only functions, so they can't be tested against the sqlite DB here. The TestPostgresStore
+// tests cover them
+
+func TestGormStoreClusterRoundTrip(t *testing.T) {
+	testStoreClusterRoundTrip(t, newGormStore(t))
+}
+
+func TestGormStoreFilterClustersByAge(t *testing.T) {
+	testStoreFilterClustersByAge(t, newGormStore(t))
+}
+
+func TestGormStoreFilterClustersByAgePages(t *testing.T) {
+	testStoreFilterClustersByAgePages(t, newGormStore(t))
+}
+
+func TestGormStoreExportClustersByAge(t *testing.T) {
+	testStoreExportClustersByAge(t, newGormStore(t))
+}
+
+func TestGormStoreVersions(t *testing.T) {
+	testStoreVersions(t, newGormStore(t))
+}
+
+func TestGormStoreVersionCountsByTrain(t *testing.T) {
+	testStoreVersionCountsByTrain(t, newGormStore(t))
+}
+
+func TestGormStoreVersionDeprecation(t *testing.T) {
+	testStoreVersionDeprecation(t, newGormStore(t))
+}
+
+func TestGormStoreClusterCheckinHistory(t *testing.T) {
+	testStoreClusterCheckinHistory(t, newGormStore(t))
+}
+
+func TestGormStoreGetClusterCheckin(t *testing.T) {
+	testStoreGetClusterCheckin(t, newGormStore(t))
+}
+
+func TestGormStoreCompactCheckins(t *testing.T) {
+	testStoreCompactCheckins(t, newGormStore(t))
+}
+
+func TestGormStoreClusterChurn(t *testing.T) {
+	testStoreClusterChurn(t, newGormStore(t))
+}
+
+func TestGormStoreClusterCohorts(t *testing.T) {
+	testStoreClusterCohorts(t, newGormStore(t))
+}
+
+func TestGormStoreActiveClusterCount(t *testing.T) {
+	testStoreActiveClusterCount(t, newGormStore(t))
+}
+
+func TestGormStoreDoctorRoundTrip(t *testing.T) {
+	testStoreDoctorRoundTrip(t, newGormStore(t))
+}
+
+func TestGormStorePublisherTokens(t *testing.T) {
+	testStorePublisherTokens(t, newGormStore(t))
+}
@@ -0,0 +1,190 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// The names of the checks that CheckHealth runs, in the order it runs them
+const (
+	// HealthCheckDatabase checks that the database can be reached
+	HealthCheckDatabase = "database"
+	// HealthCheckSchema checks that the tables that VerifyPersistentStorage verifies can be read
+	HealthCheckSchema = "schema"
+	// HealthCheckMigrations checks that every migration has been applied, and no unknown ones have
+	HealthCheckMigrations = "migrations"
+)
+
+// HealthCheck is the result of a single health check of a data store
+type HealthCheck struct {
+	Name string
+	// Err is why the check failed, or nil if it passed
+	Err      error
+	Duration time.Duration
+}
+
+type errHealthCheckTimedOut struct {
+	timeout time.Duration
+}
+
+func (e errHealthCheckTimedOut) Error() string {
+	return fmt.Sprintf("timed out after %s", e.timeout)
+}
+
+type errHealthCheckSkipped struct {
+	failed string
+}
+
+func (e errHealthCheckSkipped) Error() string {
+	return fmt.Sprintf("skipped because the %s check failed", e.failed)
+}
+
+type errHealthCheckInProgress struct {
+	name string
+}
+
+func (e errHealthCheckInProgress) Error() string {
+	return fmt.Sprintf("the previous %s check hasn't returned yet", e.name)
+}
+
+type errMigrationPending struct {
+	version     int
+	description string
+}
+
+func (e errMigrationPending) Error() string {
+	return fmt.Sprintf("migration %d (%s) hasn't been applied", e.version, e.description)
+}
+
+// runningHealthChecks holds the names of the health checks that are running, including the ones
+// that timed out and haven't returned yet
+var runningHealthChecks = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: make(map[string]bool)}
+
+// runHealthCheck runs check, and returns its result. The check fails if it doesn't return within
+// timeout. It keeps running in the background in that case, since database/sql queries can't be
+// cancelled before Go 1.8. So that a hung database can't pile up goroutines, a check with the same
+// name isn't started again until the previous one returns, and fails immediately instead
+func runHealthCheck(name string, timeout time.Duration, check func() error) HealthCheck {
+	start := time.Now()
+	runningHealthChecks.Lock()
+	running := runningHealthChecks.names[name]
+	runningHealthChecks.names[name] = true
+	runningHealthChecks.Unlock()
+	if running {
+		return HealthCheck{Name: name, Err: errHealthCheckInProgress{name: name}, Duration: time.Since(start)}
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := check()
+		runningHealthChecks.Lock()
+		delete(runningHealthChecks.names, name)
+		runningHealthChecks.Unlock()
+		done <- err
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		err = errHealthCheckTimedOut{timeout: timeout}
+	}
+	return HealthCheck{Name: name, Err: err, Duration: time.Since(start)}
+}
+
+// withStatementTimeout calls check with a handle to db. On Postgres, the handle is a transaction
+// whose statements are cancelled by the database if they take longer than timeout, so that checks
+// that time out don't hold a connection until the database recovers. The transaction is always
+// rolled back, since checks only read
+func withStatementTimeout(db *gorm.DB, timeout time.Duration, check func(db *gorm.DB) error) error {
+	if db.Dialect().GetName() != postgresDialect {
+		return check(db)
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback()
+	ms := int64(timeout / time.Millisecond)
+	if ms < 1 {
+		// a statement_timeout of 0 turns the timeout off
+		ms = 1
+	}
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)).Error; err != nil {
+		return err
+	}
+	return check(tx)
+}
+
+// pingDB returns an error if db can't be reached
+func pingDB(db *gorm.DB) error {
+	if sqlDB, ok := db.CommonDB().(*sql.DB); ok {
+		return sqlDB.Ping()
+	}
+	// db is a transaction, which already holds a connection
+	return db.Exec("SELECT 1").Error
+}
+
+// querier is the interface for running a query. Both *sql.DB and *sql.Tx implement it
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// checkTables returns an error if any of the tables in verifiedTableNames can't be read
+func checkTables(db querier) error {
+	for _, tableName := range verifiedTableNames {
+		rows, err := db.Query(fmt.Sprintf("SELECT 1 FROM %s LIMIT 1", tableName))
+		if err != nil {
+			return err
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMigrationsCurrent returns an error if any migration hasn't been applied to db, or
+// ErrSchemaTooNew if db has migrations that this code doesn't know about
+func checkMigrationsCurrent(db *gorm.DB) error {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if _, ok := applied[m.version]; !ok {
+			return errMigrationPending{version: m.version, description: m.description}
+		}
+	}
+	return checkSchemaNotTooNew(db)
+}
+
+// CheckHealth checks that db can serve requests, and returns the result of each check in the order
+// they ran. Each check fails if it takes longer than timeout, and on Postgres, its statements are
+// cancelled after timeout. If the database can't be reached, the other checks are skipped, and fail
+func CheckHealth(db *gorm.DB, timeout time.Duration) []HealthCheck {
+	database := runHealthCheck(HealthCheckDatabase, timeout, func() error {
+		return withStatementTimeout(db, timeout, pingDB)
+	})
+	ret := []HealthCheck{database}
+	if database.Err != nil {
+		for _, name := range []string{HealthCheckSchema, HealthCheckMigrations} {
+			ret = append(ret, HealthCheck{Name: name, Err: errHealthCheckSkipped{failed: HealthCheckDatabase}})
+		}
+		return ret
+	}
+	ret = append(ret, runHealthCheck(HealthCheckSchema, timeout, func() error {
+		return withStatementTimeout(db, timeout, func(db *gorm.DB) error {
+			return checkTables(db.CommonDB())
+		})
+	}))
+	ret = append(ret, runHealthCheck(HealthCheckMigrations, timeout, func() error {
+		return withStatementTimeout(db, timeout, checkMigrationsCurrent)
+	}))
+	return ret
+}
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestCheckHealth(t *testing.T) {
+	db, err := NewMemDB()
+	assert.NoErr(t, err)
+	// the tables don't exist and no migrations have been applied yet
+	checks := CheckHealth(db, time.Second)
+	assert.Equal(t, len(checks), 3, "number of checks")
+	assert.NoErr(t, checks[0].Err)
+	assert.True(t, checks[1].Err != nil, "schema check passed before the tables were created")
+	assert.Err(t, errMigrationPending{version: 1, description: migrations[0].description}, checks[2].Err)
+
+	assert.NoErr(t, VerifyPersistentStorage(db))
+	for i, check := range CheckHealth(db, time.Second) {
+		assert.Equal(t, check.Name, []string{HealthCheckDatabase, HealthCheckSchema, HealthCheckMigrations}[i], fmt.Sprintf("check %d name", i))
+		assert.NoErr(t, check.Err)
+	}
+
+	assert.NoErr(t, db.Close())
+	checks = CheckHealth(db, time.Second)
+	assert.True(t, checks[0].Err != nil, "database check passed after the database was closed")
+	assert.Err(t, errHealthCheckSkipped{failed: HealthCheckDatabase}, checks[1].Err)
+	assert.Err(t, errHealthCheckSkipped{failed: HealthCheckDatabase}, checks[2].Err)
+}
+
+func TestRunHealthCheckTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	check := runHealthCheck("test", 10*time.Millisecond, func() error {
+		<-block
+		return nil
+	})
+	assert.Equal(t, check.Name, "test", "check name")
+	assert.Err(t, errHealthCheckTimedOut{timeout: 10 * time.Millisecond}, check.Err)
+	assert.True(t, check.Duration >= 10*time.Millisecond, "check took %s, less than the timeout", check.Duration)
+}
+
+func TestRunHealthCheckInProgress(t *testing.T) {
+	block := make(chan struct{})
+	returned := make(chan struct{})
+	check := runHealthCheck("in progress", 10*time.Millisecond, func() error {
+		<-block
+		close(returned)
+		return nil
+	})
+	assert.Err(t, errHealthCheckTimedOut{timeout: 10 * time.Millisecond}, check.Err)
+
+	// the timed out check is still running, so the next one isn't started
+	started := false
+	check = runHealthCheck("in progress", time.Second, func() error {
+		started = true
+		return nil
+	})
+	assert.Err(t, errHealthCheckInProgress{name: "in progress"}, check.Err)
+	assert.False(t, started, "check was started while the previous one was running")
+
+	close(block)
+	<-returned
+	// the first check's goroutine removes it from the running checks after it returns
+	for i := 0; i < 100; i++ {
+		if check = runHealthCheck("in progress", time.Second, func() error { return nil }); check.Err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.NoErr(t, check.Err)
+}
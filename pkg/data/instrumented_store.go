@@ -0,0 +1,218 @@
+package data
+
+import (
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// QueryObserver is called with the name of a Store function and the time it took, each time an
+// instrumented Store's function returns
+type QueryObserver func(function string, duration time.Duration)
+
+// instrumentedStore is a Store that times each call to another Store
+type instrumentedStore struct {
+	store   Store
+	observe QueryObserver
+}
+
+// NewInstrumentedStore returns a Store that calls the same function of store, and reports how long
+// each call took to observe. The functions are named the same as the pkg/data functions that the
+// gorm implementation calls
+func NewInstrumentedStore(store Store, observe QueryObserver) Store {
+	return &instrumentedStore{store: store, observe: observe}
+}
+
+func (i *instrumentedStore) observeSince(function string, start time.Time) {
+	i.observe(function, time.Since(start))
+}
+
+func (i *instrumentedStore) GetCluster(id string) (models.Cluster, error) {
+	defer i.observeSince("GetCluster", time.Now())
+	return i.store.GetCluster(id)
+}
+
+func (i *instrumentedStore) UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error) {
+	defer i.observeSince("UpsertCluster", time.Now())
+	return i.store.UpsertCluster(id, cluster)
+}
+
+func (i *instrumentedStore) GetClusterCount() (int, error) {
+	defer i.observeSince("GetClusterCount", time.Now())
+	return i.store.GetClusterCount()
+}
+
+func (i *instrumentedStore) FilterClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error) {
+	defer i.observeSince("FilterClustersByAge", time.Now())
+	return i.store.FilterClustersByAge(filter, page)
+}
+
+func (i *instrumentedStore) ExportClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error) {
+	defer i.observeSince("ExportClustersByAge", time.Now())
+	return i.store.ExportClustersByAge(filter, page)
+}
+
+func (i *instrumentedStore) SetUpdatesAvailable(cluster *models.Cluster) error {
+	defer i.observeSince("SetUpdatesAvailable", time.Now())
+	return i.store.SetUpdatesAvailable(cluster)
+}
+
+func (i *instrumentedStore) DetectDormantClusters(now time.Time, window time.Duration) (int, error) {
+	defer i.observeSince("DetectDormantClusters", time.Now())
+	return i.store.DetectDormantClusters(now, window)
+}
+
+func (i *instrumentedStore) CheckInCluster(id string, checkinTime time.Time, cluster models.Cluster) error {
+	defer i.observeSince("CheckInCluster", time.Now())
+	return i.store.CheckInCluster(id, checkinTime, cluster)
+}
+
+func (i *instrumentedStore) FilterClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	defer i.observeSince("FilterClusterCheckins", time.Now())
+	return i.store.FilterClusterCheckins(filter, page)
+}
+
+func (i *instrumentedStore) ExportClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error) {
+	defer i.observeSince("ExportClusterCheckins", time.Now())
+	return i.store.ExportClusterCheckins(filter, page)
+}
+
+func (i *instrumentedStore) FilterPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	defer i.observeSince("FilterPersistentClusters", time.Now())
+	return i.store.FilterPersistentClusters(filter, page)
+}
+
+func (i *instrumentedStore) ExportPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error) {
+	defer i.observeSince("ExportPersistentClusters", time.Now())
+	return i.store.ExportPersistentClusters(filter, page)
+}
+
+func (i *instrumentedStore) GetClusterCheckinHistory(query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error) {
+	defer i.observeSince("GetClusterCheckinHistory", time.Now())
+	return i.store.GetClusterCheckinHistory(query)
+}
+
+func (i *instrumentedStore) GetClusterCheckinByID(clusterID, checkinID string) (models.ClusterCheckinRecord, error) {
+	defer i.observeSince("GetClusterCheckinByID", time.Now())
+	return i.store.GetClusterCheckinByID(clusterID, checkinID)
+}
+
+func (i *instrumentedStore) GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	defer i.observeSince("GetClusterCheckinAt", time.Now())
+	return i.store.GetClusterCheckinAt(clusterID, t)
+}
+
+func (i *instrumentedStore) GetLastCheckinTime(clusterID string) (time.Time, error) {
+	defer i.observeSince("GetLastCheckinTime", time.Now())
+	return i.store.GetLastCheckinTime(clusterID)
+}
+
+func (i *instrumentedStore) CompactCheckins(cutoff time.Time) (int, error) {
+	defer i.observeSince("CompactCheckins", time.Now())
+	return i.store.CompactCheckins(cutoff)
+}
+
+func (i *instrumentedStore) GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	defer i.observeSince("GetVersion", time.Now())
+	return i.store.GetVersion(cv)
+}
+
+func (i *instrumentedStore) GetLatestVersion(train, component string) (models.ComponentVersion, error) {
+	defer i.observeSince("GetLatestVersion", time.Now())
+	return i.store.GetLatestVersion(train, component)
+}
+
+func (i *instrumentedStore) GetLatestVersions(ct []ComponentAndTrain) ([]*models.ComponentVersion, error) {
+	defer i.observeSince("GetLatestVersions", time.Now())
+	return i.store.GetLatestVersions(ct)
+}
+
+func (i *instrumentedStore) GetVersionsList(train, component string) ([]*models.ComponentVersion, error) {
+	defer i.observeSince("GetVersionsList", time.Now())
+	return i.store.GetVersionsList(train, component)
+}
+
+func (i *instrumentedStore) UpsertVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	defer i.observeSince("UpsertVersion", time.Now())
+	return i.store.UpsertVersion(cv)
+}
+
+func (i *instrumentedStore) GetVersionCountsByTrain() (map[string]int, error) {
+	defer i.observeSince("GetVersionCountsByTrain", time.Now())
+	return i.store.GetVersionCountsByTrain()
+}
+
+func (i *instrumentedStore) SetVersionDeprecation(train, component, version string, deprecation *models.Deprecation) (models.ComponentVersion, error) {
+	defer i.observeSince("SetVersionDeprecation", time.Now())
+	return i.store.SetVersionDeprecation(train, component, version, deprecation)
+}
+
+func (i *instrumentedStore) GetDoctor(id string) (models.DoctorInfo, error) {
+	defer i.observeSince("GetDoctor", time.Now())
+	return i.store.GetDoctor(id)
+}
+
+func (i *instrumentedStore) UpsertDoctor(id string, doctor models.DoctorInfo) (models.DoctorInfo, error) {
+	defer i.observeSince("UpsertDoctor", time.Now())
+	return i.store.UpsertDoctor(id, doctor)
+}
+
+func (i *instrumentedStore) GetDoctorCount() (int, error) {
+	defer i.observeSince("GetDoctorCount", time.Now())
+	return i.store.GetDoctorCount()
+}
+
+func (i *instrumentedStore) CreatePublisherToken(name string, components, trains []string) (string, PublisherToken, error) {
+	defer i.observeSince("CreatePublisherToken", time.Now())
+	return i.store.CreatePublisherToken(name, components, trains)
+}
+
+func (i *instrumentedStore) GetPublisherToken(token string) (PublisherToken, error) {
+	defer i.observeSince("GetPublisherToken", time.Now())
+	return i.store.GetPublisherToken(token)
+}
+
+func (i *instrumentedStore) ListPublisherTokens() ([]PublisherToken, error) {
+	defer i.observeSince("ListPublisherTokens", time.Now())
+	return i.store.ListPublisherTokens()
+}
+
+func (i *instrumentedStore) RevokePublisherToken(name string) error {
+	defer i.observeSince("RevokePublisherToken", time.Now())
+	return i.store.RevokePublisherToken(name)
+}
+
+func (i *instrumentedStore) GetComponentAdoption(train, component string) (models.ComponentAdoption, error) {
+	defer i.observeSince("GetComponentAdoption", time.Now())
+	return i.store.GetComponentAdoption(train, component)
+}
+
+func (i *instrumentedStore) GetReleaseAdoption(train, component, version string, until time.Time) (models.ReleaseAdoption, error) {
+	defer i.observeSince("GetReleaseAdoption", time.Now())
+	return i.store.GetReleaseAdoption(train, component, version, until)
+}
+
+func (i *instrumentedStore) GetActiveClusters(query *ActiveClustersQuery) (models.ActiveClusters, error) {
+	defer i.observeSince("GetActiveClusters", time.Now())
+	return i.store.GetActiveClusters(query)
+}
+
+func (i *instrumentedStore) GetActiveClusterCount(since time.Time) (int, error) {
+	defer i.observeSince("GetActiveClusterCount", time.Now())
+	return i.store.GetActiveClusterCount(since)
+}
+
+func (i *instrumentedStore) GetClusterChurn(query *ClusterChurnQuery) (models.ClusterChurn, error) {
+	defer i.observeSince("GetClusterChurn", time.Now())
+	return i.store.GetClusterChurn(query)
+}
+
+func (i *instrumentedStore) GetClusterCohorts(query *ClusterCohortsQuery) (models.ClusterCohorts, error) {
+	defer i.observeSince("GetClusterCohorts", time.Now())
+	return i.store.GetClusterCohorts(query)
+}
+
+func (i *instrumentedStore) CheckHealth(timeout time.Duration) []HealthCheck {
+	defer i.observeSince("CheckHealth", time.Now())
+	return i.store.CheckHealth(timeout)
+}
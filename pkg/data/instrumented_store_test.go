@@ -0,0 +1,23 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestInstrumentedStore(t *testing.T) {
+	observed := make(map[string]int)
+	store := NewInstrumentedStore(NewMemStore(), func(function string, duration time.Duration) {
+		assert.True(t, duration >= 0, "negative duration %s for %s", duration, function)
+		observed[function]++
+	})
+	// the instrumented store should still pass the conformance tests
+	testStoreClusterRoundTrip(t, store)
+	testStoreVersionCountsByTrain(t, store)
+	assert.True(t, observed["UpsertCluster"] > 0, "UpsertCluster calls weren't observed")
+	assert.True(t, observed["GetCluster"] > 0, "GetCluster calls weren't observed")
+	assert.Equal(t, observed["GetVersionCountsByTrain"], 2, "number of GetVersionCountsByTrain calls")
+	assert.Equal(t, observed["GetDoctorCount"], 0, "number of GetDoctorCount calls")
+}
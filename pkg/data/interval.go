@@ -0,0 +1,95 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pgAge returns the same string that Postgres' AGE(t1, t2) function returns (with the default
+// 'postgres' IntervalStyle), so that Store implementations that don't run on Postgres can return
+// the same cluster ages and last checkin intervals. For example:
+//
+//  1 year 2 mons 3 days 04:05:06
+//  -1 days -02:00:00
+//
+// Like Postgres, the result is symbolic: it subtracts years, months, days and times separately,
+// borrowing from the larger units when necessary, rather than just dividing t1 - t2 into days
+func pgAge(t1, t2 time.Time) string {
+	t1, t2 = t1.UTC(), t2.UTC()
+	negative := t1.Before(t2)
+	if negative {
+		t1, t2 = t2, t1
+	}
+	years := t1.Year() - t2.Year()
+	months := int(t1.Month()) - int(t2.Month())
+	days := t1.Day() - t2.Day()
+	dur := time.Duration(t1.Hour()-t2.Hour())*time.Hour +
+		time.Duration(t1.Minute()-t2.Minute())*time.Minute +
+		time.Duration(t1.Second()-t2.Second())*time.Second +
+		time.Duration(t1.Nanosecond()-t2.Nanosecond())
+	// durations are truncated to microseconds, which is Postgres' timestamp precision
+	dur = dur / time.Microsecond * time.Microsecond
+
+	if dur < 0 {
+		dur += 24 * time.Hour
+		days--
+	}
+	for days < 0 {
+		// borrow a month's worth of days from the earlier time's month, the way Postgres does when
+		// the result is positive (we've already swapped the arguments so that it is)
+		days += daysIn(t2.Month(), t2.Year())
+		months--
+	}
+	if months < 0 {
+		months += 12
+		years--
+	}
+	return formatPGInterval(years, months, days, dur, negative)
+}
+
+func daysIn(month time.Month, year int) int {
+	// the zeroth day of the next month is the last day of this month
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func formatPGInterval(years, months, days int, dur time.Duration, negative bool) string {
+	sign, mult := "", 1
+	if negative {
+		sign, mult = "-", -1
+	}
+	parts := []string{}
+	if years != 0 {
+		parts = append(parts, pluralize(mult*years, "year", "years"))
+	}
+	if months != 0 {
+		parts = append(parts, pluralize(mult*months, "mon", "mons"))
+	}
+	if days != 0 {
+		parts = append(parts, pluralize(mult*days, "day", "days"))
+	}
+	if dur != 0 || len(parts) == 0 {
+		hours := dur / time.Hour
+		dur -= hours * time.Hour
+		minutes := dur / time.Minute
+		dur -= minutes * time.Minute
+		seconds := dur / time.Second
+		dur -= seconds * time.Second
+		timeStr := fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+		if micros := dur / time.Microsecond; micros != 0 {
+			timeStr += strings.TrimRight(fmt.Sprintf(".%06d", micros), "0")
+		}
+		if dur != 0 || hours != 0 || minutes != 0 || seconds != 0 {
+			timeStr = sign + timeStr
+		}
+		parts = append(parts, timeStr)
+	}
+	return strings.Join(parts, " ")
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
@@ -0,0 +1,40 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestPGAge(t *testing.T) {
+	base := time.Date(2016, time.March, 31, 12, 0, 0, 0, time.UTC)
+	type testCase struct {
+		t1       time.Time
+		t2       time.Time
+		expected string
+	}
+	testCases := []testCase{
+		testCase{t1: base, t2: base, expected: "00:00:00"},
+		testCase{t1: base.Add(90 * time.Minute), t2: base, expected: "01:30:00"},
+		testCase{t1: base, t2: base.Add(2 * time.Hour), expected: "-02:00:00"},
+		testCase{t1: base.Add(26 * time.Hour), t2: base, expected: "1 day 02:00:00"},
+		testCase{t1: base, t2: base.Add(50 * time.Hour), expected: "-2 days -02:00:00"},
+		testCase{t1: base.Add(1500 * time.Microsecond), t2: base, expected: "00:00:00.0015"},
+		testCase{
+			t1:       time.Date(2017, time.May, 3, 4, 5, 6, 0, time.UTC),
+			t2:       time.Date(2016, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected: "1 year 2 mons 2 days 04:05:06",
+		},
+		testCase{
+			// borrowing days from February in a leap year
+			t1:       time.Date(2016, time.March, 1, 0, 0, 0, 0, time.UTC),
+			t2:       time.Date(2016, time.February, 28, 12, 0, 0, 0, time.UTC),
+			expected: "1 day 12:00:00",
+		},
+	}
+	for i, tc := range testCases {
+		assert.Equal(t, pgAge(tc.t1, tc.t2), tc.expected, fmt.Sprintf("age for test case %d", i))
+	}
+}
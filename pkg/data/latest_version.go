@@ -0,0 +1,55 @@
+package data
+
+// latestVersion returns the latest of the given versions, which must all be in the same component
+// and train. Yanked versions are skipped, but deprecated ones aren't. Returns false if versions is
+// empty or every version has been yanked. See isNewerVersion for the precedence rules
+func latestVersion(versions []versionsTable, deprecations releaseDeprecations) (versionsTable, bool) {
+	var latest versionsTable
+	found := false
+	for _, candidate := range versions {
+		if deprecations.yanked(candidate) {
+			continue
+		}
+		if !found || isNewerVersion(candidate, latest) {
+			latest = candidate
+			found = true
+		}
+	}
+	return latest, found
+}
+
+// isNewerVersion returns true if candidate is a newer release than current. Both must be in the
+// same component and train. The precedence rules are as follows:
+//
+// - Versions whose version strings are valid semantic versions are compared according to the
+//   SemVer 2.0.0 precedence rules (see http://semver.org), so 2.0.0-rc1 < 2.0.0-rc2 < 2.0.0
+// - A version with a valid semantic version always takes precedence over one without
+// - Versions without valid semantic versions, or whose semantic versions have equal precedence,
+//   are compared by their release timestamps
+//
+// This replaces the "best-effort" timestamp-only algorithm described in
+// doc/component-version-freshness-algorithm.md, which let backdated hotfixes and re-published old
+// versions become the latest
+func isNewerVersion(candidate, current versionsTable) bool {
+	candidateSemVer, candidateErr := parseSemVer(candidate.Version)
+	currentSemVer, currentErr := parseSemVer(current.Version)
+	switch {
+	case candidateErr == nil && currentErr == nil:
+		if cmp := candidateSemVer.compare(currentSemVer); cmp != 0 {
+			return cmp > 0
+		}
+	case candidateErr == nil:
+		return true
+	case currentErr == nil:
+		return false
+	}
+	return candidate.ReleaseTimestamp.Time.After(current.ReleaseTimestamp.Time)
+}
+
+// versionsNewestFirst sorts versions, which must all be in the same component and train, from
+// newest to oldest. See isNewerVersion for the precedence rules
+type versionsNewestFirst []versionsTable
+
+func (v versionsNewestFirst) Len() int           { return len(v) }
+func (v versionsNewestFirst) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+func (v versionsNewestFirst) Less(i, j int) bool { return isNewerVersion(v[i], v[j]) }
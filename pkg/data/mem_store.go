@@ -0,0 +1,1037 @@
+package data
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// memStore is a pure Go, in-memory Store implementation. It stores rows in the same table types
+// that the gorm implementation does, so that both share parsing and version precedence logic
+type memStore struct {
+	mut      sync.RWMutex
+	clusters map[string]clustersTable
+	checkins []clustersCheckinsTable
+	// lastCheckinID is the ID of the newest checkin. checkins are removed by compaction, so it may
+	// not be the ID of the last element of checkins
+	lastCheckinID int
+	dailyCheckins map[dailyCheckinsKey]clustersCheckinsDailyTable
+	// snapshots is keyed on snapshot hash
+	snapshots map[string]clusterSnapshotsTable
+	versions  []versionsTable
+	// deprecations holds the deprecated and yanked releases. It's separate from versions, like the
+	// release_deprecations table, so that re-publishing a release doesn't clear its deprecation
+	deprecations releaseDeprecations
+	doctors      map[string]doctorTable
+	// publisherTokens is keyed on token hash
+	publisherTokens map[string]publisherTokensTable
+	// activityEvents are ordered by the time they were recorded
+	activityEvents []clusterActivityEventsTable
+	// dormancy is keyed on cluster ID
+	dormancy map[string]clusterDormancyTable
+	// dormancyWatermark is the time before which DetectDormantClusters has walked every checkin
+	dormancyWatermark time.Time
+	// now returns the current time. it's used in place of Postgres' NOW()
+	now func() time.Time
+}
+
+// NewMemStore returns a Store that keeps all data in memory. The initial store is empty, and is
+// best used for testing. Unlike NewMemDB, it doesn't require CGO or the testonly build tag, and it
+// supports all queries, including the ones that only work on Postgres in the gorm implementation
+func NewMemStore() Store {
+	return &memStore{
+		clusters:        make(map[string]clustersTable),
+		dailyCheckins:   make(map[dailyCheckinsKey]clustersCheckinsDailyTable),
+		snapshots:       make(map[string]clusterSnapshotsTable),
+		deprecations:    make(releaseDeprecations),
+		doctors:         make(map[string]doctorTable),
+		publisherTokens: make(map[string]publisherTokensTable),
+		dormancy:        make(map[string]clusterDormancyTable),
+		now:             time.Now,
+	}
+}
+
+func (m *memStore) GetCluster(id string) (models.Cluster, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return m.getCluster(id)
+}
+
+func (m *memStore) getCluster(id string) (models.Cluster, error) {
+	row, ok := m.clusters[id]
+	if !ok {
+		return models.Cluster{}, ErrNotFound
+	}
+	cluster, err := parseJSONCluster([]byte(row.Data))
+	if err != nil {
+		return models.Cluster{}, errParsingCluster{origErr: err}
+	}
+	return cluster, nil
+}
+
+func (m *memStore) UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error) {
+	js, err := json.Marshal(cluster)
+	if err != nil {
+		return models.Cluster{}, err
+	}
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	if err := m.checkInCluster(id, m.now(), js); err != nil {
+		return models.Cluster{}, err
+	}
+	m.clusters[id] = clustersTable{ClusterID: id, Data: string(js)}
+	return m.getCluster(id)
+}
+
+func (m *memStore) GetClusterCount() (int, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return len(m.clusters), nil
+}
+
+func (m *memStore) FilterClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	summaries, err := m.checkinSummaries()
+	if err != nil {
+		return nil, "", err
+	}
+	matched := []checkinSummary{}
+	for _, summary := range summaries {
+		if _, ok := m.clusters[summary.clusterID]; !ok {
+			continue
+		}
+		if summary.firstSeen.After(filter.CreatedAfter) &&
+			summary.firstSeen.Before(filter.CreatedBefore) &&
+			summary.firstSeen.After(filter.CheckedInAfter) &&
+			summary.lastSeen.Before(filter.CheckedInBefore) {
+			matched = append(matched, summary)
+		}
+	}
+	sort.Sort(checkinSummariesByFirstSeen(matched))
+	matched, next, err := pageCheckinSummaries(matched, page, false)
+	if err != nil {
+		return nil, "", err
+	}
+	rows := make([]clustersTable, len(matched))
+	for i, summary := range matched {
+		rows[i] = m.clusters[summary.clusterID]
+	}
+	clusters, err := makeClusters(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	return clusters, next, nil
+}
+
+func (m *memStore) ExportClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error) {
+	results, _, err := m.FilterClustersByAge(filter, page.withoutLimit())
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]interface{}, len(results))
+	for i, result := range results {
+		rows[i] = result
+	}
+	return NewSliceClusterRows(rows), nil
+}
+
+func (m *memStore) SetUpdatesAvailable(cluster *models.Cluster) error {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return setUpdatesAvailable(cluster, m.getVersionsByComponentAndTrain)
+}
+
+func (m *memStore) DetectDormantClusters(now time.Time, window time.Duration) (int, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return 0, err
+	}
+	// the same checkins that the gorm implementation walks
+	walked := []checkinSpan{}
+	for _, span := range spans {
+		if !span.lastSeen.Before(m.dormancyWatermark) && !span.firstSeen.After(now) {
+			walked = append(walked, span)
+		}
+	}
+	events := walkCheckinSpans(m.dormancy, walked, now, window)
+	clusterIDs := make([]string, 0, len(m.dormancy))
+	for clusterID := range m.dormancy {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+	sort.Strings(clusterIDs)
+	for _, clusterID := range clusterIDs {
+		state := m.dormancy[clusterID]
+		if !state.Dormant && !state.LastSeen.Time.Add(window).After(now) {
+			events = append(events, state.dormantEvent(window))
+			state.Dormant = true
+			m.dormancy[clusterID] = state
+		}
+	}
+	for _, event := range events {
+		event.EventID = strconv.Itoa(len(m.activityEvents) + 1)
+		if err := event.BeforeSave(); err != nil {
+			return 0, err
+		}
+		m.activityEvents = append(m.activityEvents, event)
+	}
+	if watermark := now.Add(-dormancyDetectionOverlap); watermark.After(m.dormancyWatermark) {
+		m.dormancyWatermark = watermark
+	}
+	return len(events), nil
+}
+
+func (m *memStore) CheckInCluster(id string, checkinTime time.Time, cluster models.Cluster) error {
+	js, err := json.Marshal(cluster)
+	if err != nil {
+		return err
+	}
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	return m.checkInCluster(id, checkinTime, js)
+}
+
+func (m *memStore) checkInCluster(id string, checkinTime time.Time, clusterJSON []byte) error {
+	checkinID := strconv.Itoa(m.lastCheckinID + 1)
+	hash := hashClusterSnapshot(clusterJSON)
+	record := newClustersCheckinsTable(checkinID, id, checkinTime, nil)
+	record.SnapshotHash = hash
+	if err := record.BeforeSave(); err != nil {
+		return err
+	}
+	if _, ok := m.snapshots[hash]; !ok {
+		m.snapshots[hash] = clusterSnapshotsTable{Hash: hash, Data: string(clusterJSON)}
+	}
+	m.lastCheckinID++
+	m.checkins = append(m.checkins, record)
+	return nil
+}
+
+// checkinWithData returns row with its Data set to the JSON of the snapshot it references, the
+// same way that checkinsWithData does in the gorm implementation. Callers must hold at least a
+// read lock
+func (m *memStore) checkinWithData(row clustersCheckinsTable) clustersCheckinsTable {
+	if row.Data == "" {
+		row.Data = m.snapshots[row.SnapshotHash].Data
+	}
+	return row
+}
+
+// dailyCheckinsKey is the primary key of the clusters_checkins_daily table
+type dailyCheckinsKey struct {
+	clusterID string
+	day       string
+}
+
+// checkinSpan is the equivalent of a row of the checkin_spans and checkin_snapshots table
+// expressions that the gorm implementation queries
+type checkinSpan struct {
+	clusterID string
+	firstSeen time.Time
+	lastSeen  time.Time
+	checkins  int
+	data      string
+}
+
+// checkinSpans returns a span for each raw checkin and each daily summary of compacted checkins.
+// Callers must hold at least a read lock
+func (m *memStore) checkinSpans() ([]checkinSpan, error) {
+	ret := make([]checkinSpan, 0, len(m.checkins)+len(m.dailyCheckins))
+	for _, checkin := range m.checkins {
+		createdAt, err := checkin.createdAtTime()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, checkinSpan{
+			clusterID: checkin.ClusterID,
+			firstSeen: createdAt,
+			lastSeen:  createdAt,
+			checkins:  1,
+			data:      m.checkinWithData(checkin).Data,
+		})
+	}
+	for _, daily := range m.dailyCheckins {
+		firstSeen, err := daily.firstSeenTime()
+		if err != nil {
+			return nil, err
+		}
+		lastSeen, err := daily.lastSeenTime()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, checkinSpan{
+			clusterID: daily.ClusterID,
+			firstSeen: firstSeen,
+			lastSeen:  lastSeen,
+			checkins:  int(daily.Checkins),
+			data:      daily.Data,
+		})
+	}
+	return ret, nil
+}
+
+func (m *memStore) CompactCheckins(cutoff time.Time) (int, error) {
+	cutoff = truncateToDay(cutoff)
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	kept := []clustersCheckinsTable{}
+	byDay := make(map[time.Time][]clustersCheckinsTable)
+	for _, row := range m.checkins {
+		createdAt, err := row.createdAtTime()
+		if err != nil {
+			return 0, err
+		}
+		if !createdAt.Before(cutoff) {
+			kept = append(kept, row)
+			continue
+		}
+		day := truncateToDay(createdAt)
+		byDay[day] = append(byDay[day], m.checkinWithData(row))
+	}
+	// build all of the new summaries before changing anything, so that an error leaves the store
+	// unchanged
+	updated := make(map[dailyCheckinsKey]clustersCheckinsDailyTable)
+	for day, rows := range byDay {
+		summaries, err := summarizeCheckins(day, rows)
+		if err != nil {
+			return 0, err
+		}
+		for clusterID, summary := range summaries {
+			key := dailyCheckinsKey{clusterID: clusterID, day: Timestamp{Time: day}.String()}
+			if existing, ok := m.dailyCheckins[key]; ok {
+				if err := summary.addSummary(existing); err != nil {
+					return 0, err
+				}
+			}
+			row, err := summary.row()
+			if err != nil {
+				return 0, err
+			}
+			updated[key] = row
+		}
+	}
+	for key, row := range updated {
+		m.dailyCheckins[key] = row
+	}
+	compacted := len(m.checkins) - len(kept)
+	m.checkins = kept
+	referenced := make(map[string]bool, len(kept))
+	for _, row := range kept {
+		referenced[row.SnapshotHash] = true
+	}
+	for hash := range m.snapshots {
+		if !referenced[hash] {
+			delete(m.snapshots, hash)
+		}
+	}
+	return compacted, nil
+}
+
+// checkinSummary is the equivalent of a row of the GROUP BY cluster_id queries on the
+// clusters_checkins table
+type checkinSummary struct {
+	clusterID string
+	firstSeen time.Time
+	lastSeen  time.Time
+	checkins  int
+}
+
+func (c checkinSummary) position() clusterPosition {
+	return clusterPosition{firstSeen: c.firstSeen, clusterID: c.clusterID}
+}
+
+func (c checkinSummary) filterResponse(now time.Time, includeLastCheckin bool) clustersCheckinsFilterResponse {
+	ret := clustersCheckinsFilterResponse{
+		ClusterID:  c.clusterID,
+		FirstSeen:  Timestamp{Time: c.firstSeen}.String(),
+		LastSeen:   Timestamp{Time: c.lastSeen}.String(),
+		ClusterAge: pgAge(c.lastSeen, c.firstSeen),
+		Checkins:   strconv.Itoa(c.checkins),
+	}
+	if includeLastCheckin {
+		ret.LastCheckin = pgAge(c.lastSeen, now)
+	}
+	return ret
+}
+
+// checkinSummaries returns a summary of the checkins for every cluster that has checked in, sorted
+// by cluster ID. Callers must hold at least a read lock
+func (m *memStore) checkinSummaries() ([]checkinSummary, error) {
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*checkinSummary)
+	for _, span := range spans {
+		summary, ok := byID[span.clusterID]
+		if !ok {
+			summary = &checkinSummary{clusterID: span.clusterID, firstSeen: span.firstSeen, lastSeen: span.lastSeen}
+			byID[span.clusterID] = summary
+		}
+		if span.firstSeen.Before(summary.firstSeen) {
+			summary.firstSeen = span.firstSeen
+		}
+		if span.lastSeen.After(summary.lastSeen) {
+			summary.lastSeen = span.lastSeen
+		}
+		summary.checkins += span.checkins
+	}
+	ret := make([]checkinSummary, 0, len(byID))
+	for _, summary := range byID {
+		ret = append(ret, *summary)
+	}
+	sort.Sort(checkinSummariesByClusterID(ret))
+	return ret, nil
+}
+
+type checkinSummariesByClusterID []checkinSummary
+
+func (c checkinSummariesByClusterID) Len() int           { return len(c) }
+func (c checkinSummariesByClusterID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c checkinSummariesByClusterID) Less(i, j int) bool { return c[i].clusterID < c[j].clusterID }
+
+// checkinSummariesByFirstSeen sorts checkin summaries by (firstSeen, clusterID), the same order
+// that the gorm implementation uses
+type checkinSummariesByFirstSeen []checkinSummary
+
+func (c checkinSummariesByFirstSeen) Len() int      { return len(c) }
+func (c checkinSummariesByFirstSeen) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c checkinSummariesByFirstSeen) Less(i, j int) bool {
+	return c[i].position().before(c[j].position())
+}
+
+// pageCheckinSummaries returns the summaries in page and the cursor for the next page. summaries
+// must already be in the query's order, and desc must be true if that order is descending
+func pageCheckinSummaries(summaries []checkinSummary, page *ClusterPage, desc bool) ([]checkinSummary, string, error) {
+	ret := []checkinSummary{}
+	for _, summary := range summaries {
+		if page.includes(summary.position(), desc) {
+			ret = append(ret, summary)
+		}
+	}
+	n, next, err := page.trim(len(ret), func(i int) (clusterPosition, error) {
+		return ret[i].position(), nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return ret[:n], next, nil
+}
+
+func (m *memStore) FilterClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	summaries, err := m.checkinSummaries()
+	if err != nil {
+		return nil, "", err
+	}
+	matched := []checkinSummary{}
+	for _, summary := range summaries {
+		if summary.firstSeen.After(filter.CreatedAfter) && summary.firstSeen.Before(filter.CreatedBefore) {
+			matched = append(matched, summary)
+		}
+	}
+	sort.Sort(sort.Reverse(checkinSummariesByFirstSeen(matched)))
+	matched, next, err := pageCheckinSummaries(matched, page, true)
+	if err != nil {
+		return nil, "", err
+	}
+	now := m.now()
+	rows := make([]clustersCheckinsFilterResponse, len(matched))
+	for i, summary := range matched {
+		rows[i] = summary.filterResponse(now, true)
+	}
+	checkins, err := makeClusterCheckins(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	return checkins, next, nil
+}
+
+func (m *memStore) ExportClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error) {
+	results, _, err := m.FilterClusterCheckins(filter, page.withoutLimit())
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]interface{}, len(results))
+	for i, result := range results {
+		rows[i] = result
+	}
+	return NewSliceClusterRows(rows), nil
+}
+
+func (m *memStore) FilterPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	summaries, err := m.checkinSummaries()
+	if err != nil {
+		return nil, "", err
+	}
+	matched := []checkinSummary{}
+	for _, summary := range summaries {
+		if summary.firstSeen.After(filter.Epoch) &&
+			summary.firstSeen.Before(filter.Timestamp) &&
+			summary.checkins > 1 &&
+			summary.lastSeen.After(filter.RelativeYesterday) {
+			matched = append(matched, summary)
+		}
+	}
+	sort.Sort(checkinSummariesByFirstSeen(matched))
+	matched, next, err := pageCheckinSummaries(matched, page, false)
+	if err != nil {
+		return nil, "", err
+	}
+	now := m.now()
+	rows := make([]clustersCheckinsFilterResponse, len(matched))
+	for i, summary := range matched {
+		rows[i] = summary.filterResponse(now, false)
+	}
+	checkins, err := makeClusterCheckins(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	return checkins, next, nil
+}
+
+func (m *memStore) ExportPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error) {
+	results, _, err := m.FilterPersistentClusters(filter, page.withoutLimit())
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]interface{}, len(results))
+	for i, result := range results {
+		rows[i] = result
+	}
+	return NewSliceClusterRows(rows), nil
+}
+
+// checkinsByCreatedAt sorts checkins by their created_at time. m.checkins is in checkins_id order,
+// so a stable sort orders them the same way as the gorm implementation does
+type checkinsByCreatedAt []clustersCheckinsTable
+
+func (c checkinsByCreatedAt) Len() int      { return len(c) }
+func (c checkinsByCreatedAt) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c checkinsByCreatedAt) Less(i, j int) bool {
+	// createdAt was validated by BeforeSave when the checkin was recorded
+	iTime, _ := c[i].createdAtTime()
+	jTime, _ := c[j].createdAtTime()
+	return iTime.Before(jTime)
+}
+
+func (m *memStore) GetClusterCheckinHistory(query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	rows := []clustersCheckinsTable{}
+	for _, row := range m.checkins {
+		included, err := query.includes(row)
+		if err != nil {
+			return models.ClusterCheckinHistory{}, err
+		}
+		if included {
+			rows = append(rows, m.checkinWithData(row))
+		}
+	}
+	sort.Stable(checkinsByCreatedAt(rows))
+	if len(rows) > query.Limit+1 {
+		rows = rows[:query.Limit+1]
+	}
+	return makeClusterCheckinHistory(rows, query.Limit)
+}
+
+func (m *memStore) GetClusterCheckinByID(clusterID, checkinID string) (models.ClusterCheckinRecord, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	id, err := strconv.Atoi(checkinID)
+	if err != nil {
+		return models.ClusterCheckinRecord{}, ErrNotFound
+	}
+	for _, row := range m.checkins {
+		if row.ClusterID == clusterID && row.CheckinsID == strconv.Itoa(id) {
+			return makeClusterCheckinRecord(m.checkinWithData(row))
+		}
+	}
+	return models.ClusterCheckinRecord{}, ErrNotFound
+}
+
+func (m *memStore) GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	var latest *clustersCheckinsTable
+	var latestTime time.Time
+	for i, row := range m.checkins {
+		if row.ClusterID != clusterID {
+			continue
+		}
+		createdAt, err := row.createdAtTime()
+		if err != nil {
+			return models.ClusterCheckinRecord{}, err
+		}
+		// checkins are in ID order, so a later checkin with the same time wins, like it does in the
+		// gorm implementation
+		if !createdAt.After(t) && (latest == nil || !createdAt.Before(latestTime)) {
+			latest = &m.checkins[i]
+			latestTime = createdAt
+		}
+	}
+	if latest == nil {
+		return models.ClusterCheckinRecord{}, ErrNotFound
+	}
+	return makeClusterCheckinRecord(m.checkinWithData(*latest))
+}
+
+func (m *memStore) GetLastCheckinTime(clusterID string) (time.Time, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	var latest time.Time
+	found := false
+	for _, row := range m.checkins {
+		if row.ClusterID != clusterID {
+			continue
+		}
+		createdAt, err := row.createdAtTime()
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !found || createdAt.After(latest) {
+			latest = createdAt
+			found = true
+		}
+	}
+	if !found {
+		return time.Time{}, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (m *memStore) GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	idx := m.versionIndex(cv.Component.Name, cv.Version.Train, cv.Version.Version)
+	if idx < 0 {
+		return models.ComponentVersion{}, ErrNotFound
+	}
+	return parseDBVersion(m.versions[idx], m.deprecations)
+}
+
+// versionIndex returns the index in m.versions of the given version, or -1 if it doesn't exist.
+// Callers must hold at least a read lock
+func (m *memStore) versionIndex(component, train, version string) int {
+	for i, row := range m.versions {
+		if row.ComponentName == component && row.Train == train && row.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// versionsList returns all the versions for the given train & component, in the order they were
+// first published. Callers must hold at least a read lock
+func (m *memStore) versionsList(train, component string) []versionsTable {
+	ret := []versionsTable{}
+	for _, row := range m.versions {
+		if row.ComponentName == component && row.Train == train {
+			ret = append(ret, row)
+		}
+	}
+	return ret
+}
+
+func (m *memStore) getVersionsByComponentAndTrain(ct []ComponentAndTrain) (map[ComponentAndTrain][]versionsTable, releaseDeprecations, error) {
+	grouped := make(map[ComponentAndTrain][]versionsTable)
+	for _, c := range ct {
+		if _, ok := grouped[c]; ok {
+			continue
+		}
+		if rows := m.versionsList(c.Train, c.ComponentName); len(rows) > 0 {
+			grouped[c] = rows
+		}
+	}
+	return grouped, m.deprecations, nil
+}
+
+func (m *memStore) GetLatestVersion(train, component string) (models.ComponentVersion, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	latest, ok := latestVersion(m.versionsList(train, component), m.deprecations)
+	if !ok {
+		return models.ComponentVersion{}, ErrNotFound
+	}
+	return parseDBVersion(latest, m.deprecations)
+}
+
+func (m *memStore) GetLatestVersions(ct []ComponentAndTrain) ([]*models.ComponentVersion, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	grouped, deprecations, err := m.getVersionsByComponentAndTrain(ct)
+	if err != nil {
+		return nil, err
+	}
+	rows := []versionsTable{}
+	for _, c := range ct {
+		candidates, ok := grouped[c]
+		if !ok {
+			continue
+		}
+		if latest, ok := latestVersion(candidates, deprecations); ok {
+			rows = append(rows, latest)
+		}
+		delete(grouped, c)
+	}
+	return parseDBVersions(rows, deprecations)
+}
+
+func (m *memStore) GetVersionsList(train, component string) ([]*models.ComponentVersion, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return parseDBVersions(m.versionsList(train, component), m.deprecations)
+}
+
+func (m *memStore) UpsertVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	releaseTimestamp, err := newTimestampFromStr(cv.Version.Released)
+	if err != nil {
+		return models.ComponentVersion{}, err
+	}
+	js, err := json.Marshal(cv.Version.Data)
+	if err != nil {
+		return models.ComponentVersion{}, err
+	}
+	newVsn := versionsTable{
+		ComponentName:    cv.Component.Name,
+		Train:            cv.Version.Train,
+		Version:          cv.Version.Version,
+		ReleaseTimestamp: releaseTimestamp,
+		Data:             string(js),
+	}
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	if idx := m.versionIndex(newVsn.ComponentName, newVsn.Train, newVsn.Version); idx >= 0 {
+		newVsn.VersionID = m.versions[idx].VersionID
+		m.versions[idx] = newVsn
+	} else {
+		newVsn.VersionID = strconv.Itoa(len(m.versions) + 1)
+		m.versions = append(m.versions, newVsn)
+	}
+	return parseDBVersion(newVsn, m.deprecations)
+}
+
+func (m *memStore) SetVersionDeprecation(train, component, version string, deprecation *models.Deprecation) (models.ComponentVersion, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	idx := m.versionIndex(component, train, version)
+	if idx < 0 {
+		return models.ComponentVersion{}, ErrNotFound
+	}
+	key := releaseKey{component: component, train: train, version: version}
+	if deprecation == nil {
+		delete(m.deprecations, key)
+	} else {
+		m.deprecations[key] = newReleaseDeprecation(key, deprecation, m.now())
+	}
+	return parseDBVersion(m.versions[idx], m.deprecations)
+}
+
+func (m *memStore) GetVersionCountsByTrain() (map[string]int, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	ret := make(map[string]int)
+	for _, row := range m.versions {
+		ret[row.Train]++
+	}
+	return ret, nil
+}
+
+func (m *memStore) GetDoctor(id string) (models.DoctorInfo, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return m.getDoctor(id)
+}
+
+func (m *memStore) getDoctor(id string) (models.DoctorInfo, error) {
+	row, ok := m.doctors[id]
+	if !ok {
+		return models.DoctorInfo{}, ErrNotFound
+	}
+	doctor, err := parseJSONDoctor([]byte(row.Data))
+	if err != nil {
+		return models.DoctorInfo{}, errParsingCluster{origErr: err}
+	}
+	return doctor, nil
+}
+
+func (m *memStore) UpsertDoctor(id string, doctor models.DoctorInfo) (models.DoctorInfo, error) {
+	js, err := json.Marshal(doctor)
+	if err != nil {
+		return models.DoctorInfo{}, err
+	}
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	m.doctors[id] = doctorTable{ReportID: id, Data: string(js)}
+	return m.getDoctor(id)
+}
+
+func (m *memStore) GetDoctorCount() (int, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	return len(m.doctors), nil
+}
+
+func (m *memStore) CreatePublisherToken(name string, components, trains []string) (string, PublisherToken, error) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	for _, row := range m.publisherTokens {
+		if row.Name == name {
+			return "", PublisherToken{}, ErrPublisherTokenExists
+		}
+	}
+	token, hash, err := newPublisherToken()
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	row, err := newPublisherTokensTable(hash, name, components, trains, m.now())
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	m.publisherTokens[hash] = row
+	pt, err := parsePublisherToken(row)
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	return token, pt, nil
+}
+
+func (m *memStore) GetPublisherToken(token string) (PublisherToken, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	row, ok := m.publisherTokens[hashPublisherToken(token)]
+	if !ok {
+		return PublisherToken{}, ErrNotFound
+	}
+	return parsePublisherToken(row)
+}
+
+func (m *memStore) ListPublisherTokens() ([]PublisherToken, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	ret := make([]PublisherToken, 0, len(m.publisherTokens))
+	for _, row := range m.publisherTokens {
+		pt, err := parsePublisherToken(row)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pt)
+	}
+	sort.Sort(publisherTokensByName(ret))
+	return ret, nil
+}
+
+type publisherTokensByName []PublisherToken
+
+func (p publisherTokensByName) Len() int           { return len(p) }
+func (p publisherTokensByName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p publisherTokensByName) Less(i, j int) bool { return p[i].Name < p[j].Name }
+
+func (m *memStore) RevokePublisherToken(name string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	for hash, row := range m.publisherTokens {
+		if row.Name == name {
+			delete(m.publisherTokens, hash)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *memStore) GetComponentAdoption(train, component string) (models.ComponentAdoption, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	counts := make(map[string]int64)
+	var total int64
+	for _, row := range m.clusters {
+		cluster, err := parseJSONCluster([]byte(row.Data))
+		if err != nil {
+			return models.ComponentAdoption{}, errParsingCluster{origErr: err}
+		}
+		versions := clusterComponentVersions(cluster, train, component)
+		for _, version := range versions {
+			counts[version]++
+		}
+		if len(versions) > 0 {
+			total++
+		}
+	}
+	return makeComponentAdoption(train, component, m.versionsList(train, component), counts, total), nil
+}
+
+func (m *memStore) GetReleaseAdoption(train, component, version string, until time.Time) (models.ReleaseAdoption, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	idx := m.versionIndex(component, train, version)
+	if idx < 0 {
+		return models.ReleaseAdoption{}, ErrNotFound
+	}
+	versionRow := m.versions[idx]
+	if err := checkBuckets(versionRow.ReleaseTimestamp.Time, until, BucketDay, MaxStatsBuckets); err != nil {
+		return models.ReleaseAdoption{}, err
+	}
+	// the set of clusters that reported the release on each day
+	clustersByDay := make(map[string]map[string]bool)
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return models.ReleaseAdoption{}, err
+	}
+	for _, span := range spans {
+		// a daily summary counts as a checkin at the first time the cluster checked in that day
+		createdAt := span.firstSeen
+		if createdAt.Before(versionRow.ReleaseTimestamp.Time) || !createdAt.Before(until) {
+			continue
+		}
+		cluster, err := parseJSONCluster([]byte(span.data))
+		if err != nil {
+			return models.ReleaseAdoption{}, errParsingCluster{origErr: err}
+		}
+		for _, vsn := range clusterComponentVersions(cluster, train, component) {
+			if vsn != version {
+				continue
+			}
+			date := createdAt.UTC().Format(statsDateFmt)
+			if clustersByDay[date] == nil {
+				clustersByDay[date] = make(map[string]bool)
+			}
+			clustersByDay[date][span.clusterID] = true
+		}
+	}
+	counts := make(map[string]int64, len(clustersByDay))
+	for date, clusters := range clustersByDay {
+		counts[date] = int64(len(clusters))
+	}
+	return makeReleaseAdoption(versionRow, until, counts), nil
+}
+
+func (m *memStore) GetActiveClusters(query *ActiveClustersQuery) (models.ActiveClusters, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	// the set of clusters that checked in during each bucket
+	clustersByBucket := make(map[string]map[string]bool)
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return models.ActiveClusters{}, err
+	}
+	for _, span := range spans {
+		if span.lastSeen.Before(query.Since) || !span.firstSeen.Before(query.Until) {
+			continue
+		}
+		bucket := query.Interval.truncate(span.firstSeen).Format(statsDateFmt)
+		if clustersByBucket[bucket] == nil {
+			clustersByBucket[bucket] = make(map[string]bool)
+		}
+		clustersByBucket[bucket][span.clusterID] = true
+	}
+	counts := make(map[string]int64, len(clustersByBucket))
+	for bucket, clusters := range clustersByBucket {
+		counts[bucket] = int64(len(clusters))
+	}
+	return makeActiveClusters(query, counts), nil
+}
+
+func (m *memStore) GetActiveClusterCount(since time.Time) (int, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return 0, err
+	}
+	clusters := make(map[string]bool)
+	for _, span := range spans {
+		if !span.lastSeen.Before(since) {
+			clusters[span.clusterID] = true
+		}
+	}
+	return len(clusters), nil
+}
+
+// activityEventsByOccurredAt sorts activity events by their occurred_at time. m.activityEvents is in
+// event_id order, so a stable sort orders them the same way as the gorm implementation does
+type activityEventsByOccurredAt []clusterActivityEventsTable
+
+func (a activityEventsByOccurredAt) Len() int      { return len(a) }
+func (a activityEventsByOccurredAt) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a activityEventsByOccurredAt) Less(i, j int) bool {
+	// occurredAt was validated by BeforeSave when the event was recorded
+	iTime, _ := a[i].occurredAtTime()
+	jTime, _ := a[j].occurredAtTime()
+	return iTime.Before(jTime)
+}
+
+func (m *memStore) GetClusterChurn(query *ClusterChurnQuery) (models.ClusterChurn, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return models.ClusterChurn{}, err
+	}
+	firstSeen := make(map[string]time.Time)
+	for _, span := range spans {
+		if !span.firstSeen.Before(query.Until) {
+			continue
+		}
+		if seen, ok := firstSeen[span.clusterID]; !ok || span.firstSeen.Before(seen) {
+			firstSeen[span.clusterID] = span.firstSeen
+		}
+	}
+	events := []clusterActivityEventsTable{}
+	for _, event := range m.activityEvents {
+		occurredAt, err := event.occurredAtTime()
+		if err != nil {
+			return models.ClusterChurn{}, err
+		}
+		if occurredAt.Before(query.Until) {
+			events = append(events, event)
+		}
+	}
+	sort.Stable(activityEventsByOccurredAt(events))
+	return makeClusterChurn(query, firstSeen, events)
+}
+
+func (m *memStore) GetClusterCohorts(query *ClusterCohortsQuery) (models.ClusterCohorts, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	spans, err := m.checkinSpans()
+	if err != nil {
+		return models.ClusterCohorts{}, err
+	}
+	periods := make(map[string][]time.Time)
+	firstSeen := make(map[string]time.Time)
+	for _, span := range spans {
+		if !span.firstSeen.Before(query.Until) {
+			continue
+		}
+		periods[span.clusterID] = append(periods[span.clusterID], query.Interval.truncate(span.firstSeen))
+		if seen, ok := firstSeen[span.clusterID]; !ok || span.firstSeen.Before(seen) {
+			firstSeen[span.clusterID] = span.firstSeen
+		}
+	}
+	for clusterID, seen := range firstSeen {
+		if seen.Before(query.cohortStart()) {
+			delete(periods, clusterID)
+		}
+	}
+	return makeClusterCohorts(query, periods), nil
+}
+
+// CheckHealth runs the same checks as the gorm implementation. They always pass, since there's no
+// database
+func (m *memStore) CheckHealth(timeout time.Duration) []HealthCheck {
+	return []HealthCheck{
+		{Name: HealthCheckDatabase},
+		{Name: HealthCheckSchema},
+		{Name: HealthCheckMigrations},
+	}
+}
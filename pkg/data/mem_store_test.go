@@ -0,0 +1,138 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func TestMemStoreClusterRoundTrip(t *testing.T) {
+	testStoreClusterRoundTrip(t, NewMemStore())
+}
+
+func TestMemStoreConcurrentCheckins(t *testing.T) {
+	testStoreConcurrentCheckins(t, NewMemStore())
+}
+
+func TestMemStoreFilterClustersByAge(t *testing.T) {
+	testStoreFilterClustersByAge(t, NewMemStore())
+}
+
+func TestMemStoreFilterClustersByAgePages(t *testing.T) {
+	testStoreFilterClustersByAgePages(t, NewMemStore())
+}
+
+func TestMemStoreExportClustersByAge(t *testing.T) {
+	testStoreExportClustersByAge(t, NewMemStore())
+}
+
+func TestMemStoreVersions(t *testing.T) {
+	testStoreVersions(t, NewMemStore())
+}
+
+func TestMemStoreVersionCountsByTrain(t *testing.T) {
+	testStoreVersionCountsByTrain(t, NewMemStore())
+}
+
+func TestMemStoreVersionDeprecation(t *testing.T) {
+	testStoreVersionDeprecation(t, NewMemStore())
+}
+
+func TestMemStoreClusterCheckinHistory(t *testing.T) {
+	testStoreClusterCheckinHistory(t, NewMemStore())
+}
+
+func TestMemStoreGetClusterCheckin(t *testing.T) {
+	testStoreGetClusterCheckin(t, NewMemStore())
+}
+
+func TestMemStoreCompactCheckins(t *testing.T) {
+	testStoreCompactCheckins(t, NewMemStore())
+}
+
+func TestMemStoreClusterChurn(t *testing.T) {
+	testStoreClusterChurn(t, NewMemStore())
+}
+
+func TestMemStoreClusterCohorts(t *testing.T) {
+	testStoreClusterCohorts(t, NewMemStore())
+}
+
+func TestMemStoreActiveClusterCount(t *testing.T) {
+	testStoreActiveClusterCount(t, NewMemStore())
+}
+
+func TestMemStoreDoctorRoundTrip(t *testing.T) {
+	testStoreDoctorRoundTrip(t, NewMemStore())
+}
+
+func TestMemStorePublisherTokens(t *testing.T) {
+	testStorePublisherTokens(t, NewMemStore())
+}
+
+func TestMemStoreFilterCheckins(t *testing.T) {
+	store := NewMemStore()
+	now := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	store.(*memStore).now = func() time.Time { return now }
+	testStoreFilterClusterCheckins(t, store, now)
+	// the time since the last checkin is relative to the store's clock, which is fixed here
+	filter, err := NewClusterCheckinsFilter(now.Add(-100*time.Hour), now)
+	assert.NoErr(t, err)
+	results, _, err := store.FilterClusterCheckins(filter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(results), 3, "number of results")
+	assert.Equal(t, results[2].LastCheckin, "-02:00:00", "last checkin")
+}
+
+func TestMemStoreCompactCheckinsDeletesOrphanedSnapshots(t *testing.T) {
+	store := NewMemStore()
+	today := truncateToDay(time.Now())
+	old := testCluster()
+	old.Components[0].Version.Version = "1.0.0"
+	assert.NoErr(t, store.CheckInCluster(clusterID, today.AddDate(0, 0, -2), old))
+	assert.NoErr(t, store.CheckInCluster(clusterID, today.Add(time.Minute), testCluster()))
+	assert.Equal(t, len(store.(*memStore).snapshots), 2, "number of snapshots")
+	_, err := store.CompactCheckins(today)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(store.(*memStore).snapshots), 1, "number of snapshots")
+}
+
+func TestMemStoreClock(t *testing.T) {
+	store := NewMemStore()
+	now := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	store.(*memStore).now = func() time.Time { return now }
+
+	_, err := store.UpsertCluster(clusterID, models.Cluster{ID: clusterID})
+	assert.NoErr(t, err)
+	checkin, err := store.GetClusterCheckinAt(clusterID, now)
+	assert.NoErr(t, err)
+	assert.True(t, time.Time(checkin.CheckedInAt).Equal(now), "checkin time %s wasn't %s", checkin.CheckedInAt, now)
+	_, err = store.GetClusterCheckinAt(clusterID, now.Add(-time.Second))
+	assert.Equal(t, err, ErrNotFound, "error for a time before the checkin")
+
+	_, token, err := store.CreatePublisherToken("ci", nil, nil)
+	assert.NoErr(t, err)
+	assert.True(t, token.CreatedAt.Equal(now), "token creation time %s wasn't %s", token.CreatedAt, now)
+}
+
+func TestMemStoreFilterCheckinsPages(t *testing.T) {
+	testStoreFilterClusterCheckinsPages(t, NewMemStore())
+}
+
+func TestMemStoreComponentAdoption(t *testing.T) {
+	testStoreComponentAdoption(t, NewMemStore())
+}
+
+func TestMemStoreReleaseAdoption(t *testing.T) {
+	testStoreReleaseAdoption(t, NewMemStore())
+}
+
+func TestMemStoreActiveClusters(t *testing.T) {
+	testStoreActiveClusters(t, NewMemStore())
+}
+
+func TestMemStoreCompactedCheckinStats(t *testing.T) {
+	testStoreCompactedCheckinStats(t, NewMemStore())
+}
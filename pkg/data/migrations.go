@@ -0,0 +1,486 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	schemaMigrationsTableName         = "schema_migrations"
+	schemaMigrationsTableVersionKey   = "version"
+	schemaMigrationsTableDescKey      = "description"
+	schemaMigrationsTableAppliedAtKey = "applied_at"
+
+	postgresDialect = "postgres"
+)
+
+// execer is the interface for running a single SQL statement. Both *sql.DB and *sql.Tx implement it
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// migration is a single, numbered change to the database schema. Migrations are run in version
+// order, each in its own transaction, and each one that's run is recorded in the
+// schema_migrations table. Once a migration has been released, it must never be changed or
+// renumbered. Instead, add a new migration to the end of the migrations slice
+type migration struct {
+	version     int
+	description string
+	// postgresOnly indicates that up and down are no-ops on other databases (i.e. the in-memory
+	// sqlite DB used in tests). The migration is still recorded as applied
+	postgresOnly bool
+	// up and down are passed the name of the database dialect (i.e. "postgres" or "sqlite3")
+	up   func(tx execer, dialect string) error
+	down func(tx execer, dialect string) error
+}
+
+// migrations is the ordered list of all schema migrations. The version of the last migration is
+// the schema version that this code expects
+var migrations = []migration{
+	{
+		version: 1,
+		// the tables use CREATE TABLE IF NOT EXISTS so that databases created before migrations
+		// existed are adopted at this version without changes
+		description: "create versions, clusters, clusters_checkins and doctors tables",
+		up: func(tx execer, dialect string) error {
+			if _, err := createVersionsTable(tx); err != nil {
+				return err
+			}
+			if _, err := createClustersTable(tx); err != nil {
+				return err
+			}
+			if _, err := createClustersCheckinsTable(tx, dialect); err != nil {
+				return err
+			}
+			_, err := createDoctorTable(tx)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, doctorTableName, clustersCheckinsTableName, clustersTableName, versionsTableName)
+		},
+	},
+	{
+		version:      2,
+		description:  "widen versions.component_name and versions.train to varchar(64)",
+		postgresOnly: true,
+		up: func(tx execer, dialect string) error {
+			return alterVersionsColumnWidths(tx, 64, 64)
+		},
+		down: func(tx execer, dialect string) error {
+			return alterVersionsColumnWidths(tx, 32, 24)
+		},
+	},
+	{
+		version:     3,
+		description: "create publisher_tokens table",
+		up: func(tx execer, dialect string) error {
+			_, err := createPublisherTokensTable(tx)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, publisherTokensTableName)
+		},
+	},
+	{
+		version:     4,
+		description: "create clusters_checkins_daily table",
+		up: func(tx execer, dialect string) error {
+			_, err := createClustersCheckinsDailyTable(tx)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, clustersCheckinsDailyTableName)
+		},
+	},
+	{
+		version:     5,
+		description: "create cluster_snapshots table and add clusters_checkins.snapshot_hash",
+		up: func(tx execer, dialect string) error {
+			if _, err := createClusterSnapshotsTable(tx); err != nil {
+				return err
+			}
+			_, err := addClustersCheckinsSnapshotHashColumn(tx)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			// sqlite can't drop columns. there, the column is dropped along with the
+			// clusters_checkins table when migration 1 is reverted
+			if dialect == postgresDialect {
+				if err := dropClustersCheckinsSnapshotHashColumn(tx); err != nil {
+					return err
+				}
+			}
+			return dropTables(tx, clusterSnapshotsTableName)
+		},
+	},
+	{
+		version:     6,
+		description: "create cluster_activity_events table",
+		up: func(tx execer, dialect string) error {
+			_, err := createClusterActivityEventsTable(tx, dialect)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, clusterActivityEventsTableName)
+		},
+	},
+	{
+		version:     7,
+		description: "create release_deprecations table",
+		up: func(tx execer, dialect string) error {
+			_, err := createReleaseDeprecationsTable(tx)
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, releaseDeprecationsTableName)
+		},
+	},
+	{
+		version:     8,
+		description: "create cluster_dormancy and dormancy_watermark tables",
+		up: func(tx execer, dialect string) error {
+			if _, err := createClusterDormancyTable(tx); err != nil {
+				return err
+			}
+			if err := createDormancyWatermarkTable(tx); err != nil {
+				return err
+			}
+			// dormancy detection used to only record each cluster's latest gap. It starts over from
+			// the watermark, which is before every checkin, and records all of them again
+			_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", clusterActivityEventsTableName))
+			return err
+		},
+		down: func(tx execer, dialect string) error {
+			return dropTables(tx, dormancyWatermarkTableName, clusterDormancyTableName)
+		},
+	},
+}
+
+// LatestSchemaVersion returns the schema version that this code expects the database to be at
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// ErrSchemaTooNew is the error returned when the database has had migrations applied that this
+// code doesn't know about. This usually means an older build is running against a database that
+// a newer build has already migrated
+type ErrSchemaTooNew struct {
+	DBVersion   int
+	CodeVersion int
+}
+
+// Error is the error interface implementation
+func (e ErrSchemaTooNew) Error() string {
+	return fmt.Sprintf(
+		"database schema version %d is newer than the latest version (%d) this code knows about",
+		e.DBVersion,
+		e.CodeVersion,
+	)
+}
+
+type errUnknownMigration struct {
+	version int
+}
+
+func (e errUnknownMigration) Error() string {
+	return fmt.Sprintf("no migration with version %d", e.version)
+}
+
+// errMigrationFailed is the error returned when one of a migration's statements fails, as opposed
+// to the transaction that it runs in
+type errMigrationFailed struct {
+	version int
+	up      bool
+	err     error
+}
+
+func (e errMigrationFailed) Error() string {
+	direction := "down from"
+	if e.up {
+		direction = "up to"
+	}
+	return fmt.Sprintf("migrating %s %d failed (%s)", direction, e.version, e.err)
+}
+
+// MigrationStatus describes a single migration and whether it has been applied to a database
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Applied     bool
+	// AppliedAt is the time the migration was applied. It's the zero time if Applied is false
+	AppliedAt time.Time
+}
+
+func dropTables(tx execer, tableNames ...string) error {
+	for _, tableName := range tableNames {
+		if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func alterVersionsColumnWidths(tx execer, componentNameWidth, trainWidth int) error {
+	_, err := tx.Exec(fmt.Sprintf(
+		"ALTER TABLE %s ALTER COLUMN %s TYPE varchar(%d), ALTER COLUMN %s TYPE varchar(%d)",
+		versionsTableName,
+		versionsTableComponentNameKey,
+		componentNameWidth,
+		versionsTableTrainKey,
+		trainWidth,
+	))
+	return err
+}
+
+func createSchemaMigrationsTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s integer PRIMARY KEY, %s varchar(128), %s timestamp )",
+		schemaMigrationsTableName,
+		schemaMigrationsTableVersionKey,
+		schemaMigrationsTableDescKey,
+		schemaMigrationsTableAppliedAtKey,
+	))
+}
+
+// schemaMigrationsTable type that expresses the `schema_migrations` postgres table schema
+type schemaMigrationsTable struct {
+	Version     int       `gorm:"primary_key;column:version"`
+	Description string    `gorm:"column:description"`
+	AppliedAt   Timestamp `gorm:"column:applied_at;type:timestamp"`
+}
+
+func (s schemaMigrationsTable) TableName() string {
+	return schemaMigrationsTableName
+}
+
+// appliedMigrations returns the applied time of each migration version in the schema_migrations
+// table, creating the table first if it doesn't exist
+func appliedMigrations(db *gorm.DB) (map[int]time.Time, error) {
+	if _, err := createSchemaMigrationsTable(db.CommonDB()); err != nil {
+		return nil, err
+	}
+	rows := []schemaMigrationsTable{}
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	ret := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		ret[row.Version] = row.AppliedAt.Time
+	}
+	return ret, nil
+}
+
+// SchemaVersion returns the version of the newest migration that has been applied to db, or 0 if
+// none have been applied
+func SchemaVersion(db *gorm.DB) (int, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return 0, err
+	}
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+// checkSchemaNotTooNew returns ErrSchemaTooNew if db has a newer schema version than this code
+func checkSchemaNotTooNew(db *gorm.DB) error {
+	current, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if latest := LatestSchemaVersion(); current > latest {
+		return ErrSchemaTooNew{DBVersion: current, CodeVersion: latest}
+	}
+	return nil
+}
+
+func checkMigrationVersion(version int) error {
+	if version == 0 {
+		return nil
+	}
+	for _, m := range migrations {
+		if m.version == version {
+			return nil
+		}
+	}
+	return errUnknownMigration{version: version}
+}
+
+// runMigration runs a single migration step inside a transaction, and records it in (or removes
+// it from) the schema_migrations table in the same transaction. Only failures to begin, commit or
+// roll back the transaction are returned as txErrs. A failed statement is returned as an
+// errMigrationFailed
+func runMigration(db *gorm.DB, m migration, up bool) error {
+	op := fmt.Sprintf("migrate down from %d", m.version)
+	fn := m.down
+	if up {
+		op = fmt.Sprintf("migrate up to %d", m.version)
+		fn = m.up
+	}
+	tx := db.Begin()
+	if tx.Error != nil {
+		return txErr{orig: nil, err: tx.Error, op: op}
+	}
+	var err error
+	dialect := db.Dialect().GetName()
+	if !m.postgresOnly || dialect == postgresDialect {
+		err = fn(tx.CommonDB(), dialect)
+	}
+	if err == nil {
+		record := schemaMigrationsTable{Version: m.version}
+		if up {
+			record.Description = m.description
+			record.AppliedAt = Timestamp{Time: time.Now()}
+			err = tx.Create(&record).Error
+		} else {
+			err = tx.Delete(&record).Error
+		}
+	}
+	if err != nil {
+		err = errMigrationFailed{version: m.version, up: up, err: err}
+		if rbErr := tx.Rollback().Error; rbErr != nil {
+			return txErr{orig: err, err: rbErr, op: op}
+		}
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return txErr{orig: nil, err: err, op: op}
+	}
+	return nil
+}
+
+// lockMigrations takes the migrations advisory lock, so that replicas that start at the same time
+// don't apply the same migrations. The lock is held by its own transaction, since each migration
+// runs in a separate one, so db must be able to open a second connection. The returned func
+// releases the lock
+func lockMigrations(db *gorm.DB) (func(), error) {
+	if db.Dialect().GetName() != postgresDialect {
+		return func() {}, nil
+	}
+	lock := db.Begin()
+	if lock.Error != nil {
+		return nil, txErr{orig: nil, err: lock.Error, op: "lock migrations"}
+	}
+	if err := lockTx(lock, migrationsLockKey); err != nil {
+		if rbErr := lock.Rollback().Error; rbErr != nil {
+			return nil, txErr{orig: err, err: rbErr, op: "lock migrations"}
+		}
+		return nil, txErr{orig: nil, err: err, op: "lock migrations"}
+	}
+	return func() {
+		// the lock transaction doesn't write anything, so there's nothing to commit
+		if err := lock.Rollback().Error; err != nil {
+			log.Printf("error releasing the migrations lock (%s)", err)
+		}
+	}, nil
+}
+
+// MigrateUp applies, in order, every migration that hasn't yet been applied to db, up to and
+// including the migration with version to. Pass LatestSchemaVersion() to apply all migrations.
+// Returns ErrSchemaTooNew without changing anything if db is already newer than this code. On
+// Postgres, it holds an advisory lock while it runs, so concurrent calls from other replicas wait
+// and then find the migrations already applied
+func MigrateUp(db *gorm.DB, to int) error {
+	if err := checkMigrationVersion(to); err != nil {
+		return err
+	}
+	unlock, err := lockMigrations(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := checkSchemaNotTooNew(db); err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version > to {
+			break
+		}
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+		log.Printf("applying migration %d (%s)", m.version, m.description)
+		if err := runMigration(db, m, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateDown reverts, in reverse order, every applied migration with a version greater than to.
+// Pass 0 to revert all migrations. Returns ErrSchemaTooNew without changing anything if db is newer
+// than this code, since this code doesn't know how to revert the newer migrations. Like MigrateUp,
+// it holds an advisory lock on Postgres
+func MigrateDown(db *gorm.DB, to int) error {
+	if err := checkMigrationVersion(to); err != nil {
+		return err
+	}
+	unlock, err := lockMigrations(db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := checkSchemaNotTooNew(db); err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version <= to {
+			break
+		}
+		if _, ok := applied[m.version]; !ok {
+			continue
+		}
+		log.Printf("reverting migration %d (%s)", m.version, m.description)
+		if err := runMigration(db, m, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMigrationStatus returns the status of every migration, in version order. Migrations that are
+// recorded in db but unknown to this code are included at the end, with an empty description
+func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt, ok := applied[m.version]
+		ret = append(ret, MigrationStatus{
+			Version:     m.version,
+			Description: m.description,
+			Applied:     ok,
+			AppliedAt:   appliedAt,
+		})
+		delete(applied, m.version)
+	}
+	unknown := make([]int, 0, len(applied))
+	for version := range applied {
+		unknown = append(unknown, version)
+	}
+	sort.Ints(unknown)
+	for _, version := range unknown {
+		ret = append(ret, MigrationStatus{Version: version, Applied: true, AppliedAt: applied[version]})
+	}
+	return ret, nil
+}
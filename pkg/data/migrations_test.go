@@ -0,0 +1,82 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func TestMigrateUpAndDown(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	version, err := SchemaVersion(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, version, LatestSchemaVersion(), "schema version")
+	// migrating up again should be a no-op
+	assert.NoErr(t, MigrateUp(db, LatestSchemaVersion()))
+
+	assert.NoErr(t, MigrateDown(db, 0))
+	version, err = SchemaVersion(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, version, 0, "schema version")
+	_, err = GetClusterCount(db)
+	assert.True(t, err != nil, "clusters table exists after migrating down")
+
+	assert.NoErr(t, MigrateUp(db, 1))
+	statuses, err := GetMigrationStatus(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(statuses), len(migrations), "number of migration statuses")
+	for i, status := range statuses {
+		assert.Equal(t, status.Version, migrations[i].version, "migration version")
+		assert.Equal(t, status.Applied, status.Version <= 1, "migration applied")
+	}
+	count, err := GetClusterCount(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, count, 0, "cluster count")
+}
+
+func TestMigrateUnknownVersion(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	unknown := LatestSchemaVersion() + 1
+	assert.Equal(t, MigrateUp(db, unknown), errUnknownMigration{version: unknown}, "returned error")
+	assert.Equal(t, MigrateDown(db, unknown), errUnknownMigration{version: unknown}, "returned error")
+}
+
+func TestSchemaTooNew(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	newer := LatestSchemaVersion() + 1
+	assert.NoErr(t, db.Create(&schemaMigrationsTable{Version: newer, Description: "from the future"}).Error)
+
+	expectedErr := ErrSchemaTooNew{DBVersion: newer, CodeVersion: LatestSchemaVersion()}
+	assert.Equal(t, VerifyPersistentStorage(db), expectedErr, "returned error")
+	assert.Equal(t, MigrateDown(db, 0), expectedErr, "returned error")
+	statuses, err := GetMigrationStatus(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(statuses), len(migrations)+1, "number of migration statuses")
+	assert.Equal(t, statuses[len(statuses)-1].Version, newer, "unknown migration version")
+	assert.True(t, statuses[len(statuses)-1].Applied, "unknown migration wasn't reported as applied")
+}
+
+func TestRunMigrationStatementError(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	version := LatestSchemaVersion() + 1
+	m := migration{
+		version:     version,
+		description: "fails",
+		up: func(tx execer, dialect string) error {
+			_, err := tx.Exec("SELECT * FROM not_a_table")
+			return err
+		},
+	}
+	err = runMigration(db, m, true)
+	failed, ok := err.(errMigrationFailed)
+	assert.True(t, ok, "returned error %#v wasn't an errMigrationFailed", err)
+	assert.Equal(t, failed.version, version, "migration version")
+	// the failed migration isn't recorded as applied
+	dbVersion, err := SchemaVersion(db)
+	assert.NoErr(t, err)
+	assert.Equal(t, dbVersion, LatestSchemaVersion(), "schema version")
+}
@@ -0,0 +1,173 @@
+package data
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// publisherTokenNumBytes is the number of random bytes in a publisher token. Tokens are hex
+	// encoded, so they're twice this many characters long
+	publisherTokenNumBytes = 32
+)
+
+var (
+	// ErrPublisherTokenExists is returned when creating a publisher token with a name that's
+	// already in use
+	ErrPublisherTokenExists = ErrConflict{msg: "a publisher token with that name already exists"}
+)
+
+// PublisherToken is an API token that allows its holder to publish component releases. Only the
+// SHA-256 hash of each token is stored, so the token itself can't be recovered after it's created
+type PublisherToken struct {
+	// Name is the unique, human readable name of the token (i.e. the name of the CI job that uses it)
+	Name string `json:"name"`
+	// Components is the list of components that the token can publish releases for. If it's
+	// empty, the token can publish releases for all components
+	Components []string `json:"components"`
+	// Trains is the list of trains that the token can publish releases to. If it's empty, the
+	// token can publish releases to all trains
+	Trains    []string  `json:"trains"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CanPublish returns true if t is allowed to publish releases for the given component to the
+// given train
+func (t PublisherToken) CanPublish(train, component string) bool {
+	return scopeAllows(t.Trains, train) && scopeAllows(t.Components, component)
+}
+
+func scopeAllows(scope []string, val string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	for _, s := range scope {
+		if s == val {
+			return true
+		}
+	}
+	return false
+}
+
+// publisherTokenScope is the JSON encoded scope of a token, as stored in the data column of the
+// publisher_tokens table
+type publisherTokenScope struct {
+	Components []string `json:"components"`
+	Trains     []string `json:"trains"`
+}
+
+// newPublisherToken returns a new random publisher token and its hash
+func newPublisherToken() (string, string, error) {
+	b := make([]byte, publisherTokenNumBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashPublisherToken(token), nil
+}
+
+func hashPublisherToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newPublisherTokensTable(hash, name string, components, trains []string, createdAt time.Time) (publisherTokensTable, error) {
+	js, err := json.Marshal(publisherTokenScope{Components: components, Trains: trains})
+	if err != nil {
+		return publisherTokensTable{}, err
+	}
+	return publisherTokensTable{
+		TokenHash: hash,
+		Name:      name,
+		CreatedAt: Timestamp{Time: createdAt},
+		Data:      string(js),
+	}, nil
+}
+
+func parsePublisherToken(row publisherTokensTable) (PublisherToken, error) {
+	scope := publisherTokenScope{}
+	if err := json.Unmarshal([]byte(row.Data), &scope); err != nil {
+		return PublisherToken{}, err
+	}
+	return PublisherToken{
+		Name:       row.Name,
+		Components: scope.Components,
+		Trains:     scope.Trains,
+		CreatedAt:  row.CreatedAt.Time,
+	}, nil
+}
+
+// CreatePublisherToken creates a new publisher token with the given name and scope, and returns
+// the token itself. This is the only time the token is available, so it must be given to the
+// publisher immediately. Returns ErrPublisherTokenExists if there's already a token with name
+func CreatePublisherToken(db *gorm.DB, name string, components, trains []string) (string, PublisherToken, error) {
+	var numExisting int
+	countDB := db.Model(&publisherTokensTable{}).Where(&publisherTokensTable{Name: name}).Count(&numExisting)
+	if countDB.Error != nil {
+		return "", PublisherToken{}, countDB.Error
+	}
+	if numExisting > 0 {
+		return "", PublisherToken{}, ErrPublisherTokenExists
+	}
+	token, hash, err := newPublisherToken()
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	row, err := newPublisherTokensTable(hash, name, components, trains, time.Now())
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	if err := db.Create(&row).Error; err != nil {
+		return "", PublisherToken{}, err
+	}
+	pt, err := parsePublisherToken(row)
+	if err != nil {
+		return "", PublisherToken{}, err
+	}
+	return token, pt, nil
+}
+
+// GetPublisherToken returns the publisher token whose hash matches token's hash. Returns
+// ErrNotFound if there's no such token
+func GetPublisherToken(db *gorm.DB, token string) (PublisherToken, error) {
+	row := publisherTokensTable{}
+	if err := db.Where(&publisherTokensTable{TokenHash: hashPublisherToken(token)}).First(&row).Error; err != nil {
+		return PublisherToken{}, err
+	}
+	return parsePublisherToken(row)
+}
+
+// ListPublisherTokens returns all publisher tokens, ordered by name
+func ListPublisherTokens(db *gorm.DB) ([]PublisherToken, error) {
+	rows := []publisherTokensTable{}
+	if err := db.Order(publisherTokensTableNameKey).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	ret := make([]PublisherToken, len(rows))
+	for i, row := range rows {
+		pt, err := parsePublisherToken(row)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = pt
+	}
+	return ret, nil
+}
+
+// RevokePublisherToken deletes the publisher token with the given name, so that it can no longer
+// be used. Returns ErrNotFound if there's no such token
+func RevokePublisherToken(db *gorm.DB, name string) error {
+	deleteDB := db.Where(&publisherTokensTable{Name: name}).Delete(&publisherTokensTable{})
+	if deleteDB.Error != nil {
+		return deleteDB.Error
+	}
+	if deleteDB.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
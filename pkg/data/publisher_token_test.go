@@ -0,0 +1,43 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func TestPublisherTokenCanPublish(t *testing.T) {
+	type testCase struct {
+		token     PublisherToken
+		train     string
+		component string
+		expected  bool
+	}
+	testCases := []testCase{
+		testCase{token: PublisherToken{}, train: "stable", component: "deis-router", expected: true},
+		testCase{token: PublisherToken{Trains: []string{"beta"}}, train: "stable", component: "deis-router", expected: false},
+		testCase{token: PublisherToken{Trains: []string{"beta", "stable"}}, train: "stable", component: "deis-router", expected: true},
+		testCase{token: PublisherToken{Components: []string{"deis-builder"}}, train: "stable", component: "deis-router", expected: false},
+		testCase{
+			token:     PublisherToken{Components: []string{"deis-router"}, Trains: []string{"stable"}},
+			train:     "stable",
+			component: "deis-router",
+			expected:  true,
+		},
+	}
+	for i, tc := range testCases {
+		assert.Equal(t, tc.token.CanPublish(tc.train, tc.component), tc.expected, fmt.Sprintf("CanPublish result for test case %d", i))
+	}
+}
+
+func TestPublisherTokenHashed(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	token, _, err := CreatePublisherToken(db, "ci", nil, nil)
+	assert.NoErr(t, err)
+	row := publisherTokensTable{}
+	assert.NoErr(t, db.Where(&publisherTokensTable{Name: "ci"}).First(&row).Error)
+	assert.True(t, row.TokenHash != token, "token was stored in plain text")
+	assert.Equal(t, row.TokenHash, hashPublisherToken(token), "token hash")
+}
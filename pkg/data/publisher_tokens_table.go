@@ -0,0 +1,37 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	publisherTokensTableName         = "publisher_tokens"
+	publisherTokensTableHashKey      = "token_hash"
+	publisherTokensTableNameKey      = "name"
+	publisherTokensTableCreatedAtKey = "created_at"
+	publisherTokensTableDataKey      = "data"
+)
+
+// publisherTokensTable type that expresses the `publisher_tokens` postgres table schema
+type publisherTokensTable struct {
+	TokenHash string    `gorm:"primary_key;column:token_hash"` // PRIMARY KEY
+	Name      string    `gorm:"column:name;unique"`
+	CreatedAt Timestamp `gorm:"column:created_at;type:timestamp"`
+	Data      string    `gorm:"column:data;type:json"`
+}
+
+func (p publisherTokensTable) TableName() string {
+	return publisherTokensTableName
+}
+
+func createPublisherTokensTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s char(64) PRIMARY KEY, %s varchar(64) UNIQUE, %s timestamp, %s json )",
+		publisherTokensTableName,
+		publisherTokensTableHashKey,
+		publisherTokensTableNameKey,
+		publisherTokensTableCreatedAtKey,
+		publisherTokensTableDataKey,
+	))
+}
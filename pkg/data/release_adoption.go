@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+// statsDateFmt is the format of the days in the stats responses
+const statsDateFmt = "2006-01-02"
+
+// MaxStatsBuckets is the maximum number of days, weeks or months that a stats query can return.
+// The stats endpoints don't require authentication, so this keeps a request with a long time span
+// from using up the server's memory and the database's time
+const MaxStatsBuckets = 1000
+
+// errTooManyBuckets is the error returned when a stats query's time span has more than max
+// buckets of interval
+type errTooManyBuckets struct {
+	since    time.Time
+	until    time.Time
+	interval BucketInterval
+	max      int
+}
+
+func (e errTooManyBuckets) Error() string {
+	return fmt.Sprintf(
+		"%s to %s is more than %d %ss. use a shorter time span",
+		e.since.UTC().Format(statsDateFmt),
+		e.until.UTC().Format(statsDateFmt),
+		e.max,
+		e.interval,
+	)
+}
+
+// checkBuckets returns errTooManyBuckets if more than max of interval's buckets overlap
+// [since, until)
+func checkBuckets(since, until time.Time, interval BucketInterval, max int) error {
+	n := 0
+	for start := interval.truncate(since); start.Before(until); start = interval.next(start) {
+		n++
+		if n > max {
+			return errTooManyBuckets{since: since, until: until, interval: interval, max: max}
+		}
+	}
+	return nil
+}
+
+// releaseAdoptionResponse type that represents a row of the release adoption query
+type releaseAdoptionResponse struct {
+	Date     string `gorm:"column_name:date"`
+	Clusters int64  `gorm:"column_name:clusters"`
+}
+
+// truncateToDay returns the start of t's UTC day
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// makeReleaseAdoption returns the adoption series for version, with one entry for each UTC day from
+// the day it was released through the day of the last instant before until. counts is the number
+// of clusters that reported the release on each day, keyed by the day formatted with
+// statsDateFmt. Days that aren't in counts have a count of 0
+func makeReleaseAdoption(version versionsTable, until time.Time, counts map[string]int64) models.ReleaseAdoption {
+	ret := models.ReleaseAdoption{
+		Component: version.ComponentName,
+		Train:     version.Train,
+		Version:   version.Version,
+		Released:  version.ReleaseTimestamp.String(),
+		Data:      []*models.DailyAdoption{},
+	}
+	last := truncateToDay(until.Add(-time.Nanosecond))
+	for day := truncateToDay(version.ReleaseTimestamp.Time); !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(statsDateFmt)
+		count := counts[date]
+		ret.Data = append(ret.Data, &models.DailyAdoption{Date: date, Clusters: &count})
+	}
+	return ret
+}
+
+// GetReleaseAdoption returns the number of clusters that reported the given release in a checkin
+// on each day from the day it was released onward. Only checkins before until are counted, and a
+// day of compacted checkins counts as a checkin at the first time the cluster checked in that day.
+// Returns ErrNotFound if the release doesn't exist, and errTooManyBuckets if the series would have
+// more than MaxStatsBuckets days. This query uses Postgres' JSON functions, so it doesn't work on
+// other databases
+func GetReleaseAdoption(db *gorm.DB, train, component, version string, until time.Time) (models.ReleaseAdoption, error) {
+	var versionRow versionsTable
+	versionDB := db.Where(versionsTable{ComponentName: component, Train: train, Version: version}).First(&versionRow)
+	if versionDB.Error != nil {
+		return models.ReleaseAdoption{}, versionDB.Error
+	}
+	if err := checkBuckets(versionRow.ReleaseTimestamp.Time, until, BucketDay, MaxStatsBuckets); err != nil {
+		return models.ReleaseAdoption{}, err
+	}
+
+	var rows []releaseAdoptionResponse
+	countsDB := db.Raw(checkinSnapshotsSQL+`SELECT to_char(checkin_snapshots.created_at, 'YYYY-MM-DD') AS date,
+		COUNT(DISTINCT checkin_snapshots.cluster_id) AS clusters
+		`+componentsFromSQL(checkinSnapshotsTableName)+`
+		WHERE component->'component'->>'name' = ?
+		AND component->'version'->>'train' = ?
+		AND component->'version'->>'version' = ?
+		AND checkin_snapshots.created_at >= ?
+		AND checkin_snapshots.created_at < ?
+		GROUP BY date`,
+		component,
+		train,
+		version,
+		Timestamp{Time: versionRow.ReleaseTimestamp.Time},
+		Timestamp{Time: until},
+	).Find(&rows)
+	if countsDB.Error != nil {
+		return models.ReleaseAdoption{}, countsDB.Error
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Date] = row.Clusters
+	}
+	return makeReleaseAdoption(versionRow, until, counts), nil
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestMakeReleaseAdoption(t *testing.T) {
+	released := time.Date(2016, time.June, 1, 18, 0, 0, 0, time.UTC)
+	version := versionsTable{ComponentName: componentName, Train: train, Version: "2.0.0", ReleaseTimestamp: Timestamp{Time: released}}
+	counts := map[string]int64{"2016-06-01": 2, "2016-06-03": 5}
+
+	type testCase struct {
+		until time.Time
+		dates []string
+	}
+	testCases := []testCase{
+		{until: released.AddDate(0, 0, 2), dates: []string{"2016-06-01", "2016-06-02", "2016-06-03"}},
+		// until is exclusive, so a series that ends at midnight doesn't include the next day
+		{until: time.Date(2016, time.June, 3, 0, 0, 0, 0, time.UTC), dates: []string{"2016-06-01", "2016-06-02"}},
+		{until: released.Add(time.Hour), dates: []string{"2016-06-01"}},
+		{until: released.Add(-24 * time.Hour), dates: []string{}},
+	}
+	for i, tc := range testCases {
+		adoption := makeReleaseAdoption(version, tc.until, counts)
+		assert.Equal(t, adoption.Version, "2.0.0", "version")
+		assert.Equal(t, adoption.Released, "2016-06-01T18:00:00Z", "released")
+		dates := make([]string, len(adoption.Data))
+		for j, day := range adoption.Data {
+			dates[j] = day.Date
+			assert.Equal(t, *day.Clusters, counts[day.Date], "number of clusters on "+day.Date)
+		}
+		assert.Equal(t, dates, tc.dates, fmt.Sprintf("test case %d dates", i))
+	}
+}
@@ -0,0 +1,134 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/go-swagger/go-swagger/strfmt"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// ReleaseActive is the status of a release that hasn't been deprecated or yanked. It's never
+	// stored, and is only used in requests to clear a deprecation
+	ReleaseActive = "active"
+	// ReleaseDeprecated is the status of a release that clusters should upgrade from. It's still
+	// advertised as the latest release of its train if there isn't a newer one
+	ReleaseDeprecated = "deprecated"
+	// ReleaseYanked is the status of a release that's been withdrawn. It's never advertised as the
+	// latest release of its train
+	ReleaseYanked = "yanked"
+)
+
+// releaseDeprecations holds the deprecations of a set of releases, keyed on release. Releases
+// without a key are active
+type releaseDeprecations map[releaseKey]releaseDeprecationsTable
+
+func versionKey(v versionsTable) releaseKey {
+	return releaseKey{component: v.ComponentName, train: v.Train, version: v.Version}
+}
+
+// yanked returns true if v has been yanked
+func (r releaseDeprecations) yanked(v versionsTable) bool {
+	d, ok := r[versionKey(v)]
+	return ok && d.Status == ReleaseYanked
+}
+
+// forVersion returns the deprecation of v, or nil if v is active
+func (r releaseDeprecations) forVersion(v versionsTable) *models.Deprecation {
+	d, ok := r[versionKey(v)]
+	if !ok {
+		return nil
+	}
+	changedAt := strfmt.DateTime(d.ChangedAt.Time)
+	return &models.Deprecation{Status: d.Status, Reason: d.Reason, ChangedAt: &changedAt}
+}
+
+// getReleaseDeprecations fetches the deprecations of all releases of the given components on the
+// given trains. Like getVersionsByComponentAndTrain, it matches the cross product of components
+// and trains, so callers should only look up the releases that they asked for
+func getReleaseDeprecations(db *gorm.DB, components, trains []string) (releaseDeprecations, error) {
+	ret := make(releaseDeprecations)
+	if len(components) == 0 || len(trains) == 0 {
+		return ret, nil
+	}
+	var rows []releaseDeprecationsTable
+	resDB := db.Where(
+		fmt.Sprintf("%s IN (?) AND %s IN (?)", releaseDeprecationsTableComponentNameKey, releaseDeprecationsTableTrainKey),
+		components,
+		trains,
+	).Find(&rows)
+	if resDB.Error != nil {
+		return nil, resDB.Error
+	}
+	for _, row := range rows {
+		ret[row.key()] = row
+	}
+	return ret, nil
+}
+
+// SetVersionDeprecation deprecates or yanks the given release, which must already be published,
+// and returns the release with its new deprecation. deprecation.Status must be ReleaseDeprecated or
+// ReleaseYanked, and a nil deprecation makes the release active again. Returns ErrNotFound if the
+// release doesn't exist
+func SetVersionDeprecation(
+	db *gorm.DB,
+	train,
+	component,
+	version string,
+	deprecation *models.Deprecation,
+) (models.ComponentVersion, error) {
+	key := releaseKey{component: component, train: train, version: version}
+	tx := db.Begin()
+	cv, err := setVersionDeprecation(tx, key, deprecation, time.Now())
+	if err != nil {
+		rollbackDB := tx.Rollback()
+		if rollbackDB.Error != nil {
+			return models.ComponentVersion{}, txErr{op: "rollback", orig: err, err: rollbackDB.Error}
+		}
+		return models.ComponentVersion{}, err
+	}
+	if commitDB := tx.Commit(); commitDB.Error != nil {
+		return models.ComponentVersion{}, txErr{op: "commit", orig: nil, err: commitDB.Error}
+	}
+	return cv, nil
+}
+
+func setVersionDeprecation(tx *gorm.DB, key releaseKey, deprecation *models.Deprecation, now time.Time) (models.ComponentVersion, error) {
+	var vsn versionsTable
+	queryDB := tx.Where(versionsTable{ComponentName: key.component, Train: key.train, Version: key.version}).First(&vsn)
+	if queryDB.Error != nil {
+		return models.ComponentVersion{}, queryDB.Error
+	}
+	deleteDB := tx.Where(&releaseDeprecationsTable{
+		ComponentName: key.component,
+		Train:         key.train,
+		Version:       key.version,
+	}).Delete(&releaseDeprecationsTable{})
+	if deleteDB.Error != nil {
+		return models.ComponentVersion{}, deleteDB.Error
+	}
+	deprecations := make(releaseDeprecations)
+	if deprecation != nil {
+		row := newReleaseDeprecation(key, deprecation, now)
+		if createDB := tx.Create(&row); createDB.Error != nil {
+			return models.ComponentVersion{}, createDB.Error
+		}
+		deprecations[key] = row
+	}
+	return parseDBVersion(vsn, deprecations)
+}
+
+// newReleaseDeprecation returns the row that records deprecation for the release with the given key
+func newReleaseDeprecation(key releaseKey, deprecation *models.Deprecation, now time.Time) releaseDeprecationsTable {
+	return releaseDeprecationsTable{
+		ComponentName: key.component,
+		Train:         key.train,
+		Version:       key.version,
+		Status:        deprecation.Status,
+		Reason:        deprecation.Reason,
+		// timestamps are stored in StdTimestampFmt, which has no sub-second precision
+		ChangedAt: Timestamp{Time: now.UTC().Truncate(time.Second)},
+	}
+}
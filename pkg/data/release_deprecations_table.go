@@ -0,0 +1,59 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	releaseDeprecationsTableName             = "release_deprecations"
+	releaseDeprecationsTableComponentNameKey = "component_name"
+	releaseDeprecationsTableTrainKey         = "train"
+	releaseDeprecationsTableVersionKey       = "version"
+	releaseDeprecationsTableStatusKey        = "status"
+	releaseDeprecationsTableReasonKey        = "reason"
+	releaseDeprecationsTableChangedAtKey     = "changed_at"
+)
+
+// releaseDeprecationsTable type that expresses the `release_deprecations` postgres table schema.
+// Deprecations are kept out of the versions table so that re-publishing a release doesn't clear
+// its deprecation
+type releaseDeprecationsTable struct {
+	ComponentName string    `gorm:"primary_key;column:component_name"`
+	Train         string    `gorm:"primary_key;column:train"`
+	Version       string    `gorm:"primary_key;column:version"`
+	Status        string    `gorm:"column:status"`
+	Reason        string    `gorm:"column:reason"`
+	ChangedAt     Timestamp `gorm:"column:changed_at;type:timestamp"`
+}
+
+func (r releaseDeprecationsTable) TableName() string {
+	return releaseDeprecationsTableName
+}
+
+// releaseKey identifies a single release of a component
+type releaseKey struct {
+	component string
+	train     string
+	version   string
+}
+
+func (r releaseDeprecationsTable) key() releaseKey {
+	return releaseKey{component: r.ComponentName, train: r.Train, version: r.Version}
+}
+
+func createReleaseDeprecationsTable(db execer) (sql.Result, error) {
+	return db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s varchar(64), %s varchar(64), %s varchar(32), %s varchar(16), %s varchar(1024), %s timestamp, PRIMARY KEY (%s, %s, %s) )",
+		releaseDeprecationsTableName,
+		releaseDeprecationsTableComponentNameKey,
+		releaseDeprecationsTableTrainKey,
+		releaseDeprecationsTableVersionKey,
+		releaseDeprecationsTableStatusKey,
+		releaseDeprecationsTableReasonKey,
+		releaseDeprecationsTableChangedAtKey,
+		releaseDeprecationsTableComponentNameKey,
+		releaseDeprecationsTableTrainKey,
+		releaseDeprecationsTableVersionKey,
+	))
+}
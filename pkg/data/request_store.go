@@ -0,0 +1,239 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// RequestError is an error that a Store returned while serving an API request. It's returned by
+// Stores created with NewRequestStore, so that errors in the logs can be matched to requests
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+// Error is the error interface implementation
+func (e RequestError) Error() string {
+	return fmt.Sprintf("%s (request %s)", e.Err, e.RequestID)
+}
+
+// requestStore is a Store that adds a request ID to the errors returned by another Store
+type requestStore struct {
+	store     Store
+	requestID string
+}
+
+// NewRequestStore returns a Store that calls the same function of store, and wraps the errors it
+// returns in a RequestError with requestID. ErrNotFound is returned as is, since callers compare
+// against it. If requestID is empty, store is returned unchanged
+func NewRequestStore(store Store, requestID string) Store {
+	if requestID == "" {
+		return store
+	}
+	return &requestStore{store: store, requestID: requestID}
+}
+
+func (r *requestStore) wrap(err error) error {
+	if err == nil || err == ErrNotFound {
+		return err
+	}
+	return RequestError{RequestID: r.requestID, Err: err}
+}
+
+func (r *requestStore) GetCluster(id string) (models.Cluster, error) {
+	ret, err := r.store.GetCluster(id)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error) {
+	ret, err := r.store.UpsertCluster(id, cluster)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetClusterCount() (int, error) {
+	ret, err := r.store.GetClusterCount()
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) FilterClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error) {
+	ret, next, err := r.store.FilterClustersByAge(filter, page)
+	return ret, next, r.wrap(err)
+}
+
+func (r *requestStore) ExportClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error) {
+	ret, err := r.store.ExportClustersByAge(filter, page)
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+	return requestClusterRows{ClusterRows: ret, wrap: r.wrap}, nil
+}
+
+func (r *requestStore) SetUpdatesAvailable(cluster *models.Cluster) error {
+	return r.wrap(r.store.SetUpdatesAvailable(cluster))
+}
+
+func (r *requestStore) DetectDormantClusters(now time.Time, window time.Duration) (int, error) {
+	ret, err := r.store.DetectDormantClusters(now, window)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) CheckInCluster(id string, checkinTime time.Time, cluster models.Cluster) error {
+	return r.wrap(r.store.CheckInCluster(id, checkinTime, cluster))
+}
+
+func (r *requestStore) FilterClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	ret, next, err := r.store.FilterClusterCheckins(filter, page)
+	return ret, next, r.wrap(err)
+}
+
+func (r *requestStore) ExportClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error) {
+	ret, err := r.store.ExportClusterCheckins(filter, page)
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+	return requestClusterRows{ClusterRows: ret, wrap: r.wrap}, nil
+}
+
+func (r *requestStore) FilterPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error) {
+	ret, next, err := r.store.FilterPersistentClusters(filter, page)
+	return ret, next, r.wrap(err)
+}
+
+func (r *requestStore) ExportPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error) {
+	ret, err := r.store.ExportPersistentClusters(filter, page)
+	if err != nil {
+		return nil, r.wrap(err)
+	}
+	return requestClusterRows{ClusterRows: ret, wrap: r.wrap}, nil
+}
+
+func (r *requestStore) GetClusterCheckinHistory(query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error) {
+	ret, err := r.store.GetClusterCheckinHistory(query)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetClusterCheckinByID(clusterID, checkinID string) (models.ClusterCheckinRecord, error) {
+	ret, err := r.store.GetClusterCheckinByID(clusterID, checkinID)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	ret, err := r.store.GetClusterCheckinAt(clusterID, t)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetLastCheckinTime(clusterID string) (time.Time, error) {
+	ret, err := r.store.GetLastCheckinTime(clusterID)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) CompactCheckins(cutoff time.Time) (int, error) {
+	ret, err := r.store.CompactCheckins(cutoff)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	ret, err := r.store.GetVersion(cv)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetLatestVersion(train, component string) (models.ComponentVersion, error) {
+	ret, err := r.store.GetLatestVersion(train, component)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetLatestVersions(ct []ComponentAndTrain) ([]*models.ComponentVersion, error) {
+	ret, err := r.store.GetLatestVersions(ct)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetVersionsList(train, component string) ([]*models.ComponentVersion, error) {
+	ret, err := r.store.GetVersionsList(train, component)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) UpsertVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	ret, err := r.store.UpsertVersion(cv)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetVersionCountsByTrain() (map[string]int, error) {
+	ret, err := r.store.GetVersionCountsByTrain()
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) SetVersionDeprecation(train, component, version string, deprecation *models.Deprecation) (models.ComponentVersion, error) {
+	ret, err := r.store.SetVersionDeprecation(train, component, version, deprecation)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetDoctor(id string) (models.DoctorInfo, error) {
+	ret, err := r.store.GetDoctor(id)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) UpsertDoctor(id string, doctor models.DoctorInfo) (models.DoctorInfo, error) {
+	ret, err := r.store.UpsertDoctor(id, doctor)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetDoctorCount() (int, error) {
+	ret, err := r.store.GetDoctorCount()
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) CreatePublisherToken(name string, components, trains []string) (string, PublisherToken, error) {
+	token, publisherToken, err := r.store.CreatePublisherToken(name, components, trains)
+	return token, publisherToken, r.wrap(err)
+}
+
+func (r *requestStore) GetPublisherToken(token string) (PublisherToken, error) {
+	ret, err := r.store.GetPublisherToken(token)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) ListPublisherTokens() ([]PublisherToken, error) {
+	ret, err := r.store.ListPublisherTokens()
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) RevokePublisherToken(name string) error {
+	return r.wrap(r.store.RevokePublisherToken(name))
+}
+
+func (r *requestStore) GetComponentAdoption(train, component string) (models.ComponentAdoption, error) {
+	ret, err := r.store.GetComponentAdoption(train, component)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetReleaseAdoption(train, component, version string, until time.Time) (models.ReleaseAdoption, error) {
+	ret, err := r.store.GetReleaseAdoption(train, component, version, until)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetActiveClusters(query *ActiveClustersQuery) (models.ActiveClusters, error) {
+	ret, err := r.store.GetActiveClusters(query)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetActiveClusterCount(since time.Time) (int, error) {
+	ret, err := r.store.GetActiveClusterCount(since)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetClusterChurn(query *ClusterChurnQuery) (models.ClusterChurn, error) {
+	ret, err := r.store.GetClusterChurn(query)
+	return ret, r.wrap(err)
+}
+
+func (r *requestStore) GetClusterCohorts(query *ClusterCohortsQuery) (models.ClusterCohorts, error) {
+	ret, err := r.store.GetClusterCohorts(query)
+	return ret, r.wrap(err)
+}
+
+// CheckHealth returns the checks' errors unchanged, since they aren't request errors
+func (r *requestStore) CheckHealth(timeout time.Duration) []HealthCheck {
+	return r.store.CheckHealth(timeout)
+}
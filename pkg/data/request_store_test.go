@@ -0,0 +1,25 @@
+package data
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func TestRequestStore(t *testing.T) {
+	memStore := NewMemStore()
+	assert.True(t, NewRequestStore(memStore, "") == memStore, "store was wrapped without a request ID")
+
+	store := NewRequestStore(memStore, "testrequest")
+	// the request store should still pass the conformance tests
+	testStoreClusterRoundTrip(t, store)
+	_, err := store.GetCluster("notacluster")
+	assert.Err(t, ErrNotFound, err)
+
+	err = store.(*requestStore).wrap(errors.New("test error"))
+	reqErr, ok := err.(RequestError)
+	assert.True(t, ok, "returned error was a %T, not a RequestError", err)
+	assert.Equal(t, reqErr.RequestID, "testrequest", "request ID")
+	assert.Equal(t, err.Error(), "test error (request testrequest)", "error message")
+}
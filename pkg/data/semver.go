@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semVer is a parsed Semantic Versioning 2.0.0 version string. See http://semver.org for the full
+// specification. Build metadata is recorded but, per the spec, doesn't participate in precedence
+type semVer struct {
+	major      uint64
+	minor      uint64
+	patch      uint64
+	preRelease []string
+	build      string
+}
+
+type errInvalidSemVer struct {
+	str    string
+	reason string
+}
+
+func (e errInvalidSemVer) Error() string {
+	return fmt.Sprintf("%s is not a valid semantic version (%s)", e.str, e.reason)
+}
+
+// parseSemVer parses str as a semantic version. A single leading 'v' (as in "v2.0.0") is allowed
+// since that's how most deis components are tagged. Returns errInvalidSemVer if str doesn't
+// conform to the spec
+func parseSemVer(str string) (semVer, error) {
+	s := strings.TrimPrefix(str, "v")
+	ret := semVer{}
+	if idx := strings.Index(s, "+"); idx >= 0 {
+		ret.build = s[idx+1:]
+		s = s[:idx]
+		if err := checkSemVerIdentifiers(ret.build, false); err != nil {
+			return semVer{}, errInvalidSemVer{str: str, reason: "build metadata " + err.Error()}
+		}
+	}
+	if idx := strings.Index(s, "-"); idx >= 0 {
+		pre := s[idx+1:]
+		s = s[:idx]
+		if err := checkSemVerIdentifiers(pre, true); err != nil {
+			return semVer{}, errInvalidSemVer{str: str, reason: "pre-release " + err.Error()}
+		}
+		ret.preRelease = strings.Split(pre, ".")
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semVer{}, errInvalidSemVer{str: str, reason: "expected MAJOR.MINOR.PATCH"}
+	}
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		if !isNumericIdentifier(part) {
+			return semVer{}, errInvalidSemVer{str: str, reason: fmt.Sprintf("%q is not a valid version number", part)}
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return semVer{}, errInvalidSemVer{str: str, reason: err.Error()}
+		}
+		nums[i] = n
+	}
+	ret.major, ret.minor, ret.patch = nums[0], nums[1], nums[2]
+	return ret, nil
+}
+
+// checkSemVerIdentifiers checks that every dot-separated identifier in ids is non-empty and made
+// up only of [0-9A-Za-z-]. If noLeadingZeros is true, numeric identifiers may not have leading
+// zeroes (this applies to pre-release identifiers but not build metadata)
+func checkSemVerIdentifiers(ids string, noLeadingZeros bool) error {
+	for _, id := range strings.Split(ids, ".") {
+		if id == "" {
+			return fmt.Errorf("has an empty identifier")
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9') && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && r != '-' {
+				return fmt.Errorf("identifier %q has invalid character %q", id, r)
+			}
+		}
+		if noLeadingZeros && isDigits(id) && !isNumericIdentifier(id) {
+			return fmt.Errorf("identifier %q has a leading zero", id)
+		}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumericIdentifier returns true if s is a number without leading zeroes
+func isNumericIdentifier(s string) bool {
+	return isDigits(s) && (s == "0" || s[0] != '0')
+}
+
+// compare returns -1, 0 or 1 if s has lower, equal or higher precedence than other, respectively
+func (s semVer) compare(other semVer) int {
+	if c := compareUint(s.major, other.major); c != 0 {
+		return c
+	}
+	if c := compareUint(s.minor, other.minor); c != 0 {
+		return c
+	}
+	if c := compareUint(s.patch, other.patch); c != 0 {
+		return c
+	}
+	// a version without a pre-release has higher precedence than one with a pre-release
+	switch {
+	case len(s.preRelease) == 0 && len(other.preRelease) == 0:
+		return 0
+	case len(s.preRelease) == 0:
+		return 1
+	case len(other.preRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(s.preRelease) && i < len(other.preRelease); i++ {
+		if c := comparePreReleaseIdentifier(s.preRelease[i], other.preRelease[i]); c != 0 {
+			return c
+		}
+	}
+	// a larger set of pre-release fields has higher precedence, if all preceding ones are equal
+	return compareUint(uint64(len(s.preRelease)), uint64(len(other.preRelease)))
+}
+
+func comparePreReleaseIdentifier(a, b string) int {
+	aNum, bNum := isDigits(a), isDigits(b)
+	switch {
+	case aNum && bNum:
+		// identifiers were validated on parse, so they're guaranteed to fit in a uint64 unless
+		// they're absurdly long. compare by length first to handle that case anyway
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		// numeric identifiers always have lower precedence than alphanumeric ones
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
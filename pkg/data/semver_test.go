@@ -0,0 +1,92 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestParseSemVer(t *testing.T) {
+	type testCase struct {
+		str string
+		err bool
+	}
+	testCases := []testCase{
+		testCase{str: "2.0.0", err: false},
+		testCase{str: "v2.0.0", err: false},
+		testCase{str: "2.0.0-rc1", err: false},
+		testCase{str: "2.0.0-beta.2", err: false},
+		testCase{str: "2.0.0-beta.2+git.abc123", err: false},
+		testCase{str: "2.0.0+20160331", err: false},
+		testCase{str: "2.0", err: true},
+		testCase{str: "2.0.0.0", err: true},
+		testCase{str: "02.0.0", err: true},
+		testCase{str: "2.0.0-beta.02", err: true},
+		testCase{str: "2.0.0-", err: true},
+		testCase{str: "2.0.0-beta..1", err: true},
+		testCase{str: "2.0.0-beta_1", err: true},
+		testCase{str: "testversion", err: true},
+		testCase{str: "", err: true},
+	}
+	for i, testCase := range testCases {
+		_, err := parseSemVer(testCase.str)
+		if testCase.err && err == nil {
+			t.Errorf("expected error on iteration %d (%s) but got none", i, testCase.str)
+		} else if !testCase.err && err != nil {
+			t.Errorf("expected no error on iteration %d (%s) but got %s", i, testCase.str, err)
+		}
+	}
+}
+
+func TestSemVerCompare(t *testing.T) {
+	// each version has strictly lower precedence than the one after it, as listed in the
+	// precedence example in http://semver.org/#spec-item-11
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0-rc1",
+		"2.0.0-rc2",
+		"2.0.0",
+		"10.0.0",
+	}
+	for i := 0; i < len(ordered); i++ {
+		lower, err := parseSemVer(ordered[i])
+		if err != nil {
+			t.Fatalf("error parsing %s (%s)", ordered[i], err)
+		}
+		if c := lower.compare(lower); c != 0 {
+			t.Errorf("expected %s to be equal to itself, got %d", ordered[i], c)
+		}
+		for j := i + 1; j < len(ordered); j++ {
+			higher, err := parseSemVer(ordered[j])
+			if err != nil {
+				t.Fatalf("error parsing %s (%s)", ordered[j], err)
+			}
+			if c := lower.compare(higher); c != -1 {
+				t.Errorf("expected %s < %s, got %d", ordered[i], ordered[j], c)
+			}
+			if c := higher.compare(lower); c != 1 {
+				t.Errorf("expected %s > %s, got %d", ordered[j], ordered[i], c)
+			}
+		}
+	}
+
+	// build metadata doesn't participate in precedence
+	withBuild, err := parseSemVer("2.0.0+build.1")
+	if err != nil {
+		t.Fatalf("error parsing version with build metadata (%s)", err)
+	}
+	withoutBuild, err := parseSemVer("v2.0.0")
+	if err != nil {
+		t.Fatalf("error parsing version without build metadata (%s)", err)
+	}
+	if c := withBuild.compare(withoutBuild); c != 0 {
+		t.Errorf("expected build metadata to be ignored, got %d", c)
+	}
+}
@@ -0,0 +1,153 @@
+package data
+
+import (
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// ClusterStore is the interface for reading and writing deis clusters
+type ClusterStore interface {
+	// GetCluster gets the cluster with the given cluster ID
+	GetCluster(id string) (models.Cluster, error)
+	// UpsertCluster checks in the cluster with the given ID, then creates or updates it
+	UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error)
+	// GetClusterCount returns the total number of clusters
+	GetClusterCount() (int, error)
+	// FilterClustersByAge returns the clusters in page whose various time fields match the
+	// requirements in the given filter, and the cursor for the next page. A nil page returns all of
+	// the clusters
+	FilterClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) ([]*models.Cluster, string, error)
+	// ExportClustersByAge returns the clusters that FilterClustersByAge would, as ClusterRows. Every
+	// cluster after page's cursor is returned, regardless of its limit
+	ExportClustersByAge(filter *ClusterAgeFilter, page *ClusterPage) (ClusterRows, error)
+	// SetUpdatesAvailable sets UpdateAvailable on each of the cluster's components that are behind
+	// the latest release on their train
+	SetUpdatesAvailable(cluster *models.Cluster) error
+	// DetectDormantClusters walks the checkins since its last run, and records each gap of more than
+	// window between a cluster's checkins as it going dormant and being reactivated, and that each
+	// cluster that hasn't checked in for window before now went dormant. Returns the number of
+	// events that were recorded
+	DetectDormantClusters(now time.Time, window time.Duration) (int, error)
+}
+
+// CheckinStore is the interface for recording and querying cluster checkins
+type CheckinStore interface {
+	// CheckInCluster records that the cluster with the given ID checked in at checkinTime
+	CheckInCluster(id string, checkinTime time.Time, cluster models.Cluster) error
+	// FilterClusterCheckins returns a checkin summary for each cluster in page that matches the
+	// requirements in the given filter, and the cursor for the next page. A nil page returns all of
+	// the summaries
+	FilterClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error)
+	// ExportClusterCheckins returns the summaries that FilterClusterCheckins would, as ClusterRows.
+	// Every summary after page's cursor is returned, regardless of its limit
+	ExportClusterCheckins(filter *ClusterCheckinsFilter, page *ClusterPage) (ClusterRows, error)
+	// FilterPersistentClusters returns a checkin summary for each cluster in page that matches the
+	// requirements in the given filter, and the cursor for the next page. A nil page returns all of
+	// the summaries
+	FilterPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) ([]*models.ClusterCheckin, string, error)
+	// ExportPersistentClusters returns the summaries that FilterPersistentClusters would, as
+	// ClusterRows. Every summary after page's cursor is returned, regardless of its limit
+	ExportPersistentClusters(filter *PersistentClustersFilter, page *ClusterPage) (ClusterRows, error)
+	// GetClusterCheckinHistory returns the page of a single cluster's checkins that query selects,
+	// ordered from oldest to newest
+	GetClusterCheckinHistory(query *ClusterCheckinHistoryQuery) (models.ClusterCheckinHistory, error)
+	// GetClusterCheckinByID returns the checkin of the given cluster with the given checkin ID
+	GetClusterCheckinByID(clusterID, checkinID string) (models.ClusterCheckinRecord, error)
+	// GetClusterCheckinAt returns the given cluster's latest checkin at or before t
+	GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error)
+	// GetLastCheckinTime returns the time of the given cluster's latest raw checkin, without reading
+	// its data
+	GetLastCheckinTime(clusterID string) (time.Time, error)
+	// CompactCheckins replaces all of the raw checkins before the start of cutoff's UTC day with a
+	// summary of each cluster's checkins on each day, and returns the number of checkins that were
+	// compacted. Compacted checkins are still counted by the filters and stats, but they no longer
+	// appear in a cluster's checkin history
+	CompactCheckins(cutoff time.Time) (int, error)
+}
+
+// VersionStore is the interface for reading and writing component releases
+type VersionStore interface {
+	// GetVersion gets the release matching the component name, train and version in cv
+	GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error)
+	// GetLatestVersion gets the latest release for the given train & component
+	GetLatestVersion(train, component string) (models.ComponentVersion, error)
+	// GetLatestVersions gets the latest release for each component/train pair in ct
+	GetLatestVersions(ct []ComponentAndTrain) ([]*models.ComponentVersion, error)
+	// GetVersionsList gets all releases for the given train & component
+	GetVersionsList(train, component string) ([]*models.ComponentVersion, error)
+	// UpsertVersion creates or updates a single release
+	UpsertVersion(cv models.ComponentVersion) (models.ComponentVersion, error)
+	// GetVersionCountsByTrain returns the number of releases on each train, keyed on train name
+	GetVersionCountsByTrain() (map[string]int, error)
+	// SetVersionDeprecation deprecates or yanks a published release, or makes it active again if
+	// deprecation is nil. See SetVersionDeprecation in this package
+	SetVersionDeprecation(train, component, version string, deprecation *models.Deprecation) (models.ComponentVersion, error)
+}
+
+// DoctorStore is the interface for reading and writing doctor reports
+type DoctorStore interface {
+	// GetDoctor gets the doctor report with the given ID
+	GetDoctor(id string) (models.DoctorInfo, error)
+	// UpsertDoctor creates or updates the doctor report with the given ID
+	UpsertDoctor(id string, doctor models.DoctorInfo) (models.DoctorInfo, error)
+	// GetDoctorCount returns the total number of doctor reports
+	GetDoctorCount() (int, error)
+}
+
+// PublisherTokenStore is the interface for managing and checking the API tokens that are allowed
+// to publish component releases
+type PublisherTokenStore interface {
+	// CreatePublisherToken creates a new publisher token with the given name and scope, and returns
+	// the token itself
+	CreatePublisherToken(name string, components, trains []string) (string, PublisherToken, error)
+	// GetPublisherToken returns the publisher token that matches token
+	GetPublisherToken(token string) (PublisherToken, error)
+	// ListPublisherTokens returns all publisher tokens, ordered by name
+	ListPublisherTokens() ([]PublisherToken, error)
+	// RevokePublisherToken deletes the publisher token with the given name
+	RevokePublisherToken(name string) error
+}
+
+// StatsStore is the interface for aggregate statistics about the clusters that have checked in
+type StatsStore interface {
+	// GetComponentAdoption returns the number of clusters running each release of the given
+	// component on the given train, according to each cluster's latest checkin
+	GetComponentAdoption(train, component string) (models.ComponentAdoption, error)
+	// GetReleaseAdoption returns the number of clusters that reported the given release in a
+	// checkin on each day from the day it was released onward. Only checkins before until are
+	// counted. Returns an error with ReasonInvalidFilter if there are more than MaxStatsBuckets days
+	GetReleaseAdoption(train, component, version string, until time.Time) (models.ReleaseAdoption, error)
+	// GetActiveClusters returns the number of distinct clusters that checked in during each of
+	// query's buckets
+	GetActiveClusters(query *ActiveClustersQuery) (models.ActiveClusters, error)
+	// GetActiveClusterCount returns the number of distinct clusters that checked in at or after since
+	GetActiveClusterCount(since time.Time) (int, error)
+	// GetClusterChurn returns the number of new, retained, reactivated and lost clusters in each of
+	// query's periods
+	GetClusterChurn(query *ClusterChurnQuery) (models.ClusterChurn, error)
+	// GetClusterCohorts groups clusters into cohorts by the period they first checked in during, and
+	// returns the fraction of each of query's cohorts that checked in during each later period
+	GetClusterCohorts(query *ClusterCohortsQuery) (models.ClusterCohorts, error)
+}
+
+// HealthStore is the interface for checking that a data store can serve requests
+type HealthStore interface {
+	// CheckHealth runs the store's health checks, each of which fails if it takes longer than
+	// timeout, and returns their results in the order they ran
+	CheckHealth(timeout time.Duration) []HealthCheck
+}
+
+// Store is the interface to all of the API's persistent data. NewGormStore returns the
+// implementation backed by a SQL database (Postgres in production), and NewMemStore returns a pure
+// Go, in-memory implementation that's best used for testing. Both must pass the conformance tests
+// in store_test.go
+type Store interface {
+	ClusterStore
+	CheckinStore
+	VersionStore
+	DoctorStore
+	PublisherTokenStore
+	StatsStore
+	HealthStore
+}
@@ -0,0 +1,858 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// the tests in this file are the conformance tests for Store implementations. each implementation
+// should have its own test file that calls each of these funcs with a new, empty Store
+
+func testStoreClusterRoundTrip(t *testing.T, store Store) {
+	_, err := store.GetCluster(clusterID)
+	assert.True(t, err != nil, "error not returned when expected")
+	count, err := store.GetClusterCount()
+	assert.NoErr(t, err)
+	assert.Equal(t, count, 0, "cluster count")
+
+	expectedCluster := testCluster()
+	newCluster, err := store.UpsertCluster(clusterID, expectedCluster)
+	assert.NoErr(t, err)
+	assert.Equal(t, newCluster.ID, expectedCluster.ID, "cluster ID property")
+	desc := "new description"
+	expectedCluster.Components[0].Component.Description = &desc
+	updatedCluster, err := store.UpsertCluster(clusterID, expectedCluster)
+	assert.NoErr(t, err)
+	assert.Equal(t, *updatedCluster.Components[0].Component.Description, desc, "cluster component description property")
+	getCluster, err := store.GetCluster(clusterID)
+	assert.NoErr(t, err)
+	assert.Equal(t, getCluster, updatedCluster, "cluster")
+	count, err = store.GetClusterCount()
+	assert.NoErr(t, err)
+	assert.Equal(t, count, 1, "cluster count")
+}
+
+// testStoreConcurrentCheckins checks that identical checkins from the same cluster can all be
+// stored at once. The gorm store isn't run against it with sqlite, because each connection to the
+// in-memory database has its own, empty, copy of it
+func testStoreConcurrentCheckins(t *testing.T, store Store) {
+	const n = 10
+	cluster := testCluster()
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.UpsertCluster(cluster.ID, cluster)
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		assert.NoErr(t, <-errs)
+	}
+	_, err := store.GetCluster(cluster.ID)
+	assert.NoErr(t, err)
+	query, err := NewClusterCheckinHistoryQuery(cluster.ID, time.Time{}, time.Time{}, 100, "")
+	assert.NoErr(t, err)
+	history, err := store.GetClusterCheckinHistory(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(history.Data), n, "number of checkins")
+}
+
+func testStoreFilterClustersByAge(t *testing.T, store Store) {
+	now := time.Now()
+	_, err := store.UpsertCluster("cluster1", models.Cluster{ID: "cluster1"})
+	assert.NoErr(t, err)
+	// only checked in, so it has no entry in the clusters table and shouldn't be returned
+	assert.NoErr(t, store.CheckInCluster("cluster2", now, models.Cluster{ID: "cluster2"}))
+
+	filter, err := NewClusterAgeFilter(now.Add(time.Hour), now.Add(-time.Hour), now.Add(time.Hour), now.Add(-time.Hour))
+	assert.NoErr(t, err)
+	clusters, _, err := store.FilterClustersByAge(filter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(clusters), 1, "number of clusters")
+	assert.Equal(t, clusters[0].ID, "cluster1", "cluster ID")
+
+	filter, err = NewClusterAgeFilter(now.Add(2*time.Hour), now.Add(time.Hour), now.Add(2*time.Hour), now.Add(time.Hour))
+	assert.NoErr(t, err)
+	clusters, _, err = store.FilterClustersByAge(filter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(clusters), 0, "number of clusters")
+}
+
+func testStoreFilterClustersByAgePages(t *testing.T, store Store) {
+	now := time.Now().Round(time.Second)
+	// cluster0 and cluster1 first checked in at the same time, so they're ordered by ID
+	firstSeen := []time.Duration{-4 * time.Hour, -4 * time.Hour, -3 * time.Hour, -2 * time.Hour, -time.Hour}
+	for i, ago := range firstSeen {
+		id := fmt.Sprintf("cluster%d", i)
+		assert.NoErr(t, store.CheckInCluster(id, now.Add(ago), models.Cluster{ID: id}))
+		_, err := store.UpsertCluster(id, models.Cluster{ID: id})
+		assert.NoErr(t, err)
+	}
+	filter, err := NewClusterAgeFilter(now.Add(time.Hour), now.Add(-24*time.Hour), now.Add(time.Hour), now.Add(-24*time.Hour))
+	assert.NoErr(t, err)
+
+	ids := []string{}
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		page, err := NewClusterPage(2, cursor)
+		assert.NoErr(t, err)
+		clusters, next, err := store.FilterClustersByAge(filter, page)
+		assert.NoErr(t, err)
+		for _, cluster := range clusters {
+			ids = append(ids, cluster.ID)
+		}
+		if i == 0 {
+			// a cluster that first checks in between pages sorts before the cursor, so it doesn't
+			// shift the following pages
+			assert.NoErr(t, store.CheckInCluster("cluster5", now.Add(-5*time.Hour), models.Cluster{ID: "cluster5"}))
+			_, err := store.UpsertCluster("cluster5", models.Cluster{ID: "cluster5"})
+			assert.NoErr(t, err)
+		}
+		if i < 2 {
+			assert.Equal(t, len(clusters), 2, "number of clusters")
+			assert.True(t, next != "", "page %d has no next cursor", i)
+		} else {
+			assert.Equal(t, len(clusters), 1, "number of clusters")
+			assert.Equal(t, next, "", "next cursor on the last page")
+		}
+		cursor = next
+	}
+	assert.Equal(t, ids, []string{"cluster0", "cluster1", "cluster2", "cluster3", "cluster4"}, "cluster IDs")
+}
+
+func testStoreExportClustersByAge(t *testing.T, store Store) {
+	now := time.Now().Round(time.Second)
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("cluster%d", i)
+		assert.NoErr(t, store.CheckInCluster(id, now.Add(time.Duration(i-4)*time.Hour), models.Cluster{ID: id}))
+		_, err := store.UpsertCluster(id, models.Cluster{ID: id})
+		assert.NoErr(t, err)
+	}
+	filter, err := NewClusterAgeFilter(now.Add(time.Hour), now.Add(-24*time.Hour), now.Add(time.Hour), now.Add(-24*time.Hour))
+	assert.NoErr(t, err)
+	first, err := NewClusterPage(1, "")
+	assert.NoErr(t, err)
+	_, cursor, err := store.FilterClustersByAge(filter, first)
+	assert.NoErr(t, err)
+
+	// the export starts after the cursor, and ignores the page's limit
+	page, err := NewClusterPage(1, cursor)
+	assert.NoErr(t, err)
+	rows, err := store.ExportClustersByAge(filter, page)
+	assert.NoErr(t, err)
+	ids := []string{}
+	for rows.Next() {
+		row, err := rows.Row()
+		assert.NoErr(t, err)
+		cluster, ok := row.(*models.Cluster)
+		assert.True(t, ok, "row was a %T, not a *models.Cluster", row)
+		ids = append(ids, cluster.ID)
+	}
+	assert.NoErr(t, rows.Err())
+	assert.NoErr(t, rows.Close())
+	assert.Equal(t, ids, []string{"cluster1", "cluster2", "cluster3"}, "cluster IDs")
+}
+
+func testStoreVersions(t *testing.T, store Store) {
+	cv := testComponentVersion()
+	_, err := store.GetVersion(*cv)
+	assert.True(t, err != nil, "error not returned when expected")
+	_, err = store.GetLatestVersion(train, componentName)
+	assert.True(t, err != nil, "error not returned when expected")
+
+	for i, vsn := range []string{"2.0.0", "2.1.0", "2.0.1"} {
+		cv := testComponentVersion()
+		cv.Version.Version = vsn
+		cv.Version.Released = time.Now().Add(time.Duration(i) * time.Hour).Format(released)
+		_, err := store.UpsertVersion(*cv)
+		assert.NoErr(t, err)
+	}
+	cv.Version.Version = "2.0.0"
+	getCV, err := store.GetVersion(*cv)
+	assert.NoErr(t, err)
+	assert.Equal(t, getCV.Version.Version, "2.0.0", "version")
+
+	list, err := store.GetVersionsList(train, componentName)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(list), 3, "number of versions")
+	latest, err := store.GetLatestVersion(train, componentName)
+	assert.NoErr(t, err)
+	assert.Equal(t, latest.Version.Version, "2.1.0", "latest version")
+
+	latests, err := store.GetLatestVersions([]ComponentAndTrain{
+		{ComponentName: componentName, Train: "notatrain"},
+		{ComponentName: componentName, Train: train},
+	})
+	assert.NoErr(t, err)
+	assert.Equal(t, len(latests), 1, "number of latest versions")
+	assert.Equal(t, latests[0].Version.Version, "2.1.0", "latest version")
+
+	cluster := models.Cluster{
+		ID: clusterID,
+		Components: []*models.ComponentVersion{{
+			Component: &models.Component{Name: componentName},
+			Version:   &models.Version{Train: train, Version: "2.0.1"},
+		}},
+	}
+	assert.NoErr(t, store.SetUpdatesAvailable(&cluster))
+	assert.True(t, cluster.Components[0].UpdateAvailable != nil, "expected an available update")
+	assert.Equal(t, *cluster.Components[0].UpdateAvailable, "2.1.0", "available update")
+}
+
+func testStoreVersionCountsByTrain(t *testing.T, store Store) {
+	counts, err := store.GetVersionCountsByTrain()
+	assert.NoErr(t, err)
+	assert.Equal(t, len(counts), 0, "number of trains")
+	for i, trainVsn := range [][]string{{"stable", "2.0.0"}, {"stable", "2.1.0"}, {"beta", "2.2.0-beta1"}} {
+		cv := testComponentVersion()
+		cv.Version.Train = trainVsn[0]
+		cv.Version.Version = trainVsn[1]
+		cv.Version.Released = time.Now().Add(time.Duration(i) * time.Hour).Format(released)
+		_, err := store.UpsertVersion(*cv)
+		assert.NoErr(t, err)
+	}
+	counts, err = store.GetVersionCountsByTrain()
+	assert.NoErr(t, err)
+	assert.Equal(t, counts, map[string]int{"stable": 2, "beta": 1}, "version counts")
+}
+
+func testStoreVersionDeprecation(t *testing.T, store Store) {
+	_, err := store.SetVersionDeprecation(train, componentName, "2.0.0", nil)
+	assert.Equal(t, err, ErrNotFound, "error for an unpublished release")
+
+	for i, vsn := range []string{"2.0.0", "2.1.0"} {
+		cv := testComponentVersion()
+		cv.Version.Version = vsn
+		cv.Version.Released = time.Now().Add(time.Duration(i) * time.Hour).Format(released)
+		_, err := store.UpsertVersion(*cv)
+		assert.NoErr(t, err)
+	}
+	latestRelease := func() string {
+		latest, err := store.GetLatestVersion(train, componentName)
+		assert.NoErr(t, err)
+		return latest.Version.Version
+	}
+
+	deprecated, err := store.SetVersionDeprecation(train, componentName, "2.1.0", &models.Deprecation{
+		Status: ReleaseDeprecated,
+		Reason: "breaks upgrades",
+	})
+	assert.NoErr(t, err)
+	assert.True(t, deprecated.Deprecation != nil, "expected a deprecation")
+	assert.Equal(t, deprecated.Deprecation.Status, ReleaseDeprecated, "status")
+	assert.Equal(t, deprecated.Deprecation.Reason, "breaks upgrades", "reason")
+	assert.True(t, deprecated.Deprecation.ChangedAt != nil, "expected a change time")
+	assert.Equal(t, latestRelease(), "2.1.0", "latest version with a deprecated release")
+
+	_, err = store.SetVersionDeprecation(train, componentName, "2.1.0", &models.Deprecation{
+		Status: ReleaseYanked,
+		Reason: "deletes data",
+	})
+	assert.NoErr(t, err)
+	assert.Equal(t, latestRelease(), "2.0.0", "latest version with a yanked release")
+	latests, err := store.GetLatestVersions([]ComponentAndTrain{{ComponentName: componentName, Train: train}})
+	assert.NoErr(t, err)
+	assert.Equal(t, len(latests), 1, "number of latest versions")
+	assert.Equal(t, latests[0].Version.Version, "2.0.0", "latest version")
+
+	cv := testComponentVersion()
+	cv.Version.Version = "2.1.0"
+	yanked, err := store.GetVersion(*cv)
+	assert.NoErr(t, err)
+	assert.True(t, yanked.Deprecation != nil, "expected a deprecation")
+	assert.Equal(t, yanked.Deprecation.Status, ReleaseYanked, "status")
+	assert.Equal(t, yanked.Deprecation.Reason, "deletes data", "reason")
+
+	cluster := models.Cluster{
+		ID: clusterID,
+		Components: []*models.ComponentVersion{{
+			Component: &models.Component{Name: componentName},
+			Version:   &models.Version{Train: train, Version: "2.1.0"},
+		}},
+	}
+	assert.NoErr(t, store.SetUpdatesAvailable(&cluster))
+	running := cluster.Components[0]
+	assert.True(t, running.Deprecation != nil, "expected the running release to be flagged")
+	assert.Equal(t, running.Deprecation.Status, ReleaseYanked, "status")
+	assert.True(t, running.UpdateAvailable != nil, "expected an available update")
+	assert.Equal(t, *running.UpdateAvailable, "2.0.0", "available update")
+
+	_, err = store.SetVersionDeprecation(train, componentName, "2.0.0", &models.Deprecation{Status: ReleaseYanked})
+	assert.NoErr(t, err)
+	_, err = store.GetLatestVersion(train, componentName)
+	assert.Equal(t, err, ErrNotFound, "error when every release is yanked")
+	latests, err = store.GetLatestVersions([]ComponentAndTrain{{ComponentName: componentName, Train: train}})
+	assert.NoErr(t, err)
+	assert.Equal(t, len(latests), 0, "number of latest versions")
+
+	active, err := store.SetVersionDeprecation(train, componentName, "2.1.0", nil)
+	assert.NoErr(t, err)
+	assert.True(t, active.Deprecation == nil, "expected the deprecation to be cleared")
+	assert.Equal(t, latestRelease(), "2.1.0", "latest version after clearing the deprecation")
+	assert.NoErr(t, store.SetUpdatesAvailable(&cluster))
+	assert.True(t, cluster.Components[0].Deprecation == nil, "expected the flag to be cleared")
+	assert.True(t, cluster.Components[0].UpdateAvailable == nil, "expected no available update")
+}
+
+func testStoreClusterCheckinHistory(t *testing.T, store Store) {
+	start := time.Now().UTC().Truncate(time.Second)
+	// the third and fourth checkins have the same time, so pages must also be ordered by checkin ID
+	offsets := []time.Duration{0, time.Hour, 2 * time.Hour, 2 * time.Hour, 3 * time.Hour}
+	for i, offset := range offsets {
+		cluster := testCluster()
+		cluster.Components[0].Version.Version = fmt.Sprintf("2.%d.0", i)
+		assert.NoErr(t, store.CheckInCluster(clusterID, start.Add(offset), cluster))
+	}
+	assert.NoErr(t, store.CheckInCluster("othercluster", start.Add(time.Hour), models.Cluster{ID: "othercluster"}))
+
+	type testCase struct {
+		since    time.Time
+		until    time.Time
+		limit    int
+		versions []string
+	}
+	testCases := []testCase{
+		{limit: 100, versions: []string{"2.0.0", "2.1.0", "2.2.0", "2.3.0", "2.4.0"}},
+		{limit: 2, versions: []string{"2.0.0", "2.1.0", "2.2.0", "2.3.0", "2.4.0"}},
+		{limit: 1, versions: []string{"2.0.0", "2.1.0", "2.2.0", "2.3.0", "2.4.0"}},
+		{since: start.Add(time.Hour), limit: 100, versions: []string{"2.1.0", "2.2.0", "2.3.0", "2.4.0"}},
+		{until: start.Add(2 * time.Hour), limit: 100, versions: []string{"2.0.0", "2.1.0"}},
+		{since: start.Add(time.Hour), until: start.Add(3 * time.Hour), limit: 2, versions: []string{"2.1.0", "2.2.0", "2.3.0"}},
+	}
+	for i, tc := range testCases {
+		versions := []string{}
+		cursor := ""
+		for {
+			query, err := NewClusterCheckinHistoryQuery(clusterID, tc.since, tc.until, tc.limit, cursor)
+			assert.NoErr(t, err)
+			page, err := store.GetClusterCheckinHistory(query)
+			assert.NoErr(t, err)
+			assert.True(t, len(page.Data) <= tc.limit, "test case %d: page had %d checkins, over the limit", i, len(page.Data))
+			for _, checkin := range page.Data {
+				assert.True(t, checkin.ID != "", "test case %d: checkin had no ID", i)
+				versions = append(versions, checkin.Components[0].Version.Version)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+		assert.Equal(t, versions, tc.versions, fmt.Sprintf("test case %d versions", i))
+	}
+
+	query, err := NewClusterCheckinHistoryQuery(clusterID, time.Time{}, time.Time{}, 1, "")
+	assert.NoErr(t, err)
+	page, err := store.GetClusterCheckinHistory(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, time.Time(page.Data[0].CheckedInAt).Unix(), start.Unix(), "checkin time")
+
+	query, err = NewClusterCheckinHistoryQuery("nosuchcluster", time.Time{}, time.Time{}, 10, "")
+	assert.NoErr(t, err)
+	page, err = store.GetClusterCheckinHistory(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(page.Data), 0, "number of checkins")
+	assert.Equal(t, page.NextCursor, "", "next cursor")
+}
+
+func testStoreGetClusterCheckin(t *testing.T, store Store) {
+	start := time.Now().UTC().Truncate(time.Second)
+	offsets := []time.Duration{0, time.Hour, time.Hour, 2 * time.Hour}
+	for i, offset := range offsets {
+		cluster := testCluster()
+		cluster.Components[0].Version.Version = fmt.Sprintf("2.%d.0", i)
+		assert.NoErr(t, store.CheckInCluster(clusterID, start.Add(offset), cluster))
+	}
+	assert.NoErr(t, store.CheckInCluster("othercluster", start, models.Cluster{ID: "othercluster"}))
+
+	type testCase struct {
+		at      time.Time
+		version string
+	}
+	testCases := []testCase{
+		{at: start, version: "2.0.0"},
+		{at: start.Add(30 * time.Minute), version: "2.0.0"},
+		// the later of two checkins at the same time wins
+		{at: start.Add(time.Hour), version: "2.2.0"},
+		{at: start.Add(10 * time.Hour), version: "2.3.0"},
+	}
+	for i, tc := range testCases {
+		checkin, err := store.GetClusterCheckinAt(clusterID, tc.at)
+		assert.NoErr(t, err)
+		assert.Equal(t, checkin.Components[0].Version.Version, tc.version, fmt.Sprintf("test case %d version", i))
+
+		byID, err := store.GetClusterCheckinByID(clusterID, checkin.ID)
+		assert.NoErr(t, err)
+		assert.Equal(t, byID, checkin, fmt.Sprintf("test case %d checkin", i))
+	}
+	_, err := store.GetClusterCheckinAt(clusterID, start.Add(-time.Second))
+	assert.Err(t, ErrNotFound, err)
+
+	last, err := store.GetLastCheckinTime(clusterID)
+	assert.NoErr(t, err)
+	assert.True(t, last.Equal(start.Add(2*time.Hour)), "last checkin time was %s, expected %s", last, start.Add(2*time.Hour))
+	_, err = store.GetLastCheckinTime("nocluster")
+	assert.Err(t, ErrNotFound, err)
+
+	other, err := store.GetClusterCheckinAt("othercluster", start)
+	assert.NoErr(t, err)
+	// a checkin can only be found by ID through the cluster it belongs to
+	_, err = store.GetClusterCheckinByID(clusterID, other.ID)
+	assert.Err(t, ErrNotFound, err)
+	_, err = store.GetClusterCheckinByID(clusterID, "notanid")
+	assert.Err(t, ErrNotFound, err)
+}
+
+func testStoreCompactCheckins(t *testing.T, store Store) {
+	today := truncateToDay(time.Now())
+	twoDaysAgo := today.AddDate(0, 0, -2)
+	checkins := []struct {
+		id string
+		t  time.Time
+	}{
+		{"cluster1", twoDaysAgo.Add(time.Hour)},
+		{"cluster1", twoDaysAgo.Add(2 * time.Hour)},
+		{"cluster1", today.Add(time.Minute)},
+		{"cluster2", twoDaysAgo.Add(3 * time.Hour)},
+	}
+	for _, checkin := range checkins {
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, models.Cluster{ID: checkin.id}))
+		_, err := store.UpsertCluster(checkin.id, models.Cluster{ID: checkin.id})
+		assert.NoErr(t, err)
+	}
+	// UpsertCluster also checks in at the current time, so those checkins aren't compacted
+	query, err := NewClusterCheckinHistoryQuery("cluster1", time.Time{}, time.Time{}, 100, "")
+	assert.NoErr(t, err)
+	before, err := store.GetClusterCheckinHistory(query)
+	assert.NoErr(t, err)
+
+	n, err := store.CompactCheckins(today.AddDate(0, 0, -1))
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 3, "number of compacted checkins")
+	after, err := store.GetClusterCheckinHistory(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(after.Data), len(before.Data)-2, "number of checkins in the history")
+
+	// compacted checkins still count as the time each cluster was first seen
+	filter, err := NewClusterAgeFilter(
+		time.Now().Add(time.Hour),
+		today.AddDate(0, 0, -3),
+		today.AddDate(0, 0, -1),
+		today.AddDate(0, 0, -3),
+	)
+	assert.NoErr(t, err)
+	clusters, _, err := store.FilterClustersByAge(filter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(clusters), 2, "number of clusters")
+	assert.Equal(t, clusters[0].ID, "cluster1", "first cluster ID")
+	assert.Equal(t, clusters[1].ID, "cluster2", "second cluster ID")
+
+	n, err = store.CompactCheckins(today.AddDate(0, 0, -1))
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 0, "number of compacted checkins")
+
+	// a late checkin on a day that was already compacted is merged into that day's summary, so
+	// cluster2 is now first seen before cluster1
+	assert.NoErr(t, store.CheckInCluster("cluster2", twoDaysAgo.Add(time.Minute), models.Cluster{ID: "cluster2"}))
+	n, err = store.CompactCheckins(today.AddDate(0, 0, -1))
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 1, "number of compacted checkins")
+	clusters, _, err = store.FilterClustersByAge(filter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(clusters), 2, "number of clusters")
+	assert.Equal(t, clusters[0].ID, "cluster2", "first cluster ID")
+	assert.Equal(t, clusters[1].ID, "cluster1", "second cluster ID")
+}
+
+func testStoreClusterChurn(t *testing.T, store Store) {
+	start := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	window := DormancyWindow(30)
+	assert.NoErr(t, store.CheckInCluster("cluster1", start, models.Cluster{ID: "cluster1"}))
+	assert.NoErr(t, store.CheckInCluster("cluster2", start.AddDate(0, 0, 40), models.Cluster{ID: "cluster2"}))
+	// cluster3 went dormant and came back twice before detection first ran, then went dormant again
+	// on June 11th
+	for _, days := range []int{-100, -60, -20} {
+		assert.NoErr(t, store.CheckInCluster("cluster3", start.AddDate(0, 0, days), models.Cluster{ID: "cluster3"}))
+	}
+	n, err := store.DetectDormantClusters(start.AddDate(0, 0, 35), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 6, "number of events")
+	// the cluster is already dormant, so nothing new is recorded
+	n, err = store.DetectDormantClusters(start.AddDate(0, 0, 35), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 0, "number of events")
+
+	assert.NoErr(t, store.CheckInCluster("cluster1", start.AddDate(0, 0, 50), models.Cluster{ID: "cluster1"}))
+	n, err = store.DetectDormantClusters(start.AddDate(0, 0, 60), window)
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 1, "number of events")
+
+	query, err := NewClusterChurnQuery(start, start.AddDate(0, 0, 60), "month")
+	assert.NoErr(t, err)
+	churn, err := store.GetClusterChurn(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(churn.Data), 2, "number of periods")
+	// new, retained, reactivated and lost clusters. cluster1 went dormant on July 1st and came
+	// back on July 21st
+	expected := [][]int64{{1, 0, 0, 1}, {1, 0, 1, 1}}
+	for i, period := range churn.Data {
+		counts := []int64{*period.New, *period.Retained, *period.Reactivated, *period.Lost}
+		assert.Equal(t, counts, expected[i], fmt.Sprintf("period %d counts", i))
+	}
+}
+
+func testStoreClusterCohorts(t *testing.T, store Store) {
+	start := time.Date(2016, time.June, 6, 0, 0, 0, 0, time.UTC)
+	checkins := []struct {
+		id string
+		t  time.Time
+	}{
+		// before the first cohort, so it isn't reported
+		{"cluster0", start.AddDate(0, 0, -1)},
+		{"cluster0", start.AddDate(0, 0, 1)},
+		{"cluster1", start.Add(time.Hour)},
+		{"cluster1", start.AddDate(0, 0, 15)},
+		{"cluster2", start.AddDate(0, 0, 2)},
+		{"cluster2", start.AddDate(0, 0, 8)},
+		{"cluster3", start.AddDate(0, 0, 9)},
+		// after until, so it isn't counted
+		{"cluster3", start.AddDate(0, 0, 22)},
+	}
+	for _, checkin := range checkins {
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, models.Cluster{ID: checkin.id}))
+	}
+	query, err := NewClusterCohortsQuery(start.AddDate(0, 0, 3), start.AddDate(0, 0, 21), "week")
+	assert.NoErr(t, err)
+	cohorts, err := store.GetClusterCohorts(query)
+	assert.NoErr(t, err)
+	expected := []struct {
+		start     string
+		clusters  int64
+		retention []float64
+	}{
+		{"2016-06-06", 2, []float64{1, 0.5, 0.5}},
+		{"2016-06-13", 1, []float64{1, 0}},
+		{"2016-06-20", 0, []float64{0}},
+	}
+	assert.Equal(t, len(cohorts.Data), len(expected), "number of cohorts")
+	for i, cohort := range cohorts.Data {
+		assert.Equal(t, cohort.Start, expected[i].start, fmt.Sprintf("cohort %d start", i))
+		assert.Equal(t, *cohort.Clusters, expected[i].clusters, fmt.Sprintf("cohort %d clusters", i))
+		assert.Equal(t, cohort.Retention, expected[i].retention, fmt.Sprintf("cohort %d retention", i))
+	}
+}
+
+func testStoreActiveClusterCount(t *testing.T, store Store) {
+	now := time.Date(2016, time.June, 6, 12, 0, 0, 0, time.UTC)
+	assert.NoErr(t, store.CheckInCluster("cluster1", now.Add(-48*time.Hour), models.Cluster{ID: "cluster1"}))
+	assert.NoErr(t, store.CheckInCluster("cluster2", now.Add(-48*time.Hour), models.Cluster{ID: "cluster2"}))
+	assert.NoErr(t, store.CheckInCluster("cluster2", now.Add(-time.Hour), models.Cluster{ID: "cluster2"}))
+	assert.NoErr(t, store.CheckInCluster("cluster3", now.Add(-time.Hour), models.Cluster{ID: "cluster3"}))
+	for i, expected := range []struct {
+		since time.Time
+		count int
+	}{
+		{since: now.Add(-72 * time.Hour), count: 3},
+		{since: now.Add(-24 * time.Hour), count: 2},
+		{since: now, count: 0},
+	} {
+		count, err := store.GetActiveClusterCount(expected.since)
+		assert.NoErr(t, err)
+		assert.Equal(t, count, expected.count, fmt.Sprintf("test case %d active cluster count", i))
+	}
+}
+
+func testStoreDoctorRoundTrip(t *testing.T, store Store) {
+	const reportID = "testreport"
+	_, err := store.GetDoctor(reportID)
+	assert.True(t, err != nil, "error not returned when expected")
+	doctor := models.DoctorInfo{Workflow: &models.Cluster{ID: clusterID}}
+	_, err = store.UpsertDoctor(reportID, doctor)
+	assert.NoErr(t, err)
+	_, err = store.UpsertDoctor(reportID, doctor)
+	assert.NoErr(t, err)
+	getDoctor, err := store.GetDoctor(reportID)
+	assert.NoErr(t, err)
+	assert.Equal(t, getDoctor.Workflow.ID, clusterID, "doctor workflow cluster ID")
+	count, err := store.GetDoctorCount()
+	assert.NoErr(t, err)
+	assert.Equal(t, count, 1, "doctor count")
+}
+
+func testStorePublisherTokens(t *testing.T, store Store) {
+	_, err := store.GetPublisherToken("notatoken")
+	assert.True(t, err != nil, "error not returned when expected")
+
+	token, created, err := store.CreatePublisherToken("ci", []string{componentName}, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, created.Name, "ci", "token name")
+	_, _, err = store.CreatePublisherToken("ci", nil, nil)
+	assert.Equal(t, err, ErrPublisherTokenExists, "returned error")
+	_, _, err = store.CreatePublisherToken("admin", nil, nil)
+	assert.NoErr(t, err)
+
+	got, err := store.GetPublisherToken(token)
+	assert.NoErr(t, err)
+	assert.Equal(t, got.Name, "ci", "token name")
+	assert.True(t, got.CanPublish(train, componentName), "token can't publish a component in its scope")
+	assert.False(t, got.CanPublish(train, "othercomponent"), "token can publish a component outside its scope")
+
+	list, err := store.ListPublisherTokens()
+	assert.NoErr(t, err)
+	assert.Equal(t, len(list), 2, "number of tokens")
+	assert.Equal(t, list[0].Name, "admin", "token name")
+
+	assert.NoErr(t, store.RevokePublisherToken("ci"))
+	assert.True(t, store.RevokePublisherToken("ci") != nil, "error not returned when expected")
+	_, err = store.GetPublisherToken(token)
+	assert.True(t, err != nil, "revoked token was returned")
+}
+
+func testStoreFilterClusterCheckins(t *testing.T, store Store, now time.Time) {
+	checkins := []struct {
+		id string
+		t  time.Time
+	}{
+		{"old", now.Add(-72 * time.Hour)},
+		{"old", now.Add(-2 * time.Hour)},
+		{"new", now.Add(-3 * time.Hour)},
+		{"once", now.Add(-48 * time.Hour)},
+	}
+	for _, checkin := range checkins {
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, models.Cluster{ID: checkin.id}))
+	}
+
+	checkinsFilter, err := NewClusterCheckinsFilter(now.Add(-100*time.Hour), now)
+	assert.NoErr(t, err)
+	results, _, err := store.FilterClusterCheckins(checkinsFilter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(results), 3, "number of results")
+	// newest first
+	assert.Equal(t, results[0].ClusterID, "new", "cluster ID")
+	assert.Equal(t, results[2].ClusterID, "old", "cluster ID")
+	assert.Equal(t, results[2].Checkins, int64(2), "number of checkins")
+	assert.Equal(t, results[2].ClusterAge, "2 days 22:00:00", "cluster age")
+
+	persistentFilter, err := NewPersistentClustersFilter(now.Add(-100*time.Hour), now)
+	assert.NoErr(t, err)
+	results, _, err = store.FilterPersistentClusters(persistentFilter, nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(results), 1, "number of results")
+	assert.Equal(t, results[0].ClusterID, "old", "cluster ID")
+	assert.Equal(t, results[0].LastCheckin, "", "last checkin")
+}
+
+func testStoreFilterClusterCheckinsPages(t *testing.T, store Store) {
+	now := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"a", "b", "c"} {
+		assert.NoErr(t, store.CheckInCluster(id, now.Add(-time.Hour), models.Cluster{ID: id}))
+	}
+	assert.NoErr(t, store.CheckInCluster("d", now.Add(-2*time.Hour), models.Cluster{ID: "d"}))
+	filter, err := NewClusterCheckinsFilter(now.Add(-100*time.Hour), now)
+	assert.NoErr(t, err)
+
+	page, err := NewClusterPage(2, "")
+	assert.NoErr(t, err)
+	results, next, err := store.FilterClusterCheckins(filter, page)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(results), 2, "number of results")
+	// newest first, with ties in descending cluster ID order
+	assert.Equal(t, results[0].ClusterID, "c", "cluster ID")
+	assert.Equal(t, results[1].ClusterID, "b", "cluster ID")
+
+	page, err = NewClusterPage(2, next)
+	assert.NoErr(t, err)
+	results, next, err = store.FilterClusterCheckins(filter, page)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(results), 2, "number of results")
+	assert.Equal(t, results[0].ClusterID, "a", "cluster ID")
+	assert.Equal(t, results[1].ClusterID, "d", "cluster ID")
+	assert.Equal(t, next, "", "next cursor")
+}
+
+func testStoreComponentAdoption(t *testing.T, store Store) {
+	for _, vsn := range []string{"2.0.0", "2.1.0"} {
+		cv := testComponentVersion()
+		cv.Version.Version = vsn
+		_, err := store.UpsertVersion(*cv)
+		assert.NoErr(t, err)
+	}
+	clusterVersions := map[string]string{"cluster1": "2.0.0", "cluster2": "2.1.0", "cluster3": "2.1.0"}
+	for id, vsn := range clusterVersions {
+		cluster := testCluster()
+		cluster.ID = id
+		cluster.Components[0].Version.Version = vsn
+		_, err := store.UpsertCluster(id, cluster)
+		assert.NoErr(t, err)
+	}
+	// a cluster that upgraded is only counted for the version in its latest checkin
+	cluster := testCluster()
+	cluster.ID = "cluster1"
+	cluster.Components[0].Version.Version = "2.1.0"
+	_, err := store.UpsertCluster("cluster1", cluster)
+	assert.NoErr(t, err)
+
+	adoption, err := store.GetComponentAdoption(train, componentName)
+	assert.NoErr(t, err)
+	assert.Equal(t, *adoption.Clusters, int64(3), "number of clusters")
+	assert.Equal(t, len(adoption.Versions), 2, "number of versions")
+	assert.Equal(t, adoption.Versions[0].Version, "2.1.0", "version")
+	assert.Equal(t, *adoption.Versions[0].Clusters, int64(3), "number of clusters")
+	assert.Equal(t, adoption.Versions[1].Version, "2.0.0", "version")
+	assert.Equal(t, *adoption.Versions[1].Clusters, int64(0), "number of clusters")
+
+	adoption, err = store.GetComponentAdoption("beta", componentName)
+	assert.NoErr(t, err)
+	assert.Equal(t, *adoption.Clusters, int64(0), "number of clusters")
+	assert.Equal(t, len(adoption.Versions), 0, "number of versions")
+}
+
+func testStoreReleaseAdoption(t *testing.T, store Store) {
+	released := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	cv := testComponentVersion()
+	cv.Version.Version = "2.0.0"
+	cv.Version.Released = released.Format(StdTimestampFmt)
+	_, err := store.UpsertVersion(*cv)
+	assert.NoErr(t, err)
+
+	checkins := []struct {
+		id      string
+		version string
+		t       time.Time
+	}{
+		// before the release, so it isn't counted
+		{"cluster1", "2.0.0", released.Add(-time.Hour)},
+		{"cluster1", "2.0.0", released.Add(time.Hour)},
+		// the same cluster checking in twice on the same day is only counted once
+		{"cluster1", "2.0.0", released.Add(2 * time.Hour)},
+		{"cluster2", "1.0.0", released.Add(2 * time.Hour)},
+		{"cluster2", "2.0.0", released.Add(24 * time.Hour)},
+		{"cluster1", "2.0.0", released.Add(25 * time.Hour)},
+	}
+	for _, checkin := range checkins {
+		cluster := testCluster()
+		cluster.ID = checkin.id
+		cluster.Components[0].Version.Version = checkin.version
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, cluster))
+	}
+
+	adoption, err := store.GetReleaseAdoption(train, componentName, "2.0.0", released.Add(48*time.Hour))
+	assert.NoErr(t, err)
+	assert.Equal(t, len(adoption.Data), 3, "number of days")
+	expected := []int64{1, 2, 0}
+	for i, day := range adoption.Data {
+		assert.Equal(t, *day.Clusters, expected[i], "number of clusters on "+day.Date)
+	}
+
+	_, err = store.GetReleaseAdoption(train, componentName, "3.0.0", released)
+	assert.Err(t, ErrNotFound, err)
+}
+
+func testStoreActiveClusters(t *testing.T, store Store) {
+	since := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	checkins := []struct {
+		id string
+		t  time.Time
+	}{
+		// before since, so it isn't counted
+		{"cluster1", since.Add(-time.Hour)},
+		{"cluster1", since.Add(time.Hour)},
+		{"cluster1", since.Add(2 * time.Hour)},
+		{"cluster2", since.Add(3 * time.Hour)},
+		{"cluster2", since.Add(49 * time.Hour)},
+		// at until, so it isn't counted
+		{"cluster3", since.Add(72 * time.Hour)},
+	}
+	for _, checkin := range checkins {
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, models.Cluster{ID: checkin.id}))
+	}
+	query, err := NewActiveClustersQuery(since, since.Add(72*time.Hour), "day")
+	assert.NoErr(t, err)
+	active, err := store.GetActiveClusters(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(active.Data), 3, "number of buckets")
+	expected := []int64{2, 0, 1}
+	for i, bucket := range active.Data {
+		assert.Equal(t, *bucket.Clusters, expected[i], "number of clusters on "+bucket.Start)
+	}
+
+	query, err = NewActiveClustersQuery(since, since.Add(72*time.Hour), "month")
+	assert.NoErr(t, err)
+	active, err = store.GetActiveClusters(query)
+	assert.NoErr(t, err)
+	assert.Equal(t, len(active.Data), 1, "number of buckets")
+	assert.Equal(t, *active.Data[0].Clusters, int64(2), "number of clusters")
+}
+
+func testStoreCompactedCheckinStats(t *testing.T, store Store) {
+	now := time.Date(2016, time.June, 10, 12, 0, 0, 0, time.UTC)
+	released := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	cv := testComponentVersion()
+	cv.Version.Version = "2.0.0"
+	cv.Version.Released = released.Format(StdTimestampFmt)
+	_, err := store.UpsertVersion(*cv)
+	assert.NoErr(t, err)
+	checkins := []struct {
+		id      string
+		version string
+		t       time.Time
+	}{
+		{"cluster1", "2.0.0", released.Add(time.Hour)},
+		{"cluster1", "2.0.0", released.Add(2 * time.Hour)},
+		{"cluster2", "1.0.0", released.Add(3 * time.Hour)},
+		{"cluster2", "2.0.0", released.Add(26 * time.Hour)},
+		{"cluster1", "2.0.0", now.Add(-time.Hour)},
+		{"cluster3", "2.0.0", now.Add(-2 * time.Hour)},
+	}
+	for _, checkin := range checkins {
+		cluster := testCluster()
+		cluster.ID = checkin.id
+		cluster.Components[0].Version.Version = checkin.version
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, cluster))
+	}
+
+	// compacting checkins doesn't change any of the filter or stats results
+	checkinsFilter, err := NewClusterCheckinsFilter(released.Add(-time.Hour), now)
+	assert.NoErr(t, err)
+	persistentFilter, err := NewPersistentClustersFilter(released.Add(-time.Hour), now)
+	assert.NoErr(t, err)
+	activeQuery, err := NewActiveClustersQuery(released, now, "day")
+	assert.NoErr(t, err)
+	type results struct {
+		checkins   []*models.ClusterCheckin
+		persistent []*models.ClusterCheckin
+		adoption   models.ReleaseAdoption
+		active     models.ActiveClusters
+	}
+	getResults := func() results {
+		ret := results{}
+		ret.checkins, _, err = store.FilterClusterCheckins(checkinsFilter, nil)
+		assert.NoErr(t, err)
+		// the time since each cluster's last checkin is relative to the current time, so it changes
+		// between calls
+		for _, checkin := range ret.checkins {
+			checkin.LastCheckin = ""
+		}
+		ret.persistent, _, err = store.FilterPersistentClusters(persistentFilter, nil)
+		assert.NoErr(t, err)
+		ret.adoption, err = store.GetReleaseAdoption(train, componentName, "2.0.0", now)
+		assert.NoErr(t, err)
+		ret.active, err = store.GetActiveClusters(activeQuery)
+		assert.NoErr(t, err)
+		return ret
+	}
+	before := getResults()
+	n, err := store.CompactCheckins(CompactionCutoff(now, 3))
+	assert.NoErr(t, err)
+	assert.Equal(t, n, 4, "number of compacted checkins")
+	after := getResults()
+	assert.Equal(t, after.checkins, before.checkins, "cluster checkins")
+	assert.Equal(t, after.persistent, before.persistent, "persistent clusters")
+	assert.Equal(t, after.adoption, before.adoption, "release adoption")
+	assert.Equal(t, after.active, before.active, "active clusters")
+	assert.Equal(t, len(before.checkins), 3, "number of clusters")
+	assert.Equal(t, before.checkins[2].Checkins, int64(3), "number of checkins")
+}
@@ -1,7 +1,12 @@
 package data
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
 )
 
 type txErr struct {
@@ -16,3 +21,45 @@ func (t txErr) Error() string {
 	}
 	return fmt.Sprintf("%s transaction error (%s)", t.op, t.err)
 }
+
+// createUnlessExistsSavepoint is the name of the savepoint that createUnlessExists rolls back to
+const createUnlessExistsSavepoint = "create_unless_exists"
+
+// isUniqueViolation returns true if err is from a statement that violated a primary key or unique
+// constraint. Postgres reports these with an error code, and sqlite only in the error message
+func isUniqueViolation(err error) bool {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return pqErr.Code.Name() == "unique_violation"
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || strings.Contains(msg, "must be unique")
+}
+
+// createUnlessExists inserts row, and returns false instead of an error if a row with the same
+// primary key already exists. Callers check that the row doesn't exist first, so that only happens
+// when a concurrent transaction inserts the same row between the check and the insert. A failed
+// statement aborts the rest of a Postgres transaction, so if db is a transaction the insert runs in
+// a savepoint, which is rolled back if the row exists
+func createUnlessExists(db *gorm.DB, row interface{}) (bool, error) {
+	_, inTx := db.CommonDB().(*sql.Tx)
+	if inTx {
+		if err := db.Exec("SAVEPOINT " + createUnlessExistsSavepoint).Error; err != nil {
+			return false, err
+		}
+	}
+	createErr := db.Create(row).Error
+	if createErr != nil && !isUniqueViolation(createErr) {
+		return false, createErr
+	}
+	if inTx {
+		if createErr != nil {
+			if err := db.Exec("ROLLBACK TO SAVEPOINT " + createUnlessExistsSavepoint).Error; err != nil {
+				return false, err
+			}
+		}
+		if err := db.Exec("RELEASE SAVEPOINT " + createUnlessExistsSavepoint).Error; err != nil {
+			return false, err
+		}
+	}
+	return createErr == nil, nil
+}
@@ -0,0 +1,77 @@
+package data
+
+import (
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/jinzhu/gorm"
+)
+
+// SetUpdatesAvailable compares each of cluster's components against the latest release on its
+// train and sets UpdateAvailable to the latest version string for every component that's behind.
+// UpdateAvailable is cleared for every other component, including components that report no train
+// or whose component/train has no published releases that haven't been yanked. It also sets
+// Deprecation for every component that runs a deprecated or yanked release, and clears it for
+// every other component. Components running a yanked release have UpdateAvailable set to the
+// latest release, even if it's older than the yanked one
+func SetUpdatesAvailable(db *gorm.DB, cluster *models.Cluster) error {
+	return setUpdatesAvailable(cluster, func(ct []ComponentAndTrain) (map[ComponentAndTrain][]versionsTable, releaseDeprecations, error) {
+		return getVersionsByComponentAndTrain(db, ct)
+	})
+}
+
+// versionsGetter is the func that setUpdatesAvailable calls to get all the versions for each
+// component/train pair and their deprecations. It should have the same semantics as
+// getVersionsByComponentAndTrain
+type versionsGetter func(ct []ComponentAndTrain) (map[ComponentAndTrain][]versionsTable, releaseDeprecations, error)
+
+func setUpdatesAvailable(cluster *models.Cluster, getVersions versionsGetter) error {
+	ct := []ComponentAndTrain{}
+	for _, cv := range cluster.Components {
+		if cv == nil {
+			continue
+		}
+		cv.UpdateAvailable = nil
+		cv.Deprecation = nil
+		if cv.Component == nil || cv.Version == nil || cv.Version.Train == "" {
+			continue
+		}
+		ct = append(ct, *componentAndTrainFromComponentVersion(cv))
+	}
+	grouped, deprecations, err := getVersions(ct)
+	if err != nil {
+		return err
+	}
+	latest := make(map[ComponentAndTrain]versionsTable, len(grouped))
+	for key, versions := range grouped {
+		if latestVsn, ok := latestVersion(versions, deprecations); ok {
+			latest[key] = latestVsn
+		}
+	}
+
+	for _, cv := range cluster.Components {
+		if cv == nil || cv.Component == nil || cv.Version == nil {
+			continue
+		}
+		key := *componentAndTrainFromComponentVersion(cv)
+		// use the published release for the running version if there is one, so that its release
+		// timestamp can be used for comparisons. otherwise, the zero timestamp means the running
+		// version is only ever newer if it has a higher semantic version
+		running := versionsTable{ComponentName: key.ComponentName, Train: key.Train, Version: cv.Version.Version}
+		for _, vsn := range grouped[key] {
+			if vsn.Version == cv.Version.Version {
+				running = vsn
+				break
+			}
+		}
+		cv.Deprecation = deprecations.forVersion(running)
+		latestVsn, ok := latest[key]
+		if !ok || latestVsn.Version == cv.Version.Version {
+			continue
+		}
+		// clusters running a yanked release should move to the latest one, even if it's older
+		if deprecations.yanked(running) || isNewerVersion(latestVsn, running) {
+			updateAvailable := latestVsn.Version
+			cv.UpdateAvailable = &updateAvailable
+		}
+	}
+	return nil
+}
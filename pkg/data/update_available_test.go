@@ -0,0 +1,50 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+func TestSetUpdatesAvailable(t *testing.T) {
+	db, err := newDB()
+	assert.NoErr(t, err)
+	for i, vsn := range []string{"2.0.0-rc1", "2.0.0", "2.1.0"} {
+		cv := testComponentVersion()
+		cv.Version.Version = vsn
+		cv.Version.Released = time.Now().Add(time.Duration(i) * time.Hour).Format(released)
+		_, err := UpsertVersion(db, *cv)
+		assert.NoErr(t, err)
+	}
+
+	newComponentVersion := func(name, train, vsn string) *models.ComponentVersion {
+		return &models.ComponentVersion{
+			Component:       &models.Component{Name: name},
+			Version:         &models.Version{Train: train, Version: vsn},
+			UpdateAvailable: &updateAvailable,
+		}
+	}
+	cluster := models.Cluster{
+		ID: clusterID,
+		Components: []*models.ComponentVersion{
+			// behind
+			newComponentVersion(componentName, train, "2.0.0"),
+			// up to date
+			newComponentVersion(componentName, train, "2.1.0"),
+			// ahead of the latest release
+			newComponentVersion(componentName, train, "2.2.0-rc1"),
+			// no releases on this train
+			newComponentVersion(componentName, "notatrain", "1.0.0"),
+			// no train
+			newComponentVersion(componentName, "", "1.0.0"),
+		},
+	}
+	assert.NoErr(t, SetUpdatesAvailable(db, &cluster))
+	assert.True(t, cluster.Components[0].UpdateAvailable != nil, "expected an update for component 0")
+	assert.Equal(t, *cluster.Components[0].UpdateAvailable, "2.1.0", "available update")
+	for i := 1; i < len(cluster.Components); i++ {
+		assert.Nil(t, cluster.Components[i].UpdateAvailable, "available update")
+	}
+}
@@ -27,7 +27,11 @@ func upsertVersion(db *gorm.DB, queryExisting versionsTable, setNew versionsTabl
 	if queryDB.Error != nil {
 		return nil, queryDB.Error
 	}
-	cv, err := parseDBVersion(ret)
+	deprecations, err := getReleaseDeprecations(db, []string{ret.ComponentName}, []string{ret.Train})
+	if err != nil {
+		return nil, err
+	}
+	cv, err := parseDBVersion(ret, deprecations)
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,6 @@
 package data
 
 import (
-	"database/sql"
 	"encoding/json"
 	"log"
 
@@ -54,16 +53,26 @@ func UpsertVersion(db *gorm.DB, componentVersion models.ComponentVersion) (model
 	return *cvPtr, nil
 }
 
-// GetLatestVersion gets the latest version from the DB for the given train & component
+// GetLatestVersion gets the latest version from the DB for the given train & component. See
+// latestVersion for how "latest" is determined. Returns ErrNotFound if the train has no releases
+// that haven't been yanked
 func GetLatestVersion(db *gorm.DB, train string, component string) (models.ComponentVersion, error) {
-	resTable := new(versionsTable)
+	var rows []versionsTable
 	query := versionsTable{ComponentName: component, Train: train}
-	resDB := db.Where(query).Order("release_timestamp desc").First(resTable)
+	resDB := db.Where(query).Find(&rows)
 	if resDB.Error != nil {
 		return models.ComponentVersion{}, resDB.Error
 	}
+	deprecations, err := getReleaseDeprecations(db, []string{component}, []string{train})
+	if err != nil {
+		return models.ComponentVersion{}, err
+	}
+	latest, ok := latestVersion(rows, deprecations)
+	if !ok {
+		return models.ComponentVersion{}, ErrNotFound
+	}
 
-	componentVersion, err := parseDBVersion(*resTable)
+	componentVersion, err := parseDBVersion(latest, deprecations)
 	if err != nil {
 		return models.ComponentVersion{}, err
 	}
@@ -74,14 +83,51 @@ func GetLatestVersion(db *gorm.DB, train string, component string) (models.Compo
 // given in ct. Returns an empty slice and non-nil error on any error communicating with the
 // database or otherwise if the first returned value is not empty, it's guaranteed to:
 //
-// - Be the same length as ct
+// - Have at most one element for each distinct component/train pair in ct. Pairs with no
+//   published versions, or whose versions have all been yanked, are omitted
 // - Have the same ordering as ct, with respect to the component name
 func GetLatestVersions(db *gorm.DB, ct []ComponentAndTrain) ([]*models.ComponentVersion, error) {
+	grouped, deprecations, err := getVersionsByComponentAndTrain(db, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	// pick the latest from each group in the order they were requested
+	rowsResult := []versionsTable{}
+	for _, c := range ct {
+		candidates, ok := grouped[c]
+		if !ok {
+			continue
+		}
+		if latest, ok := latestVersion(candidates, deprecations); ok {
+			rowsResult = append(rowsResult, latest)
+		}
+		// make sure duplicate component/train pairs in ct only produce a single result
+		delete(grouped, c)
+	}
+
+	componentVersions, err := parseDBVersions(rowsResult, deprecations)
+	if err != nil {
+		return []*models.ComponentVersion{}, err
+	}
+	return componentVersions, nil
+}
+
+// getVersionsByComponentAndTrain fetches all versions for each component/train pair in ct, grouped
+// by component and train, and their deprecations. Pairs that have no versions won't have a key in
+// the returned map
+func getVersionsByComponentAndTrain(db *gorm.DB, ct []ComponentAndTrain) (map[ComponentAndTrain][]versionsTable, releaseDeprecations, error) {
+	grouped := make(map[ComponentAndTrain][]versionsTable)
+	if len(ct) == 0 {
+		return grouped, releaseDeprecations{}, nil
+	}
 	componentsList := []string{}
 	listedComponents := make(map[string]struct{})
 	trainsList := []string{}
 	listedTrains := make(map[string]struct{})
+	requested := make(map[ComponentAndTrain]struct{})
 	for _, c := range ct {
+		requested[c] = struct{}{}
 		if _, componentListed := listedComponents[c.ComponentName]; !componentListed {
 			componentsList = append(componentsList, c.ComponentName)
 			listedComponents[c.ComponentName] = struct{}{}
@@ -91,39 +137,25 @@ func GetLatestVersions(db *gorm.DB, ct []ComponentAndTrain) ([]*models.Component
 			listedTrains[c.Train] = struct{}{}
 		}
 	}
-	rows, err := db.Raw("select * from versions as ver where ver.component_name IN (?) AND ver.train IN (?) AND release_timestamp = (select MAX(release_timestamp) from versions as ver1 where ver1.component_name = ver.component_name AND ver1.train = ver.train)", componentsList, trainsList).
-		Rows()
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	rowsResult := []versionsTable{}
-	for rows.Next() {
-		var row versionsTable
-		// note that we have to pass in a *sql.NullString as the first and last arg to ignore the
-		// primary key and the final release timestamp returned from the MAX aggregate function
-		// in the above SQL
-		if err = rows.Scan(
-			&sql.NullString{},
-			&row.ComponentName,
-			&row.Train,
-			&row.Version,
-			&row.ReleaseTimestamp,
-			&row.Data,
-		); err != nil {
-			return nil, err
-		}
-		rowsResult = append(rowsResult, row)
-	}
-	if rErr := rows.Err(); rErr != nil {
-		return nil, rErr
+	var rows []versionsTable
+	resDB := db.Where("component_name IN (?) AND train IN (?)", componentsList, trainsList).Find(&rows)
+	if resDB.Error != nil {
+		return nil, nil, resDB.Error
 	}
-
-	componentVersions, err := parseDBVersions(rowsResult)
+	deprecations, err := getReleaseDeprecations(db, componentsList, trainsList)
 	if err != nil {
-		return []*models.ComponentVersion{}, err
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		key := ComponentAndTrain{ComponentName: row.ComponentName, Train: row.Train}
+		// the IN clauses match the cross product of components and trains, so skip the pairs that
+		// weren't asked for
+		if _, ok := requested[key]; !ok {
+			continue
+		}
+		grouped[key] = append(grouped[key], row)
 	}
-	return componentVersions, nil
+	return grouped, deprecations, nil
 }
 
 // GetVersion gets a single version record from a DB matching the unique property values in a ComponentVersion struct
@@ -137,8 +169,12 @@ func GetVersion(db *gorm.DB, cV models.ComponentVersion) (models.ComponentVersio
 	if resDB.Error != nil {
 		return models.ComponentVersion{}, resDB.Error
 	}
+	deprecations, err := getReleaseDeprecations(db, []string{resTable.ComponentName}, []string{resTable.Train})
+	if err != nil {
+		return models.ComponentVersion{}, err
+	}
 
-	componentVersion, err := parseDBVersion(*resTable)
+	componentVersion, err := parseDBVersion(*resTable, deprecations)
 	if err != nil {
 		return models.ComponentVersion{}, err
 	}
@@ -152,7 +188,11 @@ func GetVersionsList(db *gorm.DB, train string, component string) ([]*models.Com
 	if resDB.Error != nil {
 		return nil, resDB.Error
 	}
-	componentVersions, err := parseDBVersions(rowsResult)
+	deprecations, err := getReleaseDeprecations(db, []string{component}, []string{train})
+	if err != nil {
+		return nil, err
+	}
+	componentVersions, err := parseDBVersions(rowsResult, deprecations)
 	if err != nil {
 		log.Println("error parsing DB versions data")
 		return nil, err
@@ -160,10 +200,10 @@ func GetVersionsList(db *gorm.DB, train string, component string) ([]*models.Com
 	return componentVersions, nil
 }
 
-func parseDBVersions(versions []versionsTable) ([]*models.ComponentVersion, error) {
+func parseDBVersions(versions []versionsTable, deprecations releaseDeprecations) ([]*models.ComponentVersion, error) {
 	componentVersions := make([]*models.ComponentVersion, len(versions))
 	for i, version := range versions {
-		cver, err := parseDBVersion(version)
+		cver, err := parseDBVersion(version, deprecations)
 		if err != nil {
 			return nil, err
 		}
@@ -172,7 +212,7 @@ func parseDBVersions(versions []versionsTable) ([]*models.ComponentVersion, erro
 	return componentVersions, nil
 }
 
-func parseDBVersion(version versionsTable) (models.ComponentVersion, error) {
+func parseDBVersion(version versionsTable, deprecations releaseDeprecations) (models.ComponentVersion, error) {
 	data := models.VersionData{}
 	if err := json.Unmarshal([]byte(version.Data), &data); err != nil {
 		return models.ComponentVersion{}, err
@@ -181,6 +221,7 @@ func parseDBVersion(version versionsTable) (models.ComponentVersion, error) {
 		Component: &models.Component{
 			Name: version.ComponentName,
 		},
+		Deprecation: deprecations.forVersion(version),
 		Version: &models.Version{
 			Train:    version.Train,
 			Version:  version.Version,
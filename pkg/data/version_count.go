@@ -0,0 +1,32 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+)
+
+// versionCountResponse type that represents a row of the version count query
+type versionCountResponse struct {
+	Train    string `gorm:"column_name:train"`
+	Versions int    `gorm:"column_name:versions"`
+}
+
+// GetVersionCountsByTrain returns the number of releases on each train, keyed on train name
+func GetVersionCountsByTrain(db *gorm.DB) (map[string]int, error) {
+	var rows []versionCountResponse
+	execDB := db.Raw(fmt.Sprintf(
+		"SELECT %s AS train, COUNT(*) AS versions FROM %s GROUP BY %s",
+		versionsTableTrainKey,
+		versionsTableName,
+		versionsTableTrainKey,
+	)).Find(&rows)
+	if execDB.Error != nil {
+		return nil, execDB.Error
+	}
+	ret := make(map[string]int, len(rows))
+	for _, row := range rows {
+		ret[row.Train] = row.Versions
+	}
+	return ret, nil
+}
@@ -154,3 +154,40 @@ func TestGetLatestVersions(t *testing.T) {
 		}
 	}
 }
+
+func TestGetLatestVersionSemVer(t *testing.T) {
+	sqliteDB, err := NewMemDB()
+	assert.NoErr(t, err)
+	assert.NoErr(t, VerifyPersistentStorage(sqliteDB))
+
+	// each version is listed with its release time offset, in hours. 2.0.0 should win even though
+	// the hotfix for 1.9.1 was released after it and 2.0.0-rc2 was re-published later still
+	versionOffsets := []struct {
+		version string
+		offset  int
+	}{
+		{version: "2.0.0-rc1", offset: 0},
+		{version: "2.0.0", offset: 2},
+		{version: "1.9.1", offset: 3},
+		{version: "2.0.0-rc2", offset: 4},
+		{version: "not-a-semver", offset: 5},
+	}
+	for i, vo := range versionOffsets {
+		cv := testComponentVersion()
+		cv.Version.Version = vo.version
+		cv.Version.Released = time.Now().Add(time.Duration(vo.offset) * time.Hour).Format(released)
+		if _, setErr := UpsertVersion(sqliteDB, *cv); setErr != nil {
+			t.Fatalf("error setting component version %d (%s)", i, setErr)
+		}
+	}
+	cv, err := GetLatestVersion(sqliteDB, train, componentName)
+	assert.NoErr(t, err)
+	assert.Equal(t, cv.Version.Version, "2.0.0", "latest version")
+
+	cvs, err := GetLatestVersions(sqliteDB, []ComponentAndTrain{
+		ComponentAndTrain{ComponentName: componentName, Train: train},
+	})
+	assert.NoErr(t, err)
+	assert.Equal(t, len(cvs), 1, "number of returned components")
+	assert.Equal(t, cvs[0].Version.Version, "2.0.0", "latest version")
+}
@@ -3,8 +3,6 @@ package data
 import (
 	"database/sql"
 	"fmt"
-
-	"github.com/jinzhu/gorm"
 )
 
 const (
@@ -31,7 +29,7 @@ func (v versionsTable) TableName() string {
 	return versionsTableName
 }
 
-func createOrUpdateVersionsTable(db *gorm.DB) (sql.Result, error) {
+func createVersionsTable(db execer) (sql.Result, error) {
 	query := fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %s ( %s bigserial PRIMARY KEY, %s varchar(32), %s varchar(24), %s varchar(32), %s timestamp, %s json, unique (%s, %s, %s) )",
 		versionsTableName,
@@ -45,5 +43,5 @@ func createOrUpdateVersionsTable(db *gorm.DB) (sql.Result, error) {
 		versionsTableTrainKey,
 		versionsTableVersionKey,
 	)
-	return db.DB().Exec(query)
+	return db.Exec(query)
 }
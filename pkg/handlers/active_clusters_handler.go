@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+func parseActiveClustersParams(params operations.GetActiveClustersParams) (*data.ActiveClustersQuery, error) {
+	until := time.Now()
+	if params.Until != nil {
+		until = time.Time(*params.Until)
+	}
+	interval := *operations.NewGetActiveClustersParams().Interval
+	if params.Interval != nil {
+		interval = *params.Interval
+	}
+	return data.NewActiveClustersQuery(time.Time(params.Since), until, interval)
+}
+
+// ActiveClusters is the handler for the GET /v3/clusters/active endpoint
+func ActiveClusters(params operations.GetActiveClustersParams, store data.Store) middleware.Responder {
+	query, err := parseActiveClustersParams(params)
+	if err != nil {
+		code, payload := errorPayload(err, "active clusters")
+		return operations.NewGetActiveClustersDefault(code).WithPayload(payload)
+	}
+	active, err := store.GetActiveClusters(query)
+	if err != nil {
+		log.Printf("data.GetActiveClusters error (%s)", err)
+		code, payload := errorPayload(err, "active clusters")
+		return operations.NewGetActiveClustersDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetActiveClustersOK().WithPayload(&active)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/ratelimit"
+)
+
+// CheckinLimits are the limits on how often clusters can check in. The zero value doesn't limit
+// check-ins at all
+type CheckinLimits struct {
+	// PerCluster limits check-ins by cluster ID, and PerIP limits them by source IP. Check-ins over
+	// either limit get a 429 response. Either can be nil, which doesn't limit check-ins
+	PerCluster *ratelimit.Limiter
+	PerIP      *ratelimit.Limiter
+	// MinInterval is the shortest time between a cluster's recorded check-ins. Check-ins sooner than
+	// that after the cluster's last one are acknowledged, but not recorded
+	MinInterval time.Duration
+	// TrustForwardedFor indicates that the source IP is the last address in the X-Forwarded-For
+	// header, if there is one. That's the address that the proxy in front of the server appended.
+	// The addresses before it are sent by the client, so they can't be trusted. Only set it when the
+	// server is behind a single proxy that appends to the header
+	TrustForwardedFor bool
+}
+
+// sourceIP returns the IP address that r came from, or an empty string if r is nil
+func (c CheckinLimits) sourceIP(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if c.TrustForwardedFor {
+		// the header can be sent more than once, and proxies append to the last one
+		if headers := r.Header["X-Forwarded-For"]; len(headers) > 0 {
+			addrs := strings.Split(headers[len(headers)-1], ",")
+			if last := strings.TrimSpace(addrs[len(addrs)-1]); last != "" {
+				return last
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allow returns whether a check-in from clusterID, sent in r, is within c's rate limits. If it
+// isn't, it also returns how long the cluster should wait before checking in again
+func (c CheckinLimits) allow(clusterID string, r *http.Request, now time.Time) (bool, time.Duration) {
+	if ip := c.sourceIP(r); ip != "" {
+		if ok, wait := c.PerIP.Allow(ip, now); !ok {
+			return false, wait
+		}
+	}
+	return c.PerCluster.Allow(clusterID, now)
+}
+
+// recentlyCheckedIn returns whether lastCheckin is less than c.MinInterval before now
+func (c CheckinLimits) recentlyCheckedIn(lastCheckin, now time.Time) bool {
+	return now.Sub(lastCheckin) < c.MinInterval
+}
+
+// retryAfterSeconds returns wait as a whole number of seconds for a Retry-After header. It's
+// rounded up, so that clients that wait that long will be allowed
+func retryAfterSeconds(wait time.Duration) int64 {
+	seconds := int64((wait + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
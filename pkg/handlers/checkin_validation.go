@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/strfmt"
+	"github.com/pborman/uuid"
+)
+
+const (
+	// maxCheckinComponents is the most components that a single check-in can report
+	maxCheckinComponents = 100
+	// maxComponentNameLen and maxTrainLen are the widths of the versions table's component_name and
+	// train columns
+	maxComponentNameLen = 64
+	maxTrainLen         = 64
+	// maxVersionLen is the width of the versions table's version column
+	maxVersionLen = 32
+	// maxCheckinStringLen is the longest that any other string in a check-in can be
+	maxCheckinStringLen = 1024
+)
+
+// fieldErrors flattens err, which is returned by a swagger model's Validate method, into a field
+// error for each of the invalid fields. prefix is the path of the model in the request body
+func fieldErrors(prefix string, err error) []*models.FieldError {
+	switch e := err.(type) {
+	case *errors.CompositeError:
+		var ret []*models.FieldError
+		for _, child := range e.Errors {
+			ret = append(ret, fieldErrors(prefix, child)...)
+		}
+		return ret
+	case *errors.Validation:
+		return []*models.FieldError{{Field: prefix + e.Name, Message: e.Error()}}
+	default:
+		return []*models.FieldError{{Field: strings.TrimSuffix(prefix, "."), Message: err.Error()}}
+	}
+}
+
+// checkLen returns a field error if value is longer than max bytes, and nil otherwise
+func checkLen(field, value string, max int) []*models.FieldError {
+	if len(value) <= max {
+		return nil
+	}
+	return []*models.FieldError{{Field: field, Message: fmt.Sprintf("must be at most %d characters long", max)}}
+}
+
+// checkOptionalLen is checkLen for optional strings. A nil value is valid
+func checkOptionalLen(field string, value *string, max int) []*models.FieldError {
+	if value == nil {
+		return nil
+	}
+	return checkLen(field, *value, max)
+}
+
+// validateComponentVersion returns a field error for each of the invalid fields of cv, which is
+// the component at index i of a check-in
+func validateComponentVersion(i int, cv *models.ComponentVersion) []*models.FieldError {
+	prefix := fmt.Sprintf("components[%d].", i)
+	if cv == nil {
+		return []*models.FieldError{{Field: strings.TrimSuffix(prefix, "."), Message: "is required"}}
+	}
+	var ret []*models.FieldError
+	if component := cv.Component; component == nil {
+		ret = append(ret, &models.FieldError{Field: prefix + "component", Message: "is required"})
+	} else {
+		if err := component.Validate(strfmt.Default); err != nil {
+			ret = append(ret, fieldErrors(prefix+"component.", err)...)
+		}
+		ret = append(ret, checkLen(prefix+"component.name", component.Name, maxComponentNameLen)...)
+		ret = append(ret, checkOptionalLen(prefix+"component.type", component.Type, maxCheckinStringLen)...)
+		ret = append(ret, checkOptionalLen(prefix+"component.description", component.Description, maxCheckinStringLen)...)
+	}
+	if version := cv.Version; version != nil {
+		if err := version.Validate(strfmt.Default); err != nil {
+			ret = append(ret, fieldErrors(prefix+"version.", err)...)
+		}
+		ret = append(ret, checkLen(prefix+"version.train", version.Train, maxTrainLen)...)
+		ret = append(ret, checkLen(prefix+"version.version", version.Version, maxVersionLen)...)
+		ret = append(ret, checkLen(prefix+"version.released", version.Released, maxCheckinStringLen)...)
+	}
+	ret = append(ret, checkOptionalLen(prefix+"updateAvailable", cv.UpdateAvailable, maxCheckinStringLen)...)
+	return ret
+}
+
+// validateCheckin returns a field error for each of the invalid fields of a cluster check-in, or
+// nil if it's valid. The cluster ID must be a UUID, since it's stored in a uuid column
+func validateCheckin(cluster models.Cluster) []*models.FieldError {
+	var ret []*models.FieldError
+	if err := cluster.Validate(strfmt.Default); err != nil {
+		ret = append(ret, fieldErrors("", err)...)
+	}
+	if cluster.ID != "" && uuid.Parse(cluster.ID) == nil {
+		ret = append(ret, &models.FieldError{Field: "id", Message: "must be a UUID"})
+	}
+	if len(cluster.Components) > maxCheckinComponents {
+		// don't validate each of the components, so that the response stays small
+		msg := fmt.Sprintf("must have at most %d items", maxCheckinComponents)
+		return append(ret, &models.FieldError{Field: "components", Message: msg})
+	}
+	for i, cv := range cluster.Components {
+		ret = append(ret, validateComponentVersion(i, cv)...)
+	}
+	return ret
+}
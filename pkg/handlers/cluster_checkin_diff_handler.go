@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/deis/workflow-manager-api/rest"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/strfmt"
+)
+
+type errInvalidCheckinSelector struct {
+	timeKey string
+	idKey   string
+}
+
+func (e errInvalidCheckinSelector) Error() string {
+	return fmt.Sprintf("exactly one of %s and %s is required", e.timeKey, e.idKey)
+}
+
+// checkinSelector selects a single checkin of a cluster, either by time or by checkin ID
+type checkinSelector struct {
+	t  *strfmt.DateTime
+	id *string
+}
+
+func newCheckinSelector(t *strfmt.DateTime, id *string, timeKey, idKey string) (checkinSelector, error) {
+	if (t == nil) == (id == nil) {
+		return checkinSelector{}, errInvalidCheckinSelector{timeKey: timeKey, idKey: idKey}
+	}
+	return checkinSelector{t: t, id: id}, nil
+}
+
+func (c checkinSelector) get(store data.Store, clusterID string) (models.ClusterCheckinRecord, error) {
+	if c.id != nil {
+		return store.GetClusterCheckinByID(clusterID, *c.id)
+	}
+	return store.GetClusterCheckinAt(clusterID, time.Time(*c.t))
+}
+
+// ClusterCheckinDiff is the handler for the GET /v3/clusters/{id}/checkins/diff endpoint
+func ClusterCheckinDiff(params operations.GetClusterCheckinDiffParams, store data.Store) middleware.Responder {
+	if _, err := store.GetCluster(params.ID); err != nil {
+		log.Printf("data.GetCluster error (%s)", err)
+		code, payload := errorPayload(err, "cluster")
+		return operations.NewGetClusterCheckinDiffDefault(code).WithPayload(payload)
+	}
+	fromSelector, err := newCheckinSelector(params.From, params.FromCheckin, rest.FromQueryStringKey, rest.FromCheckinQueryStringKey)
+	if err != nil {
+		code, payload := errorPayload(err, "checkin")
+		return operations.NewGetClusterCheckinDiffDefault(code).WithPayload(payload)
+	}
+	toSelector, err := newCheckinSelector(params.To, params.ToCheckin, rest.ToQueryStringKey, rest.ToCheckinQueryStringKey)
+	if err != nil {
+		code, payload := errorPayload(err, "checkin")
+		return operations.NewGetClusterCheckinDiffDefault(code).WithPayload(payload)
+	}
+
+	checkins := make([]models.ClusterCheckinRecord, 2)
+	for i, selector := range []checkinSelector{fromSelector, toSelector} {
+		checkin, err := selector.get(store, params.ID)
+		if err != nil {
+			log.Printf("Error getting cluster checkin (%s)", err)
+			code, payload := errorPayload(err, "checkin")
+			return operations.NewGetClusterCheckinDiffDefault(code).WithPayload(payload)
+		}
+		checkins[i] = checkin
+	}
+	diff := data.DiffClusterCheckins(params.ID, checkins[0], checkins[1])
+	return operations.NewGetClusterCheckinDiffOK().WithPayload(&diff)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+func parseCheckinHistoryParams(params operations.GetClusterCheckinHistoryParams) (*data.ClusterCheckinHistoryQuery, error) {
+	var since, until time.Time
+	if params.Since != nil {
+		since = time.Time(*params.Since)
+	}
+	if params.Until != nil {
+		until = time.Time(*params.Until)
+	}
+	limit := *operations.NewGetClusterCheckinHistoryParams().Limit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	cursor := ""
+	if params.Cursor != nil {
+		cursor = *params.Cursor
+	}
+	return data.NewClusterCheckinHistoryQuery(params.ID, since, until, int(limit), cursor)
+}
+
+// ClusterCheckinHistory is the handler for the GET /v3/clusters/{id}/checkins endpoint
+func ClusterCheckinHistory(params operations.GetClusterCheckinHistoryParams, store data.Store) middleware.Responder {
+	if _, err := store.GetCluster(params.ID); err != nil {
+		log.Printf("data.GetCluster error (%s)", err)
+		code, payload := errorPayload(err, "cluster")
+		return operations.NewGetClusterCheckinHistoryDefault(code).WithPayload(payload)
+	}
+	query, err := parseCheckinHistoryParams(params)
+	if err != nil {
+		code, payload := errorPayload(err, "checkin history")
+		return operations.NewGetClusterCheckinHistoryDefault(code).WithPayload(payload)
+	}
+	history, err := store.GetClusterCheckinHistory(query)
+	if err != nil {
+		log.Printf("data.GetClusterCheckinHistory error (%s)", err)
+		code, payload := errorPayload(err, "checkin history")
+		return operations.NewGetClusterCheckinHistoryDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetClusterCheckinHistoryOK().WithPayload(&history)
+}
@@ -2,28 +2,48 @@ package handlers
 
 import (
 	"log"
-	"net/http"
 
 	"github.com/deis/workflow-manager-api/pkg/data"
 	"github.com/deis/workflow-manager-api/pkg/swagger/models"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
-	"github.com/jinzhu/gorm"
 )
 
 // ClusterCheckins is the handler for the GET /{apiVersion}/clusters/checkins endpoint
-func ClusterCheckins(params operations.GetClusterCheckinsParams, db *gorm.DB) middleware.Responder {
+func ClusterCheckins(params operations.GetClusterCheckinsParams, store data.Store) middleware.Responder {
+	fail := func(code int, payload *models.Error) middleware.Responder {
+		return operations.NewGetClusterCheckinsDefault(code).WithPayload(payload)
+	}
 	clusterCheckinsFilter, err := parseCheckinsQueryKeys(params)
 	if err != nil {
-		return operations.NewGetClusterCheckinsDefault(http.StatusBadRequest).WithPayload(&models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "cluster checkins")))
 	}
 
-	checkins, err := data.FilterClusterCheckins(db, clusterCheckinsFilter)
+	page, err := parseClusterPage(params.Limit, operations.NewGetClusterCheckinsParams().Limit, params.Cursor)
 	if err != nil {
-		log.Printf("Error filtering cluster checkins (%s)", err)
-		return operations.NewGetClusterCheckinsDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "cluster checkins")))
+	}
+
+	list := func() middleware.Responder {
+		checkins, next, err := store.FilterClusterCheckins(clusterCheckinsFilter, page)
+		if err != nil {
+			log.Printf("Error filtering cluster checkins (%s)", err)
+			return fail(errorPayload(err, "cluster checkins"))
+		}
+		numResults := int64(len(checkins))
+		clustersCount := models.ClustersCount{Count: &numResults, Data: checkins, NextCursor: next}
+		return operations.NewGetClusterCheckinsOK().WithXNextCursor(next).WithPayload(&clustersCount)
+	}
+	if !acceptsExport(params.HTTPRequest) {
+		return list()
+	}
+	return exportResponder{
+		list: list,
+		export: func() (data.ClusterRows, error) {
+			return store.ExportClusterCheckins(clusterCheckinsFilter, page)
+		},
+		fail:      fail,
+		csvHeader: checkinCSVHeader,
+		resource:  "cluster checkins",
 	}
-	numResults := int64(len(checkins))
-	clustersCount := models.ClustersCount{Count: &numResults, Data: checkins}
-	return operations.NewGetClusterCheckinsOK().WithPayload(&clustersCount)
 }
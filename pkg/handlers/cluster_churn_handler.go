@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+func parseClusterChurnParams(params operations.GetClusterChurnParams) (*data.ClusterChurnQuery, error) {
+	until := time.Now()
+	if params.Until != nil {
+		until = time.Time(*params.Until)
+	}
+	interval := *operations.NewGetClusterChurnParams().Interval
+	if params.Interval != nil {
+		interval = *params.Interval
+	}
+	return data.NewClusterChurnQuery(time.Time(params.Since), until, interval)
+}
+
+// ClusterChurn is the handler for the GET /v3/clusters/churn endpoint
+func ClusterChurn(params operations.GetClusterChurnParams, store data.Store) middleware.Responder {
+	query, err := parseClusterChurnParams(params)
+	if err != nil {
+		code, payload := errorPayload(err, "cluster churn")
+		return operations.NewGetClusterChurnDefault(code).WithPayload(payload)
+	}
+	churn, err := store.GetClusterChurn(query)
+	if err != nil {
+		log.Printf("data.GetClusterChurn error (%s)", err)
+		code, payload := errorPayload(err, "cluster churn")
+		return operations.NewGetClusterChurnDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetClusterChurnOK().WithPayload(&churn)
+}
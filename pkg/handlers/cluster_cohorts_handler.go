@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+func parseClusterCohortsParams(params operations.GetClusterCohortsParams) (*data.ClusterCohortsQuery, error) {
+	until := time.Now()
+	if params.Until != nil {
+		until = time.Time(*params.Until)
+	}
+	interval := *operations.NewGetClusterCohortsParams().Interval
+	if params.Interval != nil {
+		interval = *params.Interval
+	}
+	return data.NewClusterCohortsQuery(time.Time(params.Since), until, interval)
+}
+
+// ClusterCohorts is the handler for the GET /v3/clusters/cohorts endpoint
+func ClusterCohorts(params operations.GetClusterCohortsParams, store data.Store) middleware.Responder {
+	query, err := parseClusterCohortsParams(params)
+	if err != nil {
+		code, payload := errorPayload(err, "cluster cohorts")
+		return operations.NewGetClusterCohortsDefault(code).WithPayload(payload)
+	}
+	cohorts, err := store.GetClusterCohorts(query)
+	if err != nil {
+		log.Printf("data.GetClusterCohorts error (%s)", err)
+		code, payload := errorPayload(err, "cluster cohorts")
+		return operations.NewGetClusterCohortsDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetClusterCohortsOK().WithPayload(&cohorts)
+}
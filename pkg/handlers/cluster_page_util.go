@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/deis/workflow-manager-api/pkg/data"
+)
+
+// parseClusterPage returns the page that the limit and cursor query parameters of a cluster list
+// endpoint select. defaultLimit is the limit to use if limit is nil
+func parseClusterPage(limit, defaultLimit *int64, cursor *string) (*data.ClusterPage, error) {
+	if limit == nil {
+		limit = defaultLimit
+	}
+	cursorStr := ""
+	if cursor != nil {
+		cursorStr = *cursor
+	}
+	return data.NewClusterPage(int(*limit), cursorStr)
+}
@@ -2,26 +2,46 @@ package handlers
 
 import (
 	"log"
-	"net/http"
 
 	"github.com/deis/workflow-manager-api/pkg/data"
 	"github.com/deis/workflow-manager-api/pkg/swagger/models"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
-	"github.com/jinzhu/gorm"
 )
 
 // ClustersAge is the handler for the GET /{apiVersion}/clusters/age endpoint
-func ClustersAge(params operations.GetClustersByAgeParams, db *gorm.DB) middleware.Responder {
+func ClustersAge(params operations.GetClustersByAgeParams, store data.Store) middleware.Responder {
+	fail := func(code int, payload *models.Error) middleware.Responder {
+		return operations.NewGetClustersByAgeDefault(code).WithPayload(payload)
+	}
 	clusterAgeFilter, err := parseAgeQueryKeys(params)
 	if err != nil {
-		return operations.NewGetClustersByAgeDefault(http.StatusBadRequest).WithPayload(&models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "clusters")))
 	}
 
-	clusters, err := data.FilterClustersByAge(db, clusterAgeFilter)
+	page, err := parseClusterPage(params.Limit, operations.NewGetClustersByAgeParams().Limit, params.Cursor)
 	if err != nil {
-		log.Printf("Error filtering clusters by age (%s)", err)
-		return operations.NewGetClustersByAgeDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "clusters")))
+	}
+
+	list := func() middleware.Responder {
+		clusters, next, err := store.FilterClustersByAge(clusterAgeFilter, page)
+		if err != nil {
+			log.Printf("Error filtering clusters by age (%s)", err)
+			return fail(errorPayload(err, "clusters"))
+		}
+		return operations.NewGetClustersByAgeOK().WithXNextCursor(next).WithPayload(operations.GetClustersByAgeOKBodyBody{Data: clusters, NextCursor: next})
+	}
+	if !acceptsExport(params.HTTPRequest) {
+		return list()
+	}
+	return exportResponder{
+		list: list,
+		export: func() (data.ClusterRows, error) {
+			return store.ExportClustersByAge(clusterAgeFilter, page)
+		},
+		fail:      fail,
+		csvHeader: clusterCSVHeader,
+		resource:  "clusters",
 	}
-	return operations.NewGetClustersByAgeOK().WithPayload(operations.GetClustersByAgeOKBodyBody{Data: clusters})
 }
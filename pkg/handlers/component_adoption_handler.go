@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// ComponentAdoption is the handler for the GET /v3/stats/adoption/{train}/{component} endpoint
+func ComponentAdoption(params operations.GetComponentAdoptionParams, store data.Store) middleware.Responder {
+	adoption, err := store.GetComponentAdoption(params.Train, params.Component)
+	if err != nil {
+		log.Printf("data.GetComponentAdoption error (%s)", err)
+		code, payload := errorPayload(err, "component")
+		return operations.NewGetComponentAdoptionDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetComponentAdoptionOK().WithPayload(&adoption)
+}
@@ -5,4 +5,8 @@ const (
 	ContentTypeHeaderKey = "Content-Type"
 	// JSONContentType is the value of the "Content-Type" header passed for JSON data
 	JSONContentType = "application/json"
+	// CSVContentType is the value of the "Content-Type" header passed for CSV data
+	CSVContentType = "text/csv"
+	// NDJSONContentType is the value of the "Content-Type" header passed for newline delimited JSON
+	NDJSONContentType = "application/x-ndjson"
 )
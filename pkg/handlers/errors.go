@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+// reasonStatus is the status code of the response to an error with each data.Reason
+var reasonStatus = map[data.Reason]int{
+	data.ReasonNotFound:      http.StatusNotFound,
+	data.ReasonConflict:      http.StatusConflict,
+	data.ReasonInvalidFilter: http.StatusBadRequest,
+	data.ReasonUnavailable:   http.StatusServiceUnavailable,
+	data.ReasonInternal:      http.StatusInternalServerError,
+}
+
+// Reason returns data.ReasonInvalidFilter
+func (e errInvalidTimeFmt) Reason() data.Reason {
+	return data.ReasonInvalidFilter
+}
+
+// Reason returns data.ReasonInvalidFilter
+func (e errInvalidCheckinSelector) Reason() data.Reason {
+	return data.ReasonInvalidFilter
+}
+
+// errorPayload returns the status code and payload of the response to err, which was returned
+// while getting or writing resource. The messages of client errors are returned as is. The messages
+// of other errors can contain database details, so they're replaced with generic ones, and callers
+// should log err instead
+func errorPayload(err error, resource string) (int, *models.Error) {
+	reason := data.ReasonOf(err)
+	code := reasonStatus[reason]
+	var msg string
+	switch reason {
+	case data.ReasonNotFound:
+		msg = fmt.Sprintf("%d %s not found", code, resource)
+	case data.ReasonConflict, data.ReasonInvalidFilter:
+		msg = data.Cause(err).Error()
+	case data.ReasonUnavailable:
+		msg = fmt.Sprintf("%d database unavailable, try again later", code)
+	default:
+		msg = fmt.Sprintf("%d internal server error", code)
+	}
+	return code, &models.Error{Code: int64(code), Message: msg, Reason: string(reason)}
+}
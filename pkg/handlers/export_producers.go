@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/strfmt"
+)
+
+var (
+	clusterCSVHeader = []string{"id", "firstSeen", "lastSeen", "components"}
+	checkinCSVHeader = []string{"clusterID", "firstSeen", "lastSeen", "lastCheckin", "clusterAge", "checkins"}
+)
+
+// exportRows is the payload that exportResponder passes to an exportProducer. csvHeader is the
+// header row of the CSV format
+type exportRows struct {
+	rows      data.ClusterRows
+	csvHeader []string
+}
+
+// exportProducer is the httpkit.Producer of an export format. exportResponder streams the rows of
+// a cluster list to it, instead of loading a page of them into memory first
+type exportProducer func(w io.Writer, export exportRows) error
+
+// Produce writes payload. exportRows are written in the producer's format. Other payloads are
+// written as JSON, because go-swagger uses the producer that the client asked for for every
+// response to the request. Handlers write their errors with jsonResponder, so that the
+// Content-Type matches
+func (e exportProducer) Produce(w io.Writer, payload interface{}) error {
+	export, ok := payload.(exportRows)
+	if !ok {
+		return json.NewEncoder(w).Encode(payload)
+	}
+	return e(w, export)
+}
+
+// formatDateTime returns t formatted the same way as it is in JSON responses, or an empty string
+// if t is nil
+func formatDateTime(t *strfmt.DateTime) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// csvRecord returns the CSV columns of a row returned by data.ClusterRows. A cluster's components
+// are flattened into a single column of name@version pairs, separated by semicolons
+func csvRecord(row interface{}) []string {
+	switch r := row.(type) {
+	case *models.Cluster:
+		components := []string{}
+		for _, cv := range r.Components {
+			if cv == nil || cv.Component == nil {
+				continue
+			}
+			component := cv.Component.Name
+			if cv.Version != nil {
+				component += "@" + cv.Version.Version
+			}
+			components = append(components, component)
+		}
+		return []string{
+			r.ID,
+			formatDateTime(r.FirstSeen),
+			formatDateTime(r.LastSeen),
+			strings.Join(components, ";"),
+		}
+	case *models.ClusterCheckin:
+		return []string{
+			r.ClusterID,
+			r.FirstSeen,
+			r.LastSeen,
+			r.LastCheckin,
+			r.ClusterAge,
+			strconv.FormatInt(r.Checkins, 10),
+		}
+	default:
+		return nil
+	}
+}
+
+// flush sends everything written to w so far to the client, if w is an http.ResponseWriter that
+// supports it
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// CSVProducer returns the producer for "text/csv" responses. It writes a header row, then one row
+// for each cluster or cluster checkin in the /clusters/age, /clusters/checkins and
+// /clusters/persistent responses. Each row is written and flushed as soon as it's read from the
+// database
+func CSVProducer() httpkit.Producer {
+	return exportProducer(func(w io.Writer, export exportRows) error {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(export.csvHeader); err != nil {
+			return err
+		}
+		writer.Flush()
+		flush(w)
+		for export.rows.Next() {
+			row, err := export.rows.Row()
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(csvRecord(row)); err != nil {
+				return err
+			}
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			flush(w)
+		}
+		return export.rows.Err()
+	})
+}
+
+// NDJSONProducer returns the producer for "application/x-ndjson" responses. It writes each cluster
+// or cluster checkin in the /clusters/age, /clusters/checkins and /clusters/persistent responses as
+// a JSON object on its own line, and flushes it as soon as it's read from the database
+func NDJSONProducer() httpkit.Producer {
+	return exportProducer(func(w io.Writer, export exportRows) error {
+		encoder := json.NewEncoder(w)
+		for export.rows.Next() {
+			row, err := export.rows.Row()
+			if err != nil {
+				return err
+			}
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+			flush(w)
+		}
+		return export.rows.Err()
+	})
+}
+
+// acceptsExport returns true if r's Accept header includes one of the export formats. go-swagger
+// picks the producer after the handler returns, so exportResponder checks which one it got
+func acceptsExport(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	for _, accept := range r.Header[httpkit.HeaderAccept] {
+		if strings.Contains(accept, CSVContentType) || strings.Contains(accept, NDJSONContentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonResponder is a middleware.Responder that writes another Responder's response as JSON, and
+// sets the Content-Type to match, whichever producer the client asked for. The cluster list
+// handlers write their errors with it
+type jsonResponder struct {
+	middleware.Responder
+}
+
+// WriteResponse is the middleware.Responder interface implementation
+func (j jsonResponder) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+	rw.Header().Set(ContentTypeHeaderKey, JSONContentType)
+	j.Responder.WriteResponse(rw, httpkit.JSONProducer())
+}
+
+// exportError returns resp, which is the response to a failed cluster list request, as a
+// jsonResponder if r asked for an export format
+func exportError(r *http.Request, resp middleware.Responder) middleware.Responder {
+	if acceptsExport(r) {
+		return jsonResponder{Responder: resp}
+	}
+	return resp
+}
+
+// exportResponder is the middleware.Responder for cluster list requests that asked for an export
+// format. If go-swagger picked an exportProducer, every row after the page's cursor is streamed
+// from the database to it, regardless of the page's limit. Otherwise it writes the page that list
+// returns
+type exportResponder struct {
+	list      func() middleware.Responder
+	export    func() (data.ClusterRows, error)
+	fail      func(code int, payload *models.Error) middleware.Responder
+	csvHeader []string
+	resource  string
+}
+
+// WriteResponse is the middleware.Responder interface implementation
+func (e exportResponder) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+	if _, ok := producer.(exportProducer); !ok {
+		e.list().WriteResponse(rw, producer)
+		return
+	}
+	rows, err := e.export()
+	if err != nil {
+		log.Printf("Error exporting %s (%s)", e.resource, err)
+		code, payload := errorPayload(err, e.resource)
+		jsonResponder{Responder: e.fail(code, payload)}.WriteResponse(rw, producer)
+		return
+	}
+	defer rows.Close()
+	rw.WriteHeader(http.StatusOK)
+	// the status has already been sent, so all we can do is stop writing rows
+	if err := producer.Produce(rw, exportRows{rows: rows, csvHeader: e.csvHeader}); err != nil {
+		log.Printf("Error writing %s export (%s)", e.resource, err)
+	}
+}
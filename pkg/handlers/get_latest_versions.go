@@ -5,10 +5,10 @@ import (
 	"net/http"
 
 	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/signing"
 	"github.com/deis/workflow-manager-api/pkg/swagger/models"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
-	"github.com/jinzhu/gorm"
 )
 
 // SparseComponentInfo is the JSON compatible struct that holds limited data about a component
@@ -42,7 +42,7 @@ type ComponentVersionsJSONWrapper struct {
 }
 
 // GetLatestVersions is the handler for the POST /{apiVersion}/versions/latest endpoint
-func GetLatestVersions(params operations.GetComponentsByLatestReleaseParams, db *gorm.DB) middleware.Responder {
+func GetLatestVersions(params operations.GetComponentsByLatestReleaseParams, store data.Store, signer *signing.Signer) middleware.Responder {
 	reqStruct := params.Body
 
 	componentAndTrainSlice := make([]data.ComponentAndTrain, len(reqStruct.Data))
@@ -53,17 +53,22 @@ func GetLatestVersions(params operations.GetComponentsByLatestReleaseParams, db
 		}
 	}
 
-	componentVersions, err := data.GetLatestVersions(db, componentAndTrainSlice)
+	componentVersions, err := store.GetLatestVersions(componentAndTrainSlice)
 	if err != nil {
 		log.Printf("data.GetLatestVersions error (%s)", err)
-		return operations.NewGetComponentsByLatestReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "database error"})
+		code, payload := errorPayload(err, "releases")
+		return operations.NewGetComponentsByLatestReleaseDefault(code).WithPayload(payload)
+	}
+	if err := signer.SignAll(componentVersions); err != nil {
+		log.Printf("signing.SignAll error (%s)", err)
+		return operations.NewGetComponentsByLatestReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign releases"})
 	}
 	ret := operations.GetComponentsByLatestReleaseOKBodyBody{Data: componentVersions}
 	return operations.NewGetComponentsByLatestReleaseOK().WithPayload(ret)
 }
 
 // GetLatestVersionsForV2 is the handler for the POST /v2/versions/latest endpoint
-func GetLatestVersionsForV2(params operations.GetComponentsByLatestReleaseForV2Params, db *gorm.DB) middleware.Responder {
+func GetLatestVersionsForV2(params operations.GetComponentsByLatestReleaseForV2Params, store data.Store, signer *signing.Signer) middleware.Responder {
 	reqStruct := params.Body
 	componentAndTrainSlice := make([]data.ComponentAndTrain, len(reqStruct.Data))
 	for i, d := range reqStruct.Data {
@@ -77,10 +82,15 @@ func GetLatestVersionsForV2(params operations.GetComponentsByLatestReleaseForV2P
 		}
 	}
 
-	componentVersions, err := data.GetLatestVersions(db, componentAndTrainSlice)
+	componentVersions, err := store.GetLatestVersions(componentAndTrainSlice)
 	if err != nil {
 		log.Printf("data.GetLatestVersions error (%s)", err)
-		return operations.NewGetComponentsByLatestReleaseForV2Default(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "database error"})
+		code, payload := errorPayload(err, "releases")
+		return operations.NewGetComponentsByLatestReleaseForV2Default(code).WithPayload(payload)
+	}
+	if err := signer.SignAll(componentVersions); err != nil {
+		log.Printf("signing.SignAll error (%s)", err)
+		return operations.NewGetComponentsByLatestReleaseForV2Default(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign releases"})
 	}
 	ret := operations.GetComponentsByLatestReleaseForV2OKBodyBody{Data: componentVersions}
 	return operations.NewGetComponentsByLatestReleaseForV2OK().WithPayload(ret)
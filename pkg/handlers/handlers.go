@@ -6,118 +6,236 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/signing"
 	"github.com/deis/workflow-manager-api/pkg/swagger/models"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
-	"github.com/jinzhu/gorm"
 )
 
 // ClustersCount route handler
-func ClustersCount(db *gorm.DB) middleware.Responder {
-	count, err := data.GetClusterCount(db)
+func ClustersCount(store data.Store) middleware.Responder {
+	count, err := store.GetClusterCount()
 	if err != nil {
 		log.Printf("data.GetClusterCount error (%s)", err)
-		return operations.NewGetClustersCountDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		code, payload := errorPayload(err, "clusters")
+		return operations.NewGetClustersCountDefault(code).WithPayload(payload)
 	}
 	return operations.NewGetClustersCountOK().WithPayload(int64(count))
 }
 
 // GetCluster route handler
-func GetCluster(params operations.GetClusterByIDParams, db *gorm.DB) middleware.Responder {
+func GetCluster(params operations.GetClusterByIDParams, store data.Store) middleware.Responder {
 	id := params.ID
-	cluster, err := data.GetCluster(db, id)
+	cluster, err := store.GetCluster(id)
 	if err != nil {
 		log.Printf("data.GetCluster error (%s)", err)
-		return operations.NewGetClusterByIDDefault(http.StatusNotFound).WithPayload(&models.Error{Code: http.StatusNotFound, Message: "404 cluster not found"})
+		code, payload := errorPayload(err, "cluster")
+		return operations.NewGetClusterByIDDefault(code).WithPayload(payload)
 	}
 	return operations.NewGetClusterByIDOK().WithPayload(&cluster)
 }
 
-// ClusterCheckin route handler
-func ClusterCheckin(params operations.CreateClusterDetailsParams, db *gorm.DB) middleware.Responder {
+// ClusterCheckin route handler. Check-ins over the rate limits in limits get a 429 response, and
+// check-ins sooner than limits.MinInterval after the cluster's last one are acknowledged with the
+// submitted cluster, but not recorded
+func ClusterCheckin(params operations.CreateClusterDetailsParams, store data.Store, limits CheckinLimits) middleware.Responder {
 	cluster := *params.Body
 	id := cluster.ID
+	if fields := validateCheckin(cluster); len(fields) > 0 {
+		log.Printf("cluster %s sent an invalid check-in (%d invalid fields)", id, len(fields))
+		return operations.NewCreateClusterDetailsUnprocessableEntity().WithPayload(&models.Error{Code: http.StatusUnprocessableEntity, Message: "invalid cluster check-in", Fields: fields})
+	}
+	now := time.Now()
+	if ok, wait := limits.allow(id, params.HTTPRequest, now); !ok {
+		log.Printf("cluster %s checked in too often", id)
+		return operations.NewCreateClusterDetailsTooManyRequests().WithRetryAfter(retryAfterSeconds(wait)).WithPayload(&models.Error{Code: http.StatusTooManyRequests, Message: "429 too many check-ins"})
+	}
+	if limits.MinInterval > 0 {
+		last, err := store.GetLastCheckinTime(id)
+		if err != nil && err != data.ErrNotFound {
+			log.Printf("data.GetLastCheckinTime error (%s)", err)
+		} else if err == nil && limits.recentlyCheckedIn(last, now) {
+			if err := store.SetUpdatesAvailable(&cluster); err != nil {
+				log.Printf("data.SetUpdatesAvailable error (%s)", err)
+			}
+			return operations.NewCreateClusterDetailsOK().WithPayload(&cluster)
+		}
+	}
 	var result models.Cluster
-	result, err := data.UpsertCluster(db, id, cluster)
+	result, err := store.UpsertCluster(id, cluster)
 	if err != nil {
 		log.Printf("data.SetCluster error (%s)", err)
-		return operations.NewCreateClusterDetailsDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		code, payload := errorPayload(err, "cluster")
+		return operations.NewCreateClusterDetailsDefault(code).WithPayload(payload)
+	}
+	// the check in has already been recorded at this point, so don't fail the request if we can't
+	// figure out which components have updates
+	if err := store.SetUpdatesAvailable(&result); err != nil {
+		log.Printf("data.SetUpdatesAvailable error (%s)", err)
 	}
 	return operations.NewCreateClusterDetailsOK().WithPayload(&result)
 }
 
 // GetVersion route handler
-func GetVersion(params operations.GetComponentByReleaseParams, db *gorm.DB) middleware.Responder {
+func GetVersion(params operations.GetComponentByReleaseParams, store data.Store, signer *signing.Signer) middleware.Responder {
 	train := params.Train
 	component := params.Component
 	version := params.Release
 	var cv models.ComponentVersion
 	var err error
 	if version == "latest" {
-		cv, err = data.GetLatestVersion(db, train, component)
+		cv, err = store.GetLatestVersion(train, component)
 	} else {
 		componentVersion := models.ComponentVersion{
 			Component: &models.Component{Name: component},
 			Version:   &models.Version{Train: train, Version: version},
 		}
-		cv, err = data.GetVersion(db, componentVersion)
+		cv, err = store.GetVersion(componentVersion)
 	}
 
 	if err != nil {
 		log.Printf("data.GetVersion error (%s)", err)
-		return operations.NewGetComponentByReleaseDefault(http.StatusNotFound).WithPayload(&models.Error{Code: http.StatusNotFound, Message: "404 release not found"})
+		code, payload := errorPayload(err, "release")
+		return operations.NewGetComponentByReleaseDefault(code).WithPayload(payload)
+	}
+	if err := signer.Sign(&cv); err != nil {
+		log.Printf("signing.Sign error (%s)", err)
+		return operations.NewGetComponentByReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign release"})
 	}
 	return operations.NewGetComponentByReleaseOK().WithPayload(&cv)
 }
 
 // GetComponentTrainVersions route handler
-func GetComponentTrainVersions(params operations.GetComponentByNameParams, db *gorm.DB) middleware.Responder {
+func GetComponentTrainVersions(params operations.GetComponentByNameParams, store data.Store, signer *signing.Signer) middleware.Responder {
 	train := params.Train
 	component := params.Component
-	componentVersions, err := data.GetVersionsList(db, train, component)
+	componentVersions, err := store.GetVersionsList(train, component)
 	if err != nil {
 		log.Printf("data.GetComponentTrainVersions error (%s)", err)
-		return operations.NewGetComponentByNameDefault(http.StatusNotFound).WithPayload(&models.Error{Code: http.StatusNotFound, Message: "404 component not found"})
+		code, payload := errorPayload(err, "component")
+		return operations.NewGetComponentByNameDefault(code).WithPayload(payload)
+	}
+	if err := signer.SignAll(componentVersions); err != nil {
+		log.Printf("signing.SignAll error (%s)", err)
+		return operations.NewGetComponentByNameDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign releases"})
 	}
 	return operations.NewGetComponentByNameOK().WithPayload(operations.GetComponentByNameOKBodyBody{Data: componentVersions})
 }
 
-// PublishVersion route handler
-func PublishVersion(params operations.PublishComponentReleaseParams, db *gorm.DB) middleware.Responder {
+// PublishVersion route handler. principal is the data.PublisherToken that authenticated the
+// request, and it must be allowed to publish releases for params.Component to params.Train
+func PublishVersion(params operations.PublishComponentReleaseParams, principal interface{}, store data.Store, signer *signing.Signer) middleware.Responder {
+	token, ok := principal.(data.PublisherToken)
+	if !ok || !token.CanPublish(params.Train, params.Component) {
+		log.Printf("publisher token not allowed to publish %s on train %s", params.Component, params.Train)
+		return operations.NewPublishComponentReleaseDefault(http.StatusForbidden).WithPayload(&models.Error{Code: http.StatusForbidden, Message: "403 publisher token not allowed to publish this component or train"})
+	}
 	componentVersion := *params.Body
 	//TODO: validate request body parameter values for "component", "train", and "version"
 	// match the values passed in with the URL
 	componentVersion.Component.Name = params.Component
 	componentVersion.Version.Train = params.Train
 	componentVersion.Version.Version = params.Release
-	result, err := data.UpsertVersion(db, componentVersion)
+	result, err := store.UpsertVersion(componentVersion)
 	if err != nil {
 		log.Printf("data.SetVersion error (%s)", err)
-		return operations.NewPublishComponentReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		code, payload := errorPayload(err, "release")
+		return operations.NewPublishComponentReleaseDefault(code).WithPayload(payload)
+	}
+	if err := signer.Sign(&result); err != nil {
+		log.Printf("signing.Sign error (%s)", err)
+		return operations.NewPublishComponentReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign release"})
 	}
 	return operations.NewPublishComponentReleaseOK().WithPayload(&result)
 }
 
+// maxDeprecationReasonLen is the width of the release_deprecations table's reason column
+const maxDeprecationReasonLen = 1024
+
+// validateDeprecation returns a field error for each of the invalid fields of a deprecation request
+// body, or nil if it's valid. Deprecating or yanking a release requires a reason
+func validateDeprecation(deprecation *models.Deprecation) []*models.FieldError {
+	if deprecation == nil {
+		return []*models.FieldError{{Field: "body", Message: "is required"}}
+	}
+	switch deprecation.Status {
+	case data.ReleaseActive:
+		return nil
+	case data.ReleaseDeprecated, data.ReleaseYanked:
+		if deprecation.Reason == "" {
+			return []*models.FieldError{{Field: "reason", Message: "is required"}}
+		}
+		return checkLen("reason", deprecation.Reason, maxDeprecationReasonLen)
+	default:
+		msg := fmt.Sprintf("must be one of %s, %s or %s", data.ReleaseActive, data.ReleaseDeprecated, data.ReleaseYanked)
+		return []*models.FieldError{{Field: "status", Message: msg}}
+	}
+}
+
+// DeprecateVersion route handler. It deprecates or yanks a published release, or makes it active
+// again. principal must be allowed to publish releases for params.Component to params.Train
+func DeprecateVersion(params operations.DeprecateComponentReleaseParams, principal interface{}, store data.Store, signer *signing.Signer) middleware.Responder {
+	token, ok := principal.(data.PublisherToken)
+	if !ok || !token.CanPublish(params.Train, params.Component) {
+		log.Printf("publisher token not allowed to deprecate %s on train %s", params.Component, params.Train)
+		return operations.NewDeprecateComponentReleaseDefault(http.StatusForbidden).WithPayload(&models.Error{Code: http.StatusForbidden, Message: "403 publisher token not allowed to deprecate releases of this component or train"})
+	}
+	if fields := validateDeprecation(params.Body); len(fields) > 0 {
+		return operations.NewDeprecateComponentReleaseUnprocessableEntity().WithPayload(&models.Error{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "invalid deprecation",
+			Fields:  fields,
+		})
+	}
+	deprecation := params.Body
+	if deprecation.Status == data.ReleaseActive {
+		deprecation = nil
+	}
+	result, err := store.SetVersionDeprecation(params.Train, params.Component, params.Release, deprecation)
+	if err != nil {
+		log.Printf("data.SetVersionDeprecation error (%s)", err)
+		code, payload := errorPayload(err, "release")
+		return operations.NewDeprecateComponentReleaseDefault(code).WithPayload(payload)
+	}
+	if err := signer.Sign(&result); err != nil {
+		log.Printf("signing.Sign error (%s)", err)
+		return operations.NewDeprecateComponentReleaseDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: "unable to sign release"})
+	}
+	return operations.NewDeprecateComponentReleaseOK().WithPayload(&result)
+}
+
+// GetSigningKey route handler. Returns a 404 if release signing isn't configured
+func GetSigningKey(signer *signing.Signer) middleware.Responder {
+	if signer == nil {
+		return operations.NewGetSigningKeyDefault(http.StatusNotFound).WithPayload(&models.Error{Code: http.StatusNotFound, Message: "404 release signing is not configured"})
+	}
+	key := signer.SigningKey()
+	return operations.NewGetSigningKeyOK().WithPayload(&key)
+}
+
 // PublishDoctor writes doctorInfo to database
-func PublishDoctor(params operations.PublishDoctorInfoParams, db *gorm.DB) middleware.Responder {
+func PublishDoctor(params operations.PublishDoctorInfoParams, store data.Store) middleware.Responder {
 	doctorInfo := *params.Body
 	uuid := params.UUID
-	_, err := data.UpsertDoctor(db, uuid, doctorInfo)
+	_, err := store.UpsertDoctor(uuid, doctorInfo)
 	if err != nil {
 		log.Printf("data.PublishDoctor error (%s)", err)
-		return operations.NewPublishDoctorInfoDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		code, payload := errorPayload(err, "doctor report")
+		return operations.NewPublishDoctorInfoDefault(code).WithPayload(payload)
 	}
 	return operations.NewPublishDoctorInfoOK()
 }
 
 // GetDoctor gets doctorInfo related to UUID
-func GetDoctor(params operations.GetDoctorInfoParams, db *gorm.DB) middleware.Responder {
-	result, err := data.GetDoctor(db, params.UUID)
+func GetDoctor(params operations.GetDoctorInfoParams, store data.Store) middleware.Responder {
+	result, err := store.GetDoctor(params.UUID)
 	if err != nil {
 		log.Printf("data.GetDoctor error (%s)", err)
-		return operations.NewGetDoctorInfoDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		code, payload := errorPayload(err, "doctor report")
+		return operations.NewGetDoctorInfoDefault(code).WithPayload(payload)
 	}
 	return operations.NewGetDoctorInfoOK().WithPayload(&result)
 }
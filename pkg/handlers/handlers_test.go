@@ -1,13 +1,27 @@
 package handlers
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/ratelimit"
+	"github.com/deis/workflow-manager-api/pkg/signing"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/strfmt"
+	"github.com/pborman/uuid"
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -59,3 +73,886 @@ func TestWritePlainText(t *testing.T) {
 	assert.True(t, w.Body != nil, "response body was nil")
 	assert.Equal(t, string(w.Body.Bytes()), plainText, "response body")
 }
+
+func TestClusterCheckinAndGetCluster(t *testing.T) {
+	store := data.NewMemStore()
+	const clusterID = "d6ef1c2f-5a3b-4f0e-9b3c-8a1e2c4d5f60"
+	resp := GetCluster(operations.GetClusterByIDParams{ID: clusterID}, store)
+	notFound, ok := resp.(*operations.GetClusterByIDDefault)
+	assert.True(t, ok, "response wasn't a GetClusterByIDDefault")
+	assert.Equal(t, notFound.Payload.Code, int64(http.StatusNotFound), "response code")
+
+	cluster := &models.Cluster{ID: clusterID, Components: []*models.ComponentVersion{}}
+	resp = ClusterCheckin(operations.CreateClusterDetailsParams{Body: cluster}, store, CheckinLimits{})
+	_, ok = resp.(*operations.CreateClusterDetailsOK)
+	assert.True(t, ok, "response wasn't a CreateClusterDetailsOK")
+
+	resp = GetCluster(operations.GetClusterByIDParams{ID: clusterID}, store)
+	found, ok := resp.(*operations.GetClusterByIDOK)
+	assert.True(t, ok, "response wasn't a GetClusterByIDOK")
+	assert.Equal(t, found.Payload.ID, clusterID, "cluster ID")
+
+	resp = ClustersCount(store)
+	count, ok := resp.(*operations.GetClustersCountOK)
+	assert.True(t, ok, "response wasn't a GetClustersCountOK")
+	assert.Equal(t, count.Payload, int64(1), "cluster count")
+}
+
+func TestClusterCheckinLimits(t *testing.T) {
+	store := data.NewMemStore()
+	limits := CheckinLimits{PerCluster: ratelimit.NewLimiter(1, 1), PerIP: ratelimit.NewLimiter(1, 2)}
+	checkin := func(clusterID, remoteAddr string) middleware.Responder {
+		req, err := http.NewRequest("POST", "/v3/clusters", nil)
+		assert.NoErr(t, err)
+		req.RemoteAddr = remoteAddr
+		params := operations.CreateClusterDetailsParams{HTTPRequest: req, Body: &models.Cluster{ID: clusterID, Components: []*models.ComponentVersion{}}}
+		return ClusterCheckin(params, store, limits)
+	}
+
+	cluster1, cluster2, cluster3 := uuid.New(), uuid.New(), uuid.New()
+	_, ok := checkin(cluster1, "10.0.0.1:1234").(*operations.CreateClusterDetailsOK)
+	assert.True(t, ok, "response wasn't a CreateClusterDetailsOK")
+	resp := checkin(cluster1, "10.0.0.2:1234")
+	tooMany, ok := resp.(*operations.CreateClusterDetailsTooManyRequests)
+	assert.True(t, ok, "response to a check-in over the cluster limit wasn't a CreateClusterDetailsTooManyRequests")
+	assert.Equal(t, tooMany.Payload.Code, int64(http.StatusTooManyRequests), "response code")
+	assert.True(t, tooMany.RetryAfter > 0, "retry after %d seconds", tooMany.RetryAfter)
+
+	// 10.0.0.2 has used one of its 2 check-ins on the rate limited check-in above
+	_, ok = checkin(cluster2, "10.0.0.2:1234").(*operations.CreateClusterDetailsOK)
+	assert.True(t, ok, "response wasn't a CreateClusterDetailsOK")
+	_, ok = checkin(cluster3, "10.0.0.2:5678").(*operations.CreateClusterDetailsTooManyRequests)
+	assert.True(t, ok, "response to a check-in over the IP limit wasn't a CreateClusterDetailsTooManyRequests")
+}
+
+func TestClusterCheckinMinInterval(t *testing.T) {
+	store := data.NewMemStore()
+	const clusterID = "d6ef1c2f-5a3b-4f0e-9b3c-8a1e2c4d5f60"
+	limits := CheckinLimits{MinInterval: time.Hour}
+	for i := 0; i < 3; i++ {
+		resp := ClusterCheckin(operations.CreateClusterDetailsParams{Body: &models.Cluster{ID: clusterID, Components: []*models.ComponentVersion{}}}, store, limits)
+		ok, isOK := resp.(*operations.CreateClusterDetailsOK)
+		assert.True(t, isOK, "response %d wasn't a CreateClusterDetailsOK", i)
+		assert.Equal(t, ok.Payload.ID, clusterID, fmt.Sprintf("response %d cluster ID", i))
+	}
+	// only the first check-in is recorded
+	params := operations.NewGetClusterCheckinHistoryParams()
+	params.ID = clusterID
+	history, ok := ClusterCheckinHistory(params, store).(*operations.GetClusterCheckinHistoryOK)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinHistoryOK")
+	assert.Equal(t, len(history.Payload.Data), 1, "number of checkins")
+}
+
+// upsertCountingStore counts cluster upserts, and fails reads of whole checkins, so tests can check
+// that the min check-in interval is enforced without reading checkin data
+type upsertCountingStore struct {
+	data.Store
+	upserts int
+}
+
+func (u *upsertCountingStore) UpsertCluster(id string, cluster models.Cluster) (models.Cluster, error) {
+	u.upserts++
+	return u.Store.UpsertCluster(id, cluster)
+}
+
+func (u *upsertCountingStore) GetClusterCheckinAt(clusterID string, t time.Time) (models.ClusterCheckinRecord, error) {
+	return models.ClusterCheckinRecord{}, errors.New("checkin data read")
+}
+
+func TestClusterCheckinMinIntervalNoRow(t *testing.T) {
+	store := &upsertCountingStore{Store: data.NewMemStore()}
+	const clusterID = "0b6f4a5e-3c2d-4e1f-8a9b-7c6d5e4f3a2b"
+	checkin := func() {
+		resp := ClusterCheckin(operations.CreateClusterDetailsParams{Body: &models.Cluster{ID: clusterID, Components: []*models.ComponentVersion{}}}, store, CheckinLimits{MinInterval: time.Hour})
+		_, ok := resp.(*operations.CreateClusterDetailsOK)
+		assert.True(t, ok, "response wasn't a CreateClusterDetailsOK")
+	}
+	checkin()
+	assert.Equal(t, store.upserts, 1, "number of upserts after the first check-in")
+	last, err := store.GetLastCheckinTime(clusterID)
+	assert.NoErr(t, err)
+
+	// a check-in inside the min interval doesn't add a clusters_checkins row
+	checkin()
+	assert.Equal(t, store.upserts, 1, "number of upserts after a check-in inside the min interval")
+	again, err := store.GetLastCheckinTime(clusterID)
+	assert.NoErr(t, err)
+	assert.True(t, again.Equal(last), "last checkin time changed from %s to %s", last, again)
+}
+
+func TestClusterCheckinValidation(t *testing.T) {
+	store := data.NewMemStore()
+	checkin := func(cluster *models.Cluster) []string {
+		resp := ClusterCheckin(operations.CreateClusterDetailsParams{Body: cluster}, store, CheckinLimits{})
+		invalid, ok := resp.(*operations.CreateClusterDetailsUnprocessableEntity)
+		assert.True(t, ok, "response to an invalid check-in wasn't a CreateClusterDetailsUnprocessableEntity")
+		assert.Equal(t, invalid.Payload.Code, int64(http.StatusUnprocessableEntity), "response code")
+		fields := []string{}
+		for _, field := range invalid.Payload.Fields {
+			assert.True(t, field.Message != "", "field %s has no message", field.Field)
+			fields = append(fields, field.Field)
+		}
+		return fields
+	}
+
+	fields := checkin(&models.Cluster{ID: "testcluster"})
+	assert.Equal(t, strings.Join(fields, " "), "components id", "invalid fields")
+
+	train := "stable"
+	fields = checkin(&models.Cluster{ID: uuid.New(), Components: []*models.ComponentVersion{
+		{Component: &models.Component{Name: "deis-builder"}, Version: &models.Version{Train: train, Version: "v2.0.0"}},
+		nil,
+		{Version: &models.Version{Train: train, Version: "v2.0.0"}},
+		{Component: &models.Component{Name: strings.Repeat("a", maxComponentNameLen+1)}},
+		{Component: &models.Component{Name: "deis-router"}, Version: &models.Version{Train: train, Version: strings.Repeat("1", maxVersionLen+1)}},
+		{Component: &models.Component{Name: ""}, UpdateAvailable: &train},
+	}})
+	assert.Equal(t, strings.Join(fields, " "), "components[1] components[2].component components[3].component.name components[4].version.version components[5].component.name", "invalid fields")
+
+	tooMany := make([]*models.ComponentVersion, maxCheckinComponents+1)
+	fields = checkin(&models.Cluster{ID: uuid.New(), Components: tooMany})
+	assert.Equal(t, strings.Join(fields, " "), "components", "invalid fields")
+
+	count, err := store.GetClusterCount()
+	assert.NoErr(t, err)
+	assert.Equal(t, count, 0, "number of clusters")
+}
+
+func TestCheckinSourceIP(t *testing.T) {
+	req, err := http.NewRequest("POST", "/v3/clusters", nil)
+	assert.NoErr(t, err)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "192.168.0.1")
+	assert.Equal(t, CheckinLimits{}.sourceIP(req), "10.0.0.1", "source IP")
+	assert.Equal(t, CheckinLimits{TrustForwardedFor: true}.sourceIP(req), "192.168.0.1", "forwarded source IP")
+	// the client sent a spoofed address, and the proxy appended the real one
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 192.168.0.1")
+	assert.Equal(t, CheckinLimits{TrustForwardedFor: true}.sourceIP(req), "192.168.0.1", "forwarded source IP")
+	req.Header.Add("X-Forwarded-For", "192.168.0.2")
+	assert.Equal(t, CheckinLimits{TrustForwardedFor: true}.sourceIP(req), "192.168.0.2", "forwarded source IP")
+	assert.Equal(t, CheckinLimits{}.sourceIP(nil), "", "source IP of a nil request")
+	assert.Equal(t, retryAfterSeconds(1500*time.Millisecond), int64(2), "retry after")
+	assert.Equal(t, retryAfterSeconds(0), int64(1), "retry after")
+}
+
+func TestClusterCheckinHistory(t *testing.T) {
+	store := data.NewMemStore()
+	const clusterID = "testcluster"
+	params := operations.NewGetClusterCheckinHistoryParams()
+	params.ID = clusterID
+	resp := ClusterCheckinHistory(params, store)
+	notFound, ok := resp.(*operations.GetClusterCheckinHistoryDefault)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinHistoryDefault")
+	assert.Equal(t, notFound.Payload.Code, int64(http.StatusNotFound), "response code")
+
+	for i := 0; i < 3; i++ {
+		_, err := store.UpsertCluster(clusterID, models.Cluster{ID: clusterID})
+		assert.NoErr(t, err)
+	}
+	resp = ClusterCheckinHistory(params, store)
+	history, ok := resp.(*operations.GetClusterCheckinHistoryOK)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinHistoryOK")
+	assert.Equal(t, len(history.Payload.Data), 3, "number of checkins")
+	assert.Equal(t, history.Payload.NextCursor, "", "next cursor")
+
+	badCursor := "not a cursor"
+	params.Cursor = &badCursor
+	resp = ClusterCheckinHistory(params, store)
+	badRequest, ok := resp.(*operations.GetClusterCheckinHistoryDefault)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinHistoryDefault")
+	assert.Equal(t, badRequest.Payload.Code, int64(http.StatusBadRequest), "response code")
+}
+
+func TestClustersAgePages(t *testing.T) {
+	store := data.NewMemStore()
+	for _, id := range []string{"cluster1", "cluster2"} {
+		_, err := store.UpsertCluster(id, models.Cluster{ID: id})
+		assert.NoErr(t, err)
+	}
+	now := time.Now()
+	before := strfmt.DateTime(now.Add(time.Hour))
+	after := strfmt.DateTime(now.Add(-time.Hour))
+	limit := int64(1)
+	params := operations.GetClustersByAgeParams{
+		CheckedInBefore: &before,
+		CheckedInAfter:  &after,
+		CreatedBefore:   &before,
+		CreatedAfter:    &after,
+		Limit:           &limit,
+	}
+	ids := []string{}
+	for i := 0; i < 2; i++ {
+		resp := ClustersAge(params, store)
+		clusters, ok := resp.(*operations.GetClustersByAgeOK)
+		assert.True(t, ok, "page %d: response wasn't a GetClustersByAgeOK", i)
+		assert.Equal(t, len(clusters.Payload.Data), 1, "number of clusters")
+		ids = append(ids, clusters.Payload.Data[0].ID)
+		assert.Equal(t, clusters.XNextCursor, clusters.Payload.NextCursor, "next cursor header")
+		params.Cursor = &clusters.Payload.NextCursor
+	}
+	assert.Equal(t, ids, []string{"cluster1", "cluster2"}, "cluster IDs")
+	assert.Equal(t, *params.Cursor, "", "next cursor on the last page")
+
+	badCursor := "not a cursor"
+	params.Cursor = &badCursor
+	resp := ClustersAge(params, store)
+	badRequest, ok := resp.(*operations.GetClustersByAgeDefault)
+	assert.True(t, ok, "response wasn't a GetClustersByAgeDefault")
+	assert.Equal(t, badRequest.Payload.Code, int64(http.StatusBadRequest), "response code")
+}
+
+func TestClustersAgeExport(t *testing.T) {
+	store := data.NewMemStore()
+	for _, id := range []string{"cluster1", "cluster2", "cluster3"} {
+		_, err := store.UpsertCluster(id, models.Cluster{ID: id})
+		assert.NoErr(t, err)
+	}
+	now := time.Now()
+	before := strfmt.DateTime(now.Add(time.Hour))
+	after := strfmt.DateTime(now.Add(-time.Hour))
+	limit := int64(1)
+	req, err := http.NewRequest("GET", "/v3/clusters/age", nil)
+	assert.NoErr(t, err)
+	req.Header.Set(httpkit.HeaderAccept, CSVContentType)
+	params := operations.GetClustersByAgeParams{
+		HTTPRequest:     req,
+		CheckedInBefore: &before,
+		CheckedInAfter:  &after,
+		CreatedBefore:   &before,
+		CreatedAfter:    &after,
+		Limit:           &limit,
+	}
+
+	// exports aren't limited to a page
+	w := httptest.NewRecorder()
+	ClustersAge(params, store).WriteResponse(w, CSVProducer())
+	assert.Equal(t, w.Code, http.StatusOK, "response code")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, len(lines), 4, "number of lines")
+	assert.Equal(t, lines[0], strings.Join(clusterCSVHeader, ","), "header row")
+	assert.True(t, w.Flushed, "the export wasn't flushed")
+
+	// go-swagger can still pick JSON if the client accepts it too
+	w = httptest.NewRecorder()
+	ClustersAge(params, store).WriteResponse(w, httpkit.JSONProducer())
+	var page operations.GetClustersByAgeOKBodyBody
+	assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, len(page.Data), 1, "number of clusters in a JSON page")
+
+	// errors are JSON, and say so in their Content-Type
+	checkError := func(resp middleware.Responder, code int) {
+		w := httptest.NewRecorder()
+		resp.WriteResponse(w, CSVProducer())
+		assert.Equal(t, w.Code, code, "response code")
+		assert.Equal(t, w.HeaderMap.Get(ContentTypeHeaderKey), JSONContentType, "content type header")
+		var payload models.Error
+		assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &payload))
+		assert.Equal(t, payload.Code, int64(code), "payload code")
+	}
+	badCursor := "not a cursor"
+	badParams := params
+	badParams.Cursor = &badCursor
+	checkError(ClustersAge(badParams, store), http.StatusBadRequest)
+	checkError(ClustersAge(params, failingStore{Store: store, err: driver.ErrBadConn}), http.StatusServiceUnavailable)
+}
+
+func TestPersistentClusters(t *testing.T) {
+	store := data.NewMemStore()
+	now := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for _, checkin := range []struct {
+		id string
+		t  time.Time
+	}{
+		{"persistent", now.Add(-72 * time.Hour)},
+		{"persistent", now.Add(-2 * time.Hour)},
+		{"once", now.Add(-48 * time.Hour)},
+	} {
+		assert.NoErr(t, store.CheckInCluster(checkin.id, checkin.t, models.Cluster{ID: checkin.id}))
+	}
+	epoch := strfmt.DateTime(now.Add(-100 * time.Hour))
+	timestamp := strfmt.DateTime(now)
+	params := operations.GetPersistentClustersParams{Epoch: &epoch, Timestamp: &timestamp}
+	resp := PersistentClusters(params, store)
+	persistent, ok := resp.(*operations.GetPersistentClustersOK)
+	assert.True(t, ok, "response wasn't a GetPersistentClustersOK")
+	assert.Equal(t, *persistent.Payload.Count, int64(1), "number of clusters")
+	assert.Equal(t, persistent.Payload.Data[0].ClusterID, "persistent", "cluster ID")
+}
+
+func TestCSVProducer(t *testing.T) {
+	firstSeen := strfmt.DateTime(time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC))
+	clusters := data.NewSliceClusterRows([]interface{}{
+		&models.Cluster{
+			ID:        "cluster1",
+			FirstSeen: &firstSeen,
+			Components: []*models.ComponentVersion{
+				{Component: &models.Component{Name: "deis-builder"}, Version: &models.Version{Version: "2.0.0"}},
+				{Component: &models.Component{Name: "deis-router"}, Version: &models.Version{Version: "2.1.0"}},
+			},
+		},
+		&models.Cluster{ID: "cluster2, with a comma"},
+	})
+	w := httptest.NewRecorder()
+	assert.NoErr(t, CSVProducer().Produce(w, exportRows{rows: clusters, csvHeader: clusterCSVHeader}))
+	expected := "id,firstSeen,lastSeen,components\n" +
+		"cluster1," + firstSeen.String() + ",,deis-builder@2.0.0;deis-router@2.1.0\n" +
+		"\"cluster2, with a comma\",,,\n"
+	assert.Equal(t, w.Body.String(), expected, "CSV body")
+
+	checkins := data.NewSliceClusterRows([]interface{}{
+		&models.ClusterCheckin{ClusterID: "cluster1", FirstSeen: "2016-06-01T00:00:00Z", LastSeen: "2016-06-02T00:00:00Z", ClusterAge: "1 day", Checkins: 3},
+	})
+	w = httptest.NewRecorder()
+	assert.NoErr(t, CSVProducer().Produce(w, exportRows{rows: checkins, csvHeader: checkinCSVHeader}))
+	expected = "clusterID,firstSeen,lastSeen,lastCheckin,clusterAge,checkins\n" +
+		"cluster1,2016-06-01T00:00:00Z,2016-06-02T00:00:00Z,,1 day,3\n"
+	assert.Equal(t, w.Body.String(), expected, "CSV body")
+
+	// other payloads are written as JSON
+	for _, producer := range []httpkit.Producer{CSVProducer(), NDJSONProducer()} {
+		w = httptest.NewRecorder()
+		assert.NoErr(t, producer.Produce(w, &models.Error{Code: http.StatusBadRequest, Message: "bad cursor"}))
+		var payload models.Error
+		assert.NoErr(t, json.Unmarshal(w.Body.Bytes(), &payload))
+		assert.Equal(t, payload.Message, "bad cursor", "error message")
+	}
+}
+
+func TestNDJSONProducer(t *testing.T) {
+	checkins := []*models.ClusterCheckin{
+		{ClusterID: "cluster1", Checkins: 3},
+		{ClusterID: "cluster2", Checkins: 1},
+	}
+	rows := data.NewSliceClusterRows([]interface{}{checkins[0], checkins[1]})
+	w := httptest.NewRecorder()
+	assert.NoErr(t, NDJSONProducer().Produce(w, exportRows{rows: rows, csvHeader: checkinCSVHeader}))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, len(lines), 2, "number of lines")
+	for i, line := range lines {
+		var checkin models.ClusterCheckin
+		assert.NoErr(t, json.Unmarshal([]byte(line), &checkin))
+		assert.Equal(t, checkin, *checkins[i], fmt.Sprintf("line %d", i))
+	}
+}
+
+func TestClusterCheckinDiff(t *testing.T) {
+	store := data.NewMemStore()
+	const clusterID = "testcluster"
+	_, err := store.UpsertCluster(clusterID, models.Cluster{ID: clusterID})
+	assert.NoErr(t, err)
+	start := time.Date(2016, time.June, 1, 12, 0, 0, 0, time.UTC)
+	for i, vsn := range []string{"2.0.0", "2.1.0"} {
+		cluster := models.Cluster{
+			ID: clusterID,
+			Components: []*models.ComponentVersion{{
+				Component: &models.Component{Name: "deis-router"},
+				Version:   &models.Version{Train: "stable", Version: vsn},
+			}},
+		}
+		assert.NoErr(t, store.CheckInCluster(clusterID, start.Add(time.Duration(i)*time.Hour), cluster))
+	}
+
+	from := strfmt.DateTime(start)
+	to := strfmt.DateTime(start.Add(time.Hour))
+	resp := ClusterCheckinDiff(operations.GetClusterCheckinDiffParams{ID: clusterID, From: &from, To: &to}, store)
+	diff, ok := resp.(*operations.GetClusterCheckinDiffOK)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinDiffOK")
+	assert.Equal(t, len(diff.Payload.Upgraded), 1, "number of upgraded components")
+	assert.Equal(t, diff.Payload.Upgraded[0].Name, "deis-router", "upgraded component")
+
+	toCheckin := diff.Payload.To.ID
+	resp = ClusterCheckinDiff(operations.GetClusterCheckinDiffParams{ID: clusterID, From: &to, ToCheckin: &toCheckin}, store)
+	diff, ok = resp.(*operations.GetClusterCheckinDiffOK)
+	assert.True(t, ok, "response wasn't a GetClusterCheckinDiffOK")
+	assert.Equal(t, len(diff.Payload.Upgraded), 0, "number of upgraded components")
+
+	type testCase struct {
+		params operations.GetClusterCheckinDiffParams
+		code   int64
+	}
+	before := strfmt.DateTime(start.Add(-time.Hour))
+	noSuchCheckin := "12345"
+	testCases := []testCase{
+		{params: operations.GetClusterCheckinDiffParams{ID: "nosuchcluster", From: &from, To: &to}, code: http.StatusNotFound},
+		{params: operations.GetClusterCheckinDiffParams{ID: clusterID, To: &to}, code: http.StatusBadRequest},
+		{params: operations.GetClusterCheckinDiffParams{ID: clusterID, From: &from, FromCheckin: &toCheckin, To: &to}, code: http.StatusBadRequest},
+		{params: operations.GetClusterCheckinDiffParams{ID: clusterID, From: &before, To: &to}, code: http.StatusNotFound},
+		{params: operations.GetClusterCheckinDiffParams{ID: clusterID, From: &from, ToCheckin: &noSuchCheckin}, code: http.StatusNotFound},
+	}
+	for i, tc := range testCases {
+		resp := ClusterCheckinDiff(tc.params, store)
+		errResp, ok := resp.(*operations.GetClusterCheckinDiffDefault)
+		assert.True(t, ok, "test case %d: response wasn't a GetClusterCheckinDiffDefault", i)
+		assert.Equal(t, errResp.Payload.Code, tc.code, fmt.Sprintf("test case %d response code", i))
+	}
+}
+
+func TestActiveClusters(t *testing.T) {
+	store := data.NewMemStore()
+	since := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"cluster1", "cluster2"} {
+		assert.NoErr(t, store.CheckInCluster(id, since.Add(time.Duration(i)*time.Hour), models.Cluster{ID: id}))
+	}
+	until := strfmt.DateTime(since.AddDate(0, 0, 14))
+	interval := "week"
+	params := operations.GetActiveClustersParams{Since: strfmt.DateTime(since), Until: &until, Interval: &interval}
+	resp := ActiveClusters(params, store)
+	active, ok := resp.(*operations.GetActiveClustersOK)
+	assert.True(t, ok, "response wasn't a GetActiveClustersOK")
+	// June 1st 2016 was a Wednesday, so the range overlaps 3 weeks
+	assert.Equal(t, len(active.Payload.Data), 3, "number of buckets")
+	assert.Equal(t, active.Payload.Data[0].Start, "2016-05-30", "bucket start")
+	assert.Equal(t, *active.Payload.Data[0].Clusters, int64(2), "number of clusters")
+
+	params.Until = &params.Since
+	resp = ActiveClusters(params, store)
+	badRequest, ok := resp.(*operations.GetActiveClustersDefault)
+	assert.True(t, ok, "response wasn't a GetActiveClustersDefault")
+	assert.Equal(t, badRequest.Payload.Code, int64(http.StatusBadRequest), "response code")
+
+	// more than data.MaxStatsBuckets days
+	interval = "day"
+	params.Since = strfmt.DateTime(time.Time{})
+	params.Until = &until
+	resp = ActiveClusters(params, store)
+	tooLong, ok := resp.(*operations.GetActiveClustersDefault)
+	assert.True(t, ok, "response wasn't a GetActiveClustersDefault")
+	assert.Equal(t, tooLong.Payload.Code, int64(http.StatusBadRequest), "response code")
+	assert.Equal(t, tooLong.Payload.Reason, string(data.ReasonInvalidFilter), "reason")
+}
+
+func TestClusterChurn(t *testing.T) {
+	store := data.NewMemStore()
+	since := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoErr(t, store.CheckInCluster("cluster1", since, models.Cluster{ID: "cluster1"}))
+	assert.NoErr(t, store.CheckInCluster("cluster2", since.AddDate(0, 1, 0), models.Cluster{ID: "cluster2"}))
+	// cluster1 goes dormant on July 1st
+	_, err := store.DetectDormantClusters(since.AddDate(0, 1, 15), data.DormancyWindow(30))
+	assert.NoErr(t, err)
+
+	until := strfmt.DateTime(since.AddDate(0, 2, 0))
+	params := operations.GetClusterChurnParams{Since: strfmt.DateTime(since), Until: &until}
+	resp := ClusterChurn(params, store)
+	churn, ok := resp.(*operations.GetClusterChurnOK)
+	assert.True(t, ok, "response wasn't a GetClusterChurnOK")
+	assert.Equal(t, churn.Payload.Interval, "month", "interval")
+	assert.Equal(t, len(churn.Payload.Data), 2, "number of periods")
+	july := churn.Payload.Data[1]
+	assert.Equal(t, july.Start, "2016-07-01", "period start")
+	assert.Equal(t, *july.New, int64(1), "number of new clusters")
+	assert.Equal(t, *july.Lost, int64(1), "number of lost clusters")
+	assert.Equal(t, *july.Retained, int64(0), "number of retained clusters")
+
+	params.Until = &params.Since
+	resp = ClusterChurn(params, store)
+	badRequest, ok := resp.(*operations.GetClusterChurnDefault)
+	assert.True(t, ok, "response wasn't a GetClusterChurnDefault")
+	assert.Equal(t, badRequest.Payload.Code, int64(http.StatusBadRequest), "response code")
+
+	// more than data.MaxStatsBuckets months
+	params.Since = strfmt.DateTime(time.Time{})
+	params.Until = &until
+	resp = ClusterChurn(params, store)
+	tooLong, ok := resp.(*operations.GetClusterChurnDefault)
+	assert.True(t, ok, "response wasn't a GetClusterChurnDefault")
+	assert.Equal(t, tooLong.Payload.Code, int64(http.StatusBadRequest), "response code")
+	assert.Equal(t, tooLong.Payload.Reason, string(data.ReasonInvalidFilter), "reason")
+}
+
+func TestClusterCohorts(t *testing.T) {
+	store := data.NewMemStore()
+	since := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []string{"cluster1", "cluster2"} {
+		assert.NoErr(t, store.CheckInCluster(id, since, models.Cluster{ID: id}))
+	}
+	assert.NoErr(t, store.CheckInCluster("cluster1", since.AddDate(0, 1, 0), models.Cluster{ID: "cluster1"}))
+
+	until := strfmt.DateTime(since.AddDate(0, 2, 0))
+	params := operations.GetClusterCohortsParams{Since: strfmt.DateTime(since), Until: &until}
+	resp := ClusterCohorts(params, store)
+	cohorts, ok := resp.(*operations.GetClusterCohortsOK)
+	assert.True(t, ok, "response wasn't a GetClusterCohortsOK")
+	assert.Equal(t, cohorts.Payload.Interval, "month", "interval")
+	assert.Equal(t, len(cohorts.Payload.Data), 2, "number of cohorts")
+	june := cohorts.Payload.Data[0]
+	assert.Equal(t, *june.Clusters, int64(2), "number of clusters")
+	assert.Equal(t, june.Retention, []float64{1, 0.5}, "retention")
+
+	interval := "day"
+	params.Interval = &interval
+	resp = ClusterCohorts(params, store)
+	badRequest, ok := resp.(*operations.GetClusterCohortsDefault)
+	assert.True(t, ok, "response wasn't a GetClusterCohortsDefault")
+	assert.Equal(t, badRequest.Payload.Code, int64(http.StatusBadRequest), "response code")
+
+	// more than data.MaxCohortPeriods months
+	interval = "month"
+	params.Since = strfmt.DateTime(since.AddDate(-10, 0, 0))
+	resp = ClusterCohorts(params, store)
+	tooLong, ok := resp.(*operations.GetClusterCohortsDefault)
+	assert.True(t, ok, "response wasn't a GetClusterCohortsDefault")
+	assert.Equal(t, tooLong.Payload.Code, int64(http.StatusBadRequest), "response code")
+	assert.Equal(t, tooLong.Payload.Reason, string(data.ReasonInvalidFilter), "reason")
+}
+
+func TestComponentAdoption(t *testing.T) {
+	store := data.NewMemStore()
+	cv := models.ComponentVersion{
+		Component: &models.Component{Name: "deis-builder"},
+		Version:   &models.Version{Train: "stable", Version: "2.0.0", Released: "2016-06-01T00:00:00Z"},
+	}
+	_, err := store.UpsertVersion(cv)
+	assert.NoErr(t, err)
+	cluster := models.Cluster{ID: "testcluster", Components: []*models.ComponentVersion{&cv}}
+	_, err = store.UpsertCluster(cluster.ID, cluster)
+	assert.NoErr(t, err)
+
+	resp := ComponentAdoption(operations.GetComponentAdoptionParams{Train: "stable", Component: "deis-builder"}, store)
+	adoption, ok := resp.(*operations.GetComponentAdoptionOK)
+	assert.True(t, ok, "response wasn't a GetComponentAdoptionOK")
+	assert.Equal(t, *adoption.Payload.Clusters, int64(1), "number of clusters")
+	assert.Equal(t, len(adoption.Payload.Versions), 1, "number of versions")
+	assert.Equal(t, adoption.Payload.Versions[0].Version, "2.0.0", "version")
+	assert.Equal(t, *adoption.Payload.Versions[0].Clusters, int64(1), "number of clusters")
+}
+
+func TestReleaseAdoption(t *testing.T) {
+	store := data.NewMemStore()
+	released := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	cv := models.ComponentVersion{
+		Component: &models.Component{Name: "deis-builder"},
+		Version:   &models.Version{Train: "stable", Version: "2.0.0", Released: released.Format(data.StdTimestampFmt)},
+	}
+	_, err := store.UpsertVersion(cv)
+	assert.NoErr(t, err)
+	cluster := models.Cluster{ID: "testcluster", Components: []*models.ComponentVersion{&cv}}
+	assert.NoErr(t, store.CheckInCluster(cluster.ID, released.Add(time.Hour), cluster))
+
+	until := strfmt.DateTime(released.Add(48 * time.Hour))
+	params := operations.GetReleaseAdoptionParams{Train: "stable", Component: "deis-builder", Release: "2.0.0", Until: &until}
+	resp := ReleaseAdoption(params, store)
+	adoption, ok := resp.(*operations.GetReleaseAdoptionOK)
+	assert.True(t, ok, "response wasn't a GetReleaseAdoptionOK")
+	assert.Equal(t, len(adoption.Payload.Data), 2, "number of days")
+	assert.Equal(t, *adoption.Payload.Data[0].Clusters, int64(1), "number of clusters")
+	assert.Equal(t, *adoption.Payload.Data[1].Clusters, int64(0), "number of clusters")
+
+	// a series of more than data.MaxStatsBuckets days is rejected
+	farFuture := strfmt.DateTime(time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC))
+	params.Until = &farFuture
+	resp = ReleaseAdoption(params, store)
+	tooLong, ok := resp.(*operations.GetReleaseAdoptionDefault)
+	assert.True(t, ok, "response wasn't a GetReleaseAdoptionDefault")
+	assert.Equal(t, tooLong.Payload.Code, int64(http.StatusBadRequest), "response code")
+	assert.Equal(t, tooLong.Payload.Reason, string(data.ReasonInvalidFilter), "reason")
+	lastDay := strfmt.DateTime(released.AddDate(0, 0, data.MaxStatsBuckets))
+	params.Until = &lastDay
+	_, ok = ReleaseAdoption(params, store).(*operations.GetReleaseAdoptionOK)
+	assert.True(t, ok, "response to a series of data.MaxStatsBuckets days wasn't a GetReleaseAdoptionOK")
+
+	params.Release = "3.0.0"
+	resp = ReleaseAdoption(params, store)
+	notFound, ok := resp.(*operations.GetReleaseAdoptionDefault)
+	assert.True(t, ok, "response wasn't a GetReleaseAdoptionDefault")
+	assert.Equal(t, notFound.Payload.Code, int64(http.StatusNotFound), "response code")
+}
+
+func TestPublishAndGetVersion(t *testing.T) {
+	store := data.NewMemStore()
+	const (
+		train     = "stable"
+		component = "testcomponent"
+	)
+	token := data.PublisherToken{Name: "ci", Components: []string{component}}
+	for _, release := range []string{"1.0.0", "1.1.0"} {
+		body := &models.ComponentVersion{
+			Component: &models.Component{},
+			Version:   &models.Version{Released: "2016-06-01T00:00:00Z"},
+		}
+		params := operations.PublishComponentReleaseParams{Body: body, Train: train, Component: component, Release: release}
+		resp := PublishVersion(params, token, store, nil)
+		_, ok := resp.(*operations.PublishComponentReleaseOK)
+		assert.True(t, ok, "response wasn't a PublishComponentReleaseOK")
+	}
+
+	resp := GetVersion(operations.GetComponentByReleaseParams{Train: train, Component: component, Release: "latest"}, store, nil)
+	latest, ok := resp.(*operations.GetComponentByReleaseOK)
+	assert.True(t, ok, "response wasn't a GetComponentByReleaseOK")
+	assert.Equal(t, latest.Payload.Version.Version, "1.1.0", "latest version")
+
+	resp = GetVersion(operations.GetComponentByReleaseParams{Train: train, Component: component, Release: "2.0.0"}, store, nil)
+	notFound, ok := resp.(*operations.GetComponentByReleaseDefault)
+	assert.True(t, ok, "response wasn't a GetComponentByReleaseDefault")
+	assert.Equal(t, notFound.Payload.Code, int64(http.StatusNotFound), "response code")
+}
+
+func TestSignedVersions(t *testing.T) {
+	store := data.NewMemStore()
+	key := ed25519.NewKeyFromSeed([]byte(strings.Repeat("a", ed25519.SeedSize)))
+	signer := signing.NewSigner(key)
+	const (
+		train     = "stable"
+		component = "testcomponent"
+	)
+	body := &models.ComponentVersion{
+		Component: &models.Component{},
+		Version:   &models.Version{Released: "2016-06-01T00:00:00Z"},
+	}
+	params := operations.PublishComponentReleaseParams{Body: body, Train: train, Component: component, Release: "1.0.0"}
+	resp := PublishVersion(params, data.PublisherToken{Name: "ci"}, store, signer)
+	published, ok := resp.(*operations.PublishComponentReleaseOK)
+	assert.True(t, ok, "response wasn't a PublishComponentReleaseOK")
+	assert.True(t, signing.Verify(signer.PublicKey(), *published.Payload), "published release wasn't signed")
+
+	resp = GetVersion(operations.GetComponentByReleaseParams{Train: train, Component: component, Release: "1.0.0"}, store, signer)
+	got, ok := resp.(*operations.GetComponentByReleaseOK)
+	assert.True(t, ok, "response wasn't a GetComponentByReleaseOK")
+	assert.True(t, signing.Verify(signer.PublicKey(), *got.Payload), "release wasn't signed")
+
+	resp = GetComponentTrainVersions(operations.GetComponentByNameParams{Train: train, Component: component}, store, signer)
+	list, ok := resp.(*operations.GetComponentByNameOK)
+	assert.True(t, ok, "response wasn't a GetComponentByNameOK")
+	assert.Equal(t, len(list.Payload.Data), 1, "number of releases")
+	assert.True(t, signing.Verify(signer.PublicKey(), *list.Payload.Data[0]), "listed release wasn't signed")
+
+	resp = GetSigningKey(signer)
+	signingKey, ok := resp.(*operations.GetSigningKeyOK)
+	assert.True(t, ok, "response wasn't a GetSigningKeyOK")
+	assert.Equal(t, *signingKey.Payload, signer.SigningKey(), "signing key")
+
+	resp = GetSigningKey(nil)
+	notFound, ok := resp.(*operations.GetSigningKeyDefault)
+	assert.True(t, ok, "response wasn't a GetSigningKeyDefault")
+	assert.Equal(t, notFound.Payload.Code, int64(http.StatusNotFound), "response code")
+}
+
+func TestPublishVersionForbidden(t *testing.T) {
+	store := data.NewMemStore()
+	body := &models.ComponentVersion{
+		Component: &models.Component{},
+		Version:   &models.Version{Released: "2016-06-01T00:00:00Z"},
+	}
+	params := operations.PublishComponentReleaseParams{Body: body, Train: "stable", Component: "testcomponent", Release: "1.0.0"}
+	for _, principal := range []interface{}{
+		nil,
+		data.PublisherToken{Name: "ci", Components: []string{"othercomponent"}},
+		data.PublisherToken{Name: "ci", Trains: []string{"beta"}},
+	} {
+		resp := PublishVersion(params, principal, store, nil)
+		forbidden, ok := resp.(*operations.PublishComponentReleaseDefault)
+		assert.True(t, ok, "response wasn't a PublishComponentReleaseDefault")
+		assert.Equal(t, forbidden.Payload.Code, int64(http.StatusForbidden), "response code")
+	}
+	versions, err := store.GetVersionsList("stable", "testcomponent")
+	assert.NoErr(t, err)
+	assert.Equal(t, len(versions), 0, "number of published versions")
+}
+
+func TestDeprecateVersion(t *testing.T) {
+	store := data.NewMemStore()
+	const (
+		train     = "stable"
+		component = "testcomponent"
+	)
+	token := data.PublisherToken{Name: "ci", Components: []string{component}}
+	for _, release := range []string{"1.0.0", "1.1.0"} {
+		body := &models.ComponentVersion{
+			Component: &models.Component{},
+			Version:   &models.Version{Released: "2016-06-01T00:00:00Z"},
+		}
+		params := operations.PublishComponentReleaseParams{Body: body, Train: train, Component: component, Release: release}
+		_, ok := PublishVersion(params, token, store, nil).(*operations.PublishComponentReleaseOK)
+		assert.True(t, ok, "response wasn't a PublishComponentReleaseOK")
+	}
+	deprecate := func(principal interface{}, release string, body *models.Deprecation) middleware.Responder {
+		params := operations.DeprecateComponentReleaseParams{Body: body, Train: train, Component: component, Release: release}
+		return DeprecateVersion(params, principal, store, nil)
+	}
+	latest := func() string {
+		resp := GetVersion(operations.GetComponentByReleaseParams{Train: train, Component: component, Release: "latest"}, store, nil)
+		got, ok := resp.(*operations.GetComponentByReleaseOK)
+		assert.True(t, ok, "response wasn't a GetComponentByReleaseOK")
+		return got.Payload.Version.Version
+	}
+	yank := &models.Deprecation{Status: data.ReleaseYanked, Reason: "deletes data"}
+
+	errCases := []struct {
+		principal interface{}
+		release   string
+		body      *models.Deprecation
+		code      int
+	}{
+		{principal: nil, release: "1.1.0", body: yank, code: http.StatusForbidden},
+		{principal: data.PublisherToken{Name: "ci", Trains: []string{"beta"}}, release: "1.1.0", body: yank, code: http.StatusForbidden},
+		{principal: token, release: "2.0.0", body: yank, code: http.StatusNotFound},
+	}
+	for i, c := range errCases {
+		resp, ok := deprecate(c.principal, c.release, c.body).(*operations.DeprecateComponentReleaseDefault)
+		assert.True(t, ok, "case %d: response wasn't a DeprecateComponentReleaseDefault", i)
+		assert.Equal(t, resp.Payload.Code, int64(c.code), fmt.Sprintf("case %d response code", i))
+		if c.code == http.StatusForbidden {
+			assert.Equal(t, resp.Payload.Message, "403 publisher token not allowed to deprecate releases of this component or train", fmt.Sprintf("case %d message", i))
+		}
+	}
+	invalidBodies := []*models.Deprecation{
+		nil,
+		&models.Deprecation{Status: "broken", Reason: "deletes data"},
+		&models.Deprecation{Status: data.ReleaseYanked},
+	}
+	for i, body := range invalidBodies {
+		resp, ok := deprecate(token, "1.1.0", body).(*operations.DeprecateComponentReleaseUnprocessableEntity)
+		assert.True(t, ok, "invalid body %d: response wasn't a DeprecateComponentReleaseUnprocessableEntity", i)
+		assert.Equal(t, resp.Payload.Code, int64(http.StatusUnprocessableEntity), fmt.Sprintf("invalid body %d response code", i))
+		assert.True(t, len(resp.Payload.Fields) > 0, "invalid body %d: no invalid fields", i)
+	}
+	assert.Equal(t, latest(), "1.1.0", "latest version after failed requests")
+
+	resp, ok := deprecate(token, "1.1.0", yank).(*operations.DeprecateComponentReleaseOK)
+	assert.True(t, ok, "response wasn't a DeprecateComponentReleaseOK")
+	assert.Equal(t, resp.Payload.Deprecation.Status, data.ReleaseYanked, "status")
+	assert.Equal(t, resp.Payload.Deprecation.Reason, "deletes data", "reason")
+	assert.Equal(t, latest(), "1.0.0", "latest version after yanking")
+
+	resp, ok = deprecate(token, "1.1.0", &models.Deprecation{Status: data.ReleaseActive}).(*operations.DeprecateComponentReleaseOK)
+	assert.True(t, ok, "response wasn't a DeprecateComponentReleaseOK")
+	assert.True(t, resp.Payload.Deprecation == nil, "expected the deprecation to be cleared")
+	assert.Equal(t, latest(), "1.1.0", "latest version after reactivating")
+}
+
+func TestLiveness(t *testing.T) {
+	resp, ok := Liveness().(*operations.CheckLivenessOK)
+	assert.True(t, ok, "response wasn't a CheckLivenessOK")
+	assert.Equal(t, resp.Payload.Status, "ok", "status")
+}
+
+// healthStore is a data.Store whose health checks return checks
+type healthStore struct {
+	data.Store
+	checks []data.HealthCheck
+}
+
+func (h healthStore) CheckHealth(timeout time.Duration) []data.HealthCheck {
+	return h.checks
+}
+
+func TestReadiness(t *testing.T) {
+	resp := Readiness(data.NewMemStore(), time.Second)
+	ready, ok := resp.(*operations.CheckReadinessOK)
+	assert.True(t, ok, "response wasn't a CheckReadinessOK")
+	assert.Equal(t, ready.Payload.Status, "ok", "status")
+	assert.Equal(t, len(ready.Payload.Checks), 3, "number of checks")
+
+	dbErr := errors.New("connection refused")
+	store := healthStore{checks: []data.HealthCheck{
+		{Name: data.HealthCheckDatabase, Err: dbErr},
+		{Name: data.HealthCheckSchema, Err: dbErr},
+		{Name: data.HealthCheckMigrations},
+	}}
+	resp = Readiness(store, time.Second)
+	unavailable, ok := resp.(*operations.CheckReadinessServiceUnavailable)
+	assert.True(t, ok, "response wasn't a CheckReadinessServiceUnavailable")
+	assert.Equal(t, unavailable.Payload.Status, "unavailable", "status")
+	assert.Equal(t, len(unavailable.Payload.Checks), 3, "number of checks")
+	for i, check := range unavailable.Payload.Checks[:2] {
+		assert.Equal(t, check.Status, "failed", fmt.Sprintf("check %d status", i))
+		assert.Equal(t, check.Error, dbErr.Error(), fmt.Sprintf("check %d error", i))
+	}
+	assert.Equal(t, unavailable.Payload.Checks[2].Status, "ok", "passing check status")
+}
+
+func TestErrorPayload(t *testing.T) {
+	type testCase struct {
+		err     error
+		code    int
+		reason  data.Reason
+		message string
+	}
+	_, invalidInterval := data.ParseBucketInterval("fortnight")
+	testCases := []testCase{
+		{err: data.ErrNotFound, code: http.StatusNotFound, reason: data.ReasonNotFound, message: "404 cluster not found"},
+		{err: data.ErrPublisherTokenExists, code: http.StatusConflict, reason: data.ReasonConflict, message: data.ErrPublisherTokenExists.Error()},
+		{err: invalidInterval, code: http.StatusBadRequest, reason: data.ReasonInvalidFilter, message: invalidInterval.Error()},
+		{err: data.RequestError{RequestID: "testrequest", Err: data.ErrInvalidCursor}, code: http.StatusBadRequest, reason: data.ReasonInvalidFilter, message: data.ErrInvalidCursor.Error()},
+		{err: errInvalidTimeFmt{key: "since", err: fmt.Errorf("bad time")}, code: http.StatusBadRequest, reason: data.ReasonInvalidFilter},
+		{err: driver.ErrBadConn, code: http.StatusServiceUnavailable, reason: data.ReasonUnavailable},
+		{err: fmt.Errorf(`pq: invalid input syntax for uuid: "testcluster"`), code: http.StatusInternalServerError, reason: data.ReasonInternal, message: "500 internal server error"},
+	}
+	for i, tc := range testCases {
+		code, payload := errorPayload(tc.err, "cluster")
+		assert.Equal(t, code, tc.code, fmt.Sprintf("test case %d status code", i))
+		assert.Equal(t, payload.Code, int64(tc.code), fmt.Sprintf("test case %d payload code", i))
+		assert.Equal(t, payload.Reason, string(tc.reason), fmt.Sprintf("test case %d reason", i))
+		if tc.message != "" {
+			assert.Equal(t, payload.Message, tc.message, fmt.Sprintf("test case %d message", i))
+		}
+		assert.False(t, strings.Contains(payload.Message, "pq:"), "test case %d leaked a database error (%s)", i, payload.Message)
+	}
+}
+
+// failingStore is a data.Store whose single record reads all fail with err
+type failingStore struct {
+	data.Store
+	err error
+}
+
+func (f failingStore) GetCluster(id string) (models.Cluster, error) {
+	return models.Cluster{}, f.err
+}
+
+func (f failingStore) GetVersion(cv models.ComponentVersion) (models.ComponentVersion, error) {
+	return models.ComponentVersion{}, f.err
+}
+
+func (f failingStore) GetVersionsList(train, component string) ([]*models.ComponentVersion, error) {
+	return nil, f.err
+}
+
+func (f failingStore) GetDoctor(id string) (models.DoctorInfo, error) {
+	return models.DoctorInfo{}, f.err
+}
+
+func (f failingStore) ExportClustersByAge(filter *data.ClusterAgeFilter, page *data.ClusterPage) (data.ClusterRows, error) {
+	return nil, f.err
+}
+
+func TestReadHandlersNotFound(t *testing.T) {
+	type getter func(store data.Store) *models.Error
+	getters := map[string]getter{
+		"GetCluster": func(store data.Store) *models.Error {
+			resp, ok := GetCluster(operations.GetClusterByIDParams{ID: "nosuchcluster"}, store).(*operations.GetClusterByIDDefault)
+			assert.True(t, ok, "GetCluster response wasn't a GetClusterByIDDefault")
+			return resp.Payload
+		},
+		"GetVersion": func(store data.Store) *models.Error {
+			params := operations.GetComponentByReleaseParams{Train: "stable", Component: "nosuchcomponent", Release: "1.0.0"}
+			resp, ok := GetVersion(params, store, nil).(*operations.GetComponentByReleaseDefault)
+			assert.True(t, ok, "GetVersion response wasn't a GetComponentByReleaseDefault")
+			return resp.Payload
+		},
+		"GetDoctor": func(store data.Store) *models.Error {
+			resp, ok := GetDoctor(operations.GetDoctorInfoParams{UUID: "nosuchreport"}, store).(*operations.GetDoctorInfoDefault)
+			assert.True(t, ok, "GetDoctor response wasn't a GetDoctorInfoDefault")
+			return resp.Payload
+		},
+	}
+	type testCase struct {
+		store data.Store
+		code  int64
+	}
+	memStore := data.NewMemStore()
+	testCases := []testCase{
+		{store: memStore, code: http.StatusNotFound},
+		{store: failingStore{Store: memStore, err: driver.ErrBadConn}, code: http.StatusServiceUnavailable},
+		{store: failingStore{Store: memStore, err: fmt.Errorf("Error parsing cluster (unexpected end of JSON input)")}, code: http.StatusInternalServerError},
+	}
+	for name, get := range getters {
+		for i, tc := range testCases {
+			assert.Equal(t, get(tc.store).Code, tc.code, fmt.Sprintf("%s test case %d response code", name, i))
+		}
+	}
+
+	resp := GetComponentTrainVersions(operations.GetComponentByNameParams{Train: "stable", Component: "nosuchcomponent"}, failingStore{Store: memStore, err: driver.ErrBadConn}, nil)
+	errResp, ok := resp.(*operations.GetComponentByNameDefault)
+	assert.True(t, ok, "GetComponentTrainVersions response wasn't a GetComponentByNameDefault")
+	assert.Equal(t, errResp.Payload.Code, int64(http.StatusServiceUnavailable), "response code")
+}
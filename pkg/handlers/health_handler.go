@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// the statuses of health checks and of the server as a whole
+const (
+	healthCheckOK     = "ok"
+	healthCheckFailed = "failed"
+	healthOK          = "ok"
+	healthUnavailable = "unavailable"
+)
+
+// makeHealthStatus converts the results of a store's health checks to a response body, and returns
+// whether all of them passed
+func makeHealthStatus(checks []data.HealthCheck) (*models.HealthStatus, bool) {
+	ret := &models.HealthStatus{Status: healthOK, Checks: []*models.HealthCheck{}}
+	for _, check := range checks {
+		durationMs := int64(check.Duration / time.Millisecond)
+		result := &models.HealthCheck{Name: check.Name, Status: healthCheckOK, DurationMs: &durationMs}
+		if check.Err != nil {
+			result.Status = healthCheckFailed
+			result.Error = check.Err.Error()
+			ret.Status = healthUnavailable
+		}
+		ret.Checks = append(ret.Checks, result)
+	}
+	return ret, ret.Status == healthOK
+}
+
+// Liveness is the handler for the GET /healthz endpoint. It always succeeds if the server can
+// respond at all, so it doesn't check the database
+func Liveness() middleware.Responder {
+	return operations.NewCheckLivenessOK().WithPayload(&models.HealthStatus{Status: healthOK, Checks: []*models.HealthCheck{}})
+}
+
+// Readiness is the handler for the GET /readyz endpoint. It returns a 503 if any of store's health
+// checks fail, each of which fails if it takes longer than timeout
+func Readiness(store data.Store, timeout time.Duration) middleware.Responder {
+	status, ok := makeHealthStatus(store.CheckHealth(timeout))
+	if !ok {
+		for _, check := range status.Checks {
+			if check.Status == healthCheckFailed {
+				log.Printf("readiness check %s failed (%s)", check.Name, check.Error)
+			}
+		}
+		return operations.NewCheckReadinessServiceUnavailable().WithPayload(status)
+	}
+	return operations.NewCheckReadinessOK().WithPayload(status)
+}
@@ -2,28 +2,48 @@ package handlers
 
 import (
 	"log"
-	"net/http"
 
 	"github.com/deis/workflow-manager-api/pkg/data"
 	"github.com/deis/workflow-manager-api/pkg/swagger/models"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
-	"github.com/jinzhu/gorm"
 )
 
 // PersistentClusters is the handler for the GET /{apiVersion}/clusters/persistent endpoint
-func PersistentClusters(params operations.GetPersistentClustersParams, db *gorm.DB) middleware.Responder {
+func PersistentClusters(params operations.GetPersistentClustersParams, store data.Store) middleware.Responder {
+	fail := func(code int, payload *models.Error) middleware.Responder {
+		return operations.NewGetPersistentClustersDefault(code).WithPayload(payload)
+	}
 	persistentClustersFilter, err := parsePersistentClusterQueryKeys(params)
 	if err != nil {
-		return operations.NewGetPersistentClustersDefault(http.StatusBadRequest).WithPayload(&models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "clusters")))
 	}
 
-	checkins, err := data.FilterPersistentClusters(db, persistentClustersFilter)
+	page, err := parseClusterPage(params.Limit, operations.NewGetPersistentClustersParams().Limit, params.Cursor)
 	if err != nil {
-		log.Printf("Error filtering persistent clusters (%s)", err)
-		return operations.NewGetPersistentClustersDefault(http.StatusInternalServerError).WithPayload(&models.Error{Code: http.StatusInternalServerError, Message: err.Error()})
+		return exportError(params.HTTPRequest, fail(errorPayload(err, "clusters")))
+	}
+
+	list := func() middleware.Responder {
+		checkins, next, err := store.FilterPersistentClusters(persistentClustersFilter, page)
+		if err != nil {
+			log.Printf("Error filtering persistent clusters (%s)", err)
+			return fail(errorPayload(err, "clusters"))
+		}
+		numResults := int64(len(checkins))
+		clustersCount := models.ClustersCount{Count: &numResults, Data: checkins, NextCursor: next}
+		return operations.NewGetPersistentClustersOK().WithXNextCursor(next).WithPayload(&clustersCount)
+	}
+	if !acceptsExport(params.HTTPRequest) {
+		return list()
+	}
+	return exportResponder{
+		list: list,
+		export: func() (data.ClusterRows, error) {
+			return store.ExportPersistentClusters(persistentClustersFilter, page)
+		},
+		fail:      fail,
+		csvHeader: checkinCSVHeader,
+		resource:  "clusters",
 	}
-	numResults := int64(len(checkins))
-	clustersCount := models.ClustersCount{Count: &numResults, Data: checkins}
-	return operations.NewGetClusterCheckinsOK().WithPayload(&clustersCount)
 }
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// ReleaseAdoption is the handler for the GET /v3/stats/adoption/{train}/{component}/{release}
+// endpoint
+func ReleaseAdoption(params operations.GetReleaseAdoptionParams, store data.Store) middleware.Responder {
+	until := time.Now()
+	if params.Until != nil {
+		until = time.Time(*params.Until)
+	}
+	adoption, err := store.GetReleaseAdoption(params.Train, params.Component, params.Release, until)
+	if err != nil {
+		log.Printf("data.GetReleaseAdoption error (%s)", err)
+		code, payload := errorPayload(err, "release")
+		return operations.NewGetReleaseAdoptionDefault(code).WithPayload(payload)
+	}
+	return operations.NewGetReleaseAdoptionOK().WithPayload(&adoption)
+}
@@ -0,0 +1,113 @@
+// Package logging implements the API server's request IDs and structured request logs
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/deis/workflow-manager-api/pkg/metrics"
+	"github.com/pborman/uuid"
+)
+
+// RequestIDHeader is the header that request IDs are read from and written to. If a request
+// doesn't have one, a new ID is generated for it
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen is the longest request ID that's propagated from a request. Longer IDs are
+// replaced with a new one, so that clients can't fill the logs with arbitrary data
+const maxRequestIDLen = 128
+
+type contextKey int
+
+const entryKey contextKey = 0
+
+// Entry is the log line for a single request
+type Entry struct {
+	Time      string  `json:"time"`
+	RequestID string  `json:"request_id"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Operation string  `json:"operation"`
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	// ClusterID is the ID of the cluster that the request is for, if there is one
+	ClusterID string `json:"cluster_id,omitempty"`
+}
+
+// validRequestID returns whether id can be propagated from a request. It must be short and only
+// contain printable ASCII characters
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if c < ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// entry returns the log entry of r, or nil if r isn't being logged
+func entry(r *http.Request) *Entry {
+	if r == nil {
+		return nil
+	}
+	e, _ := r.Context().Value(entryKey).(*Entry)
+	return e
+}
+
+// RequestID returns the ID of r, or an empty string if r isn't being logged
+func RequestID(r *http.Request) string {
+	if e := entry(r); e != nil {
+		return e.RequestID
+	}
+	return ""
+}
+
+// SetClusterID records the ID of the cluster that r is for in r's log line. It does nothing if r
+// isn't being logged
+func SetClusterID(r *http.Request, clusterID string) {
+	if e := entry(r); e != nil {
+		e.ClusterID = clusterID
+	}
+}
+
+// LogRequests returns a handler that calls next, and writes a JSON log line for each request to
+// out. Each request is given an ID, which is taken from its X-Request-ID header if it has a valid
+// one, and is returned in the X-Request-ID header of the response. operation returns the name of
+// the operation that each request is for
+func LogRequests(next http.Handler, operation func(*http.Request) string, out io.Writer) http.Handler {
+	var mut sync.Mutex
+	encoder := json.NewEncoder(out)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New()
+		}
+		e := &Entry{
+			RequestID: id,
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Operation: operation(r),
+		}
+		w.Header().Set(RequestIDHeader, id)
+		recorder := metrics.NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), entryKey, e)))
+
+		e.Status = recorder.Status()
+		e.Time = start.UTC().Format(time.RFC3339Nano)
+		e.LatencyMS = float64(time.Since(start)) / float64(time.Millisecond)
+		mut.Lock()
+		defer mut.Unlock()
+		if err := encoder.Encode(e); err != nil {
+			log.Printf("error writing request log (%s)", err)
+		}
+	})
+}
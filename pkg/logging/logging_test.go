@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arschles/assert"
+)
+
+func TestLogRequests(t *testing.T) {
+	out := new(bytes.Buffer)
+	var handlerRequestID string
+	handler := LogRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = RequestID(r)
+		SetClusterID(r, "testcluster")
+		w.WriteHeader(http.StatusTeapot)
+	}), func(r *http.Request) string {
+		return "testOperation"
+	}, out)
+
+	testCases := []struct {
+		header      string
+		propagated  bool
+		description string
+	}{
+		{header: "", propagated: false, description: "no request ID"},
+		{header: "abc-123", propagated: true, description: "valid request ID"},
+		{header: "abc\x01", propagated: false, description: "request ID with a control character"},
+		{header: strings.Repeat("a", maxRequestIDLen+1), propagated: false, description: "long request ID"},
+	}
+	for i, testCase := range testCases {
+		out.Reset()
+		req, err := http.NewRequest("GET", "/v3/clusters/testcluster", nil)
+		assert.NoErr(t, err)
+		if testCase.header != "" {
+			req.Header.Set(RequestIDHeader, testCase.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, w.Code, http.StatusTeapot, fmt.Sprintf("test case %d (%s) response code", i, testCase.description))
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.True(t, id != "", "test case %d (%s) has no request ID", i, testCase.description)
+		assert.Equal(t, id == testCase.header, testCase.propagated, fmt.Sprintf("test case %d (%s) propagated", i, testCase.description))
+		assert.Equal(t, handlerRequestID, id, fmt.Sprintf("test case %d (%s) handler request ID", i, testCase.description))
+
+		entry := new(Entry)
+		assert.NoErr(t, json.Unmarshal(out.Bytes(), entry))
+		assert.Equal(t, entry.RequestID, id, fmt.Sprintf("test case %d (%s) logged request ID", i, testCase.description))
+		assert.Equal(t, entry.Method, "GET", fmt.Sprintf("test case %d (%s) logged method", i, testCase.description))
+		assert.Equal(t, entry.Path, "/v3/clusters/testcluster", fmt.Sprintf("test case %d (%s) logged path", i, testCase.description))
+		assert.Equal(t, entry.Operation, "testOperation", fmt.Sprintf("test case %d (%s) logged operation", i, testCase.description))
+		assert.Equal(t, entry.Status, http.StatusTeapot, fmt.Sprintf("test case %d (%s) logged status", i, testCase.description))
+		assert.Equal(t, entry.ClusterID, "testcluster", fmt.Sprintf("test case %d (%s) logged cluster ID", i, testCase.description))
+		assert.True(t, entry.LatencyMS >= 0, "test case %d (%s) has negative latency %f", i, testCase.description, entry.LatencyMS)
+	}
+}
+
+func TestRequestIDNotLogged(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	assert.NoErr(t, err)
+	assert.Equal(t, RequestID(req), "", "request ID")
+	assert.Equal(t, RequestID(nil), "", "request ID of a nil request")
+	// these shouldn't panic
+	SetClusterID(req, "testcluster")
+	SetClusterID(nil, "testcluster")
+}
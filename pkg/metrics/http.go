@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UnknownOperation is the operation name of requests that don't match an operation in the spec.
+// They're all counted together, so that requests for arbitrary paths can't create new time series
+const UnknownOperation = "unknown"
+
+// pathParamRegexp matches the parameters in a swagger path template, like {id}
+var pathParamRegexp = regexp.MustCompile(`\\\{[^/]+?\\\}`)
+
+type operationRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	id      string
+	// params is the number of parameters in the path template
+	params int
+}
+
+// operationRoutes sorts routes so that paths with fewer parameters come first. That way, literal
+// paths like /v3/clusters/active match before templates like /v3/clusters/{id}
+type operationRoutes []operationRoute
+
+func (o operationRoutes) Len() int           { return len(o) }
+func (o operationRoutes) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o operationRoutes) Less(i, j int) bool { return o[i].params < o[j].params }
+
+// OperationMatcher finds the swagger operation that a request is for. Create one with
+// NewOperationMatcher
+type OperationMatcher struct {
+	routes operationRoutes
+}
+
+// swaggerPaths is the part of a swagger document that NewOperationMatcher reads
+type swaggerPaths struct {
+	BasePath string                                `json:"basePath"`
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// httpMethods are the keys of a swagger path item that are operations. The other keys, like
+// "parameters", aren't
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+}
+
+type swaggerOperation struct {
+	OperationID string `json:"operationId"`
+}
+
+// NewOperationMatcher returns an OperationMatcher for the operations in the given swagger JSON
+// document
+func NewOperationMatcher(swaggerJSON []byte) (*OperationMatcher, error) {
+	var doc swaggerPaths
+	if err := json.Unmarshal(swaggerJSON, &doc); err != nil {
+		return nil, err
+	}
+	basePath := strings.TrimRight(doc.BasePath, "/")
+	matcher := &OperationMatcher{}
+	for path, operations := range doc.Paths {
+		// each parameter matches a single path segment
+		quoted := regexp.QuoteMeta(basePath + path)
+		params := len(pathParamRegexp.FindAllString(quoted, -1))
+		pattern := pathParamRegexp.ReplaceAllString(quoted, `[^/]+`)
+		re, err := regexp.Compile("^" + pattern + "/?$")
+		if err != nil {
+			return nil, err
+		}
+		for method, raw := range operations {
+			if !httpMethods[method] {
+				continue
+			}
+			var op swaggerOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, err
+			}
+			matcher.routes = append(matcher.routes, operationRoute{
+				method:  strings.ToUpper(method),
+				pattern: re,
+				id:      op.OperationID,
+				params:  params,
+			})
+		}
+	}
+	sort.Stable(operationRoutes(matcher.routes))
+	return matcher, nil
+}
+
+// Operation returns the ID of the operation that r is for, or UnknownOperation if r doesn't match any
+// operation
+func (m *OperationMatcher) Operation(r *http.Request) string {
+	for _, route := range m.routes {
+		if route.method == r.Method && route.pattern.MatchString(r.URL.Path) {
+			return route.id
+		}
+	}
+	return UnknownOperation
+}
+
+// StatusRecorder is an http.ResponseWriter that records the status code of the response. Both
+// Instrument and the request logging middleware wrap responses in one. Create one with
+// NewStatusRecorder
+type StatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// NewStatusRecorder returns a StatusRecorder that writes the response to w
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w}
+}
+
+// Status returns the status code of the response. It's 200 if the handler didn't write a status
+func (s *StatusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// WriteHeader is the http.ResponseWriter interface implementation
+func (s *StatusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write is the http.ResponseWriter interface implementation
+func (s *StatusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Flush is the http.Flusher interface implementation, so that streamed responses are still flushed
+// when they're instrumented or logged
+func (s *StatusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// HTTPMetrics are the request count and latency metrics of an HTTP server, partitioned by
+// operation and status code. Create one with NewHTTPMetrics
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics creates HTTPMetrics whose metric names start with namespace, and registers them
+// in r
+func NewHTTPMetrics(r prometheus.Registerer, namespace string) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "The number of HTTP requests, by operation and status code.",
+		}, []string{"operation", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "The time taken to serve HTTP requests, by operation and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "code"}),
+	}
+	r.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// Instrument returns a handler that calls next, and records the request in m. operation returns
+// the name of the operation that each request is for
+func (m *HTTPMetrics) Instrument(next http.Handler, operation func(*http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+		status := recorder.Status()
+		op := operation(r)
+		code := strconv.Itoa(status)
+		m.requests.WithLabelValues(op, code).Inc()
+		m.duration.WithLabelValues(op, code).Observe(time.Since(start).Seconds())
+	})
+}
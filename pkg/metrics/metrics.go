@@ -0,0 +1,65 @@
+// Package metrics instruments the API server with Prometheus metrics. The metrics are
+// github.com/prometheus/client_golang collectors, and they're served with promhttp
+package metrics
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewRegistry returns a new prometheus.Registry, with the Go runtime and process collectors
+// already registered in it
+func NewRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+// Handler returns an http.Handler that serves the metrics in r in the Prometheus exposition format
+func Handler(r *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(r, promhttp.HandlerOpts{})
+}
+
+// Sample is a single value of a GaugeFunc
+type Sample struct {
+	// LabelValues are in the order of the gauge's label names
+	LabelValues []string
+	Value       float64
+}
+
+// GaugeFunc is a gauge whose values are computed each time the metrics are collected. Unlike
+// prometheus.GaugeFunc, it can have labels. Create one with NewGaugeFunc
+type GaugeFunc struct {
+	desc *prometheus.Desc
+	name string
+	fn   func() ([]Sample, error)
+}
+
+// NewGaugeFunc returns a new GaugeFunc. fn is called each time the metrics are collected. If it
+// returns an error, the error is logged and the gauge has no values
+func NewGaugeFunc(name, help string, labelNames []string, fn func() ([]Sample, error)) *GaugeFunc {
+	return &GaugeFunc{desc: prometheus.NewDesc(name, help, labelNames, nil), name: name, fn: fn}
+}
+
+// Describe is the prometheus.Collector interface implementation
+func (g *GaugeFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+// Collect is the prometheus.Collector interface implementation
+func (g *GaugeFunc) Collect(ch chan<- prometheus.Metric) {
+	samples, err := g.fn()
+	if err != nil {
+		log.Printf("error collecting metric %s (%s)", g.name, err)
+		return
+	}
+	for _, sample := range samples {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, sample.Value, sample.LabelValues...)
+	}
+}
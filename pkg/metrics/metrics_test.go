@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arschles/assert"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func scrape(t *testing.T, r *prometheus.Registry) string {
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	assert.NoErr(t, err)
+	Handler(r).ServeHTTP(w, req)
+	assert.Equal(t, w.Code, http.StatusOK, "response code")
+	return w.Body.String()
+}
+
+// assertMetrics checks that each of the expected lines is in the scraped metrics
+func assertMetrics(t *testing.T, metrics string, expected ...string) {
+	for _, line := range expected {
+		assert.True(t, strings.Contains(metrics, line+"\n"), "metrics don't contain %s:\n%s", line, metrics)
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	metrics := scrape(t, NewRegistry())
+	assertMetrics(t, metrics, "# TYPE go_goroutines gauge", "# TYPE process_cpu_seconds_total counter")
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		NewGaugeFunc("test_count", "A test gauge.", nil, func() ([]Sample, error) {
+			return []Sample{{Value: 42}}, nil
+		}),
+		NewGaugeFunc("test_labeled", "A labeled test gauge.", []string{"train"}, func() ([]Sample, error) {
+			return []Sample{{LabelValues: []string{"beta"}, Value: 1.5}, {LabelValues: []string{"stable"}, Value: 3}}, nil
+		}),
+		NewGaugeFunc("test_failing", "A failing test gauge.", nil, func() ([]Sample, error) {
+			return nil, errors.New("test error")
+		}),
+	)
+	metrics := scrape(t, r)
+	assertMetrics(t, metrics,
+		"# HELP test_count A test gauge.",
+		"# TYPE test_count gauge",
+		"test_count 42",
+		`test_labeled{train="beta"} 1.5`,
+		`test_labeled{train="stable"} 3`,
+	)
+	assert.False(t, strings.Contains(metrics, "test_failing"), "a failing gauge was collected:\n%s", metrics)
+}
+
+const testSwaggerJSON = `{
+	"basePath": "/",
+	"paths": {
+		"/v3/clusters/{id}": {
+			"get": {"operationId": "getClusterById"},
+			"parameters": [{"name": "id", "in": "path", "type": "string", "required": true}]
+		},
+		"/v3/clusters/age": {"get": {"operationId": "getClustersByAge"}},
+		"/v3/clusters": {"get": {"operationId": "getClustersCount"}, "post": {"operationId": "createClusterDetails"}}
+	}
+}`
+
+func TestOperationMatcher(t *testing.T) {
+	matcher, err := NewOperationMatcher([]byte(testSwaggerJSON))
+	assert.NoErr(t, err)
+	testCases := []struct {
+		method    string
+		path      string
+		operation string
+	}{
+		{method: "GET", path: "/v3/clusters/abc", operation: "getClusterById"},
+		{method: "GET", path: "/v3/clusters/age", operation: "getClustersByAge"},
+		{method: "GET", path: "/v3/clusters", operation: "getClustersCount"},
+		{method: "POST", path: "/v3/clusters/", operation: "createClusterDetails"},
+		{method: "DELETE", path: "/v3/clusters", operation: UnknownOperation},
+		{method: "GET", path: "/v3/clusters/abc/def", operation: UnknownOperation},
+		{method: "GET", path: "/notapath", operation: UnknownOperation},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, testCase.path, nil)
+		assert.NoErr(t, err)
+		assert.Equal(t, matcher.Operation(req), testCase.operation, fmt.Sprintf("test case %d operation", i))
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	r := prometheus.NewRegistry()
+	m := NewHTTPMetrics(r, "test")
+	handler := m.Instrument(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte("ok"))
+	}), func(req *http.Request) string {
+		return req.URL.Path[1:]
+	})
+	for _, path := range []string{"/found", "/found", "/missing"} {
+		req, err := http.NewRequest("GET", path, nil)
+		assert.NoErr(t, err)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	assertMetrics(t, scrape(t, r),
+		`test_http_requests_total{code="200",operation="found"} 2`,
+		`test_http_requests_total{code="404",operation="missing"} 1`,
+		`test_http_request_duration_seconds_count{code="200",operation="found"} 2`,
+	)
+}
+
+func TestStatusRecorder(t *testing.T) {
+	w := httptest.NewRecorder()
+	recorder := NewStatusRecorder(w)
+	assert.Equal(t, recorder.Status(), http.StatusOK, "status before anything is written")
+	recorder.WriteHeader(http.StatusNotFound)
+	recorder.WriteHeader(http.StatusInternalServerError)
+	assert.Equal(t, recorder.Status(), http.StatusNotFound, "status")
+
+	w = httptest.NewRecorder()
+	recorder = NewStatusRecorder(w)
+	_, err := recorder.Write([]byte("ok"))
+	assert.NoErr(t, err)
+	recorder.Flush()
+	assert.Equal(t, recorder.Status(), http.StatusOK, "status after writing the body")
+	assert.True(t, w.Flushed, "response wasn't flushed")
+}
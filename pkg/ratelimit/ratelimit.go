@@ -0,0 +1,85 @@
+// Package ratelimit implements token bucket rate limits, keyed on arbitrary strings such as cluster
+// IDs or IP addresses
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often a Limiter forgets the keys whose buckets have refilled. A full bucket
+// is the same as no bucket, so this only bounds memory use
+const sweepInterval = time.Minute
+
+// bucket is the token bucket for a single key
+type bucket struct {
+	tokens float64
+	// updated is the time that tokens was last refilled
+	updated time.Time
+}
+
+// Limiter is a set of token buckets, one for each key. Each bucket holds up to burst tokens, and
+// refills at a constant rate. Create one with NewLimiter. A nil *Limiter allows everything
+type Limiter struct {
+	// rate is the number of tokens added to each bucket per second
+	rate      float64
+	burst     float64
+	mut       sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter returns a Limiter that allows perHour events for each key per hour, in bursts of up to
+// burst events. Both must be positive
+func NewLimiter(perHour, burst int) *Limiter {
+	return &Limiter{
+		rate:    float64(perHour) / time.Hour.Seconds(),
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// refill adds the tokens that b has earned since it was last refilled
+func (l *Limiter) refill(b *bucket, now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+		b.updated = now
+	}
+}
+
+// sweep forgets the keys whose buckets are full. Must be called with l.mut held
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Allow takes a token from key's bucket, and returns true if there was one. If there wasn't, it
+// returns false and how long it will be until there is
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
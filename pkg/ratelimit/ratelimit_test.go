@@ -0,0 +1,49 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arschles/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	// one token per second, in bursts of 2
+	limiter := NewLimiter(3600, 2)
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.Allow("a", now)
+		assert.True(t, ok, "event %d wasn't allowed", i)
+	}
+	ok, wait := limiter.Allow("a", now)
+	assert.False(t, ok, "event over the burst was allowed")
+	assert.Equal(t, wait, time.Second, "wait")
+	// other keys have their own buckets
+	ok, _ = limiter.Allow("b", now)
+	assert.True(t, ok, "event for another key wasn't allowed")
+
+	ok, wait = limiter.Allow("a", now.Add(500*time.Millisecond))
+	assert.False(t, ok, "event before the bucket refilled was allowed")
+	assert.Equal(t, wait, 500*time.Millisecond, "wait")
+	ok, _ = limiter.Allow("a", now.Add(time.Second))
+	assert.True(t, ok, "event after the bucket refilled wasn't allowed")
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Date(2016, time.June, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(3600, 2)
+	limiter.Allow("a", now)
+	limiter.Allow("b", now)
+	assert.Equal(t, len(limiter.buckets), 2, "number of buckets")
+	// both buckets have refilled by the time of the next sweep, so they're forgotten
+	limiter.Allow("c", now.Add(sweepInterval))
+	assert.Equal(t, len(limiter.buckets), 1, "number of buckets")
+}
+
+func TestNilLimiter(t *testing.T) {
+	var limiter *Limiter
+	for i := 0; i < 100; i++ {
+		ok, _ := limiter.Allow("a", time.Now())
+		assert.True(t, ok, "event %d wasn't allowed", i)
+	}
+}
@@ -0,0 +1,152 @@
+// Package signing signs component releases so that clusters can verify that the release data they
+// get from the API hasn't been tampered with.
+//
+// Each release is signed with Ed25519 over its canonical JSON encoding (see CanonicalJSON), and
+// the base64 encoded signature is returned in the release's signature field. Clients can get the
+// public key from the GET /v3/signing-key endpoint.
+package signing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Algorithm is the name of the signature algorithm, as returned from the signing key endpoint
+const Algorithm = "ed25519"
+
+var (
+	errNilComponentOrVersion = errors.New("component version has a nil component or version")
+)
+
+type errInvalidKeyLength struct {
+	length int
+}
+
+func (e errInvalidKeyLength) Error() string {
+	return fmt.Sprintf(
+		"invalid Ed25519 key length %d. expected a %d byte seed or a %d byte private key",
+		e.length,
+		ed25519.SeedSize,
+		ed25519.PrivateKeySize,
+	)
+}
+
+// canonicalRelease is the struct that's encoded to JSON and signed. Its fields are always
+// encoded in this order, so the encoding is stable
+type canonicalRelease struct {
+	Component string              `json:"component"`
+	Train     string              `json:"train"`
+	Version   string              `json:"version"`
+	Released  string              `json:"released"`
+	Data      *models.VersionData `json:"data"`
+}
+
+// CanonicalJSON returns the bytes that are signed for cv. It's the compact JSON encoding of an
+// object with the following keys, in this order:
+//
+//	component: the component name
+//	train: the release train
+//	version: the version string
+//	released: the release timestamp, exactly as it appears in cv
+//	data: the release data object, or null if there is none
+//
+// Other fields in cv (i.e. the component description, updateAvailable and deprecation) aren't
+// signed. Deprecations can change after a release is published, so they aren't part of its signature
+func CanonicalJSON(cv models.ComponentVersion) ([]byte, error) {
+	if cv.Component == nil || cv.Version == nil {
+		return nil, errNilComponentOrVersion
+	}
+	return json.Marshal(canonicalRelease{
+		Component: cv.Component.Name,
+		Train:     cv.Version.Train,
+		Version:   cv.Version.Version,
+		Released:  cv.Version.Released,
+		Data:      cv.Version.Data,
+	})
+}
+
+// Signer signs component releases with an Ed25519 private key. A nil *Signer is valid, and doesn't
+// sign anything
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner returns a Signer that signs with key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key}
+}
+
+// ParsePrivateKey decodes a base64 encoded Ed25519 private key. The decoded key may be either a
+// 32 byte seed or a 64 byte private key
+func ParsePrivateKey(b64 string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, errInvalidKeyLength{length: len(b)}
+	}
+}
+
+// PublicKey returns the public key that verifies s's signatures
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// SigningKey returns the public key in the form that the signing key endpoint returns
+func (s *Signer) SigningKey() models.SigningKey {
+	return models.SigningKey{
+		Algorithm: Algorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(s.PublicKey()),
+	}
+}
+
+// Sign sets cv's signature field to the base64 encoded signature of CanonicalJSON(*cv)
+func (s *Signer) Sign(cv *models.ComponentVersion) error {
+	if s == nil {
+		return nil
+	}
+	msg, err := CanonicalJSON(*cv)
+	if err != nil {
+		return err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, msg))
+	cv.Signature = &sig
+	return nil
+}
+
+// SignAll signs each component version in cvs
+func (s *Signer) SignAll(cvs []*models.ComponentVersion) error {
+	for _, cv := range cvs {
+		if err := s.Sign(cv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify returns true if cv has a valid signature from the private key that corresponds to pub
+func Verify(pub ed25519.PublicKey, cv models.ComponentVersion) bool {
+	if cv.Signature == nil {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(*cv.Signature)
+	if err != nil {
+		return false
+	}
+	msg, err := CanonicalJSON(cv)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, msg, sig)
+}
@@ -0,0 +1,101 @@
+package signing
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/arschles/assert"
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+	"golang.org/x/crypto/ed25519"
+)
+
+var testSeed = strings.Repeat("a", ed25519.SeedSize)
+
+func testSigner(t *testing.T) *Signer {
+	key, err := ParsePrivateKey(base64.StdEncoding.EncodeToString([]byte(testSeed)))
+	assert.NoErr(t, err)
+	return NewSigner(key)
+}
+
+func testComponentVersion() *models.ComponentVersion {
+	return &models.ComponentVersion{
+		Component: &models.Component{Name: "testcomponent"},
+		Version: &models.Version{
+			Train:    "stable",
+			Version:  "1.0.0",
+			Released: "2016-06-01T00:00:00Z",
+			Data:     &models.VersionData{Description: "a release"},
+		},
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	type testCase struct {
+		key   []byte
+		valid bool
+	}
+	full := ed25519.NewKeyFromSeed([]byte(testSeed))
+	testCases := []testCase{
+		{key: []byte(testSeed), valid: true},
+		{key: full, valid: true},
+		{key: []byte("tooshort"), valid: false},
+	}
+	for i, tc := range testCases {
+		key, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(tc.key))
+		if !tc.valid {
+			assert.True(t, err != nil, "expected an error for test case %d", i)
+			continue
+		}
+		assert.NoErr(t, err)
+		assert.Equal(t, []byte(key), []byte(full), "private key")
+	}
+	_, err := ParsePrivateKey("not base64!")
+	assert.True(t, err != nil, "expected an error for invalid base64")
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer := testSigner(t)
+	cv := testComponentVersion()
+	assert.NoErr(t, signer.Sign(cv))
+	assert.True(t, cv.Signature != nil, "signature wasn't set")
+	assert.True(t, Verify(signer.PublicKey(), *cv), "signature didn't verify")
+
+	key := signer.SigningKey()
+	assert.Equal(t, key.Algorithm, Algorithm, "algorithm")
+	pub, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	assert.NoErr(t, err)
+	assert.True(t, Verify(ed25519.PublicKey(pub), *cv), "signature didn't verify with the published key")
+
+	// fields that aren't signed can change without invalidating the signature
+	desc := "a description"
+	cv.Component.Description = &desc
+	assert.True(t, Verify(signer.PublicKey(), *cv), "signature didn't verify after changing an unsigned field")
+}
+
+func TestVerifyTampered(t *testing.T) {
+	signer := testSigner(t)
+	tamperFuncs := []func(cv *models.ComponentVersion){
+		func(cv *models.ComponentVersion) { cv.Component.Name = "othercomponent" },
+		func(cv *models.ComponentVersion) { cv.Version.Train = "beta" },
+		func(cv *models.ComponentVersion) { cv.Version.Version = "1.0.1" },
+		func(cv *models.ComponentVersion) { cv.Version.Released = "2016-06-02T00:00:00Z" },
+		func(cv *models.ComponentVersion) { cv.Version.Data.Description = "a tampered release" },
+		func(cv *models.ComponentVersion) { cv.Signature = nil },
+	}
+	for i, tamper := range tamperFuncs {
+		cv := testComponentVersion()
+		assert.NoErr(t, signer.Sign(cv))
+		tamper(cv)
+		assert.False(t, Verify(signer.PublicKey(), *cv), "tampered release %d verified", i)
+	}
+}
+
+func TestNilSigner(t *testing.T) {
+	var signer *Signer
+	cvs := []*models.ComponentVersion{testComponentVersion(), testComponentVersion()}
+	assert.NoErr(t, signer.SignAll(cvs))
+	for _, cv := range cvs {
+		assert.True(t, cv.Signature == nil, "nil signer set a signature")
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*ActiveClustersBucket active clusters bucket
+
+swagger:model activeClustersBucket
+*/
+type ActiveClustersBucket struct {
+
+	/* the number of distinct clusters that checked in during the bucket
+	 */
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	/* the first UTC day of the bucket, formatted as YYYY-MM-DD
+	 */
+	Start string `json:"start,omitempty"`
+}
+
+// Validate validates this active clusters bucket
+func (m *ActiveClustersBucket) Validate(formats strfmt.Registry) error {
+	return nil
+}
@@ -0,0 +1,254 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ClusterCheckinDiff cluster checkin diff
+
+swagger:model clusterCheckinDiff
+*/
+type ClusterCheckinDiff struct {
+
+	/* added
+
+	Required: true
+	*/
+	Added []*ComponentChange `json:"added"`
+
+	/* components whose version or train changed, but whose versions can't be ordered (i.e. they aren't semantic versions)
+
+	Required: true
+	*/
+	Changed []*ComponentChange `json:"changed"`
+
+	/* cluster ID
+
+	Required: true
+	Min Length: 1
+	*/
+	ClusterID string `json:"clusterID"`
+
+	/* downgraded
+
+	Required: true
+	*/
+	Downgraded []*ComponentChange `json:"downgraded"`
+
+	/* from
+
+	Required: true
+	*/
+	From *ClusterCheckinRef `json:"from"`
+
+	/* removed
+
+	Required: true
+	*/
+	Removed []*ComponentChange `json:"removed"`
+
+	/* to
+
+	Required: true
+	*/
+	To *ClusterCheckinRef `json:"to"`
+
+	/* upgraded
+
+	Required: true
+	*/
+	Upgraded []*ComponentChange `json:"upgraded"`
+}
+
+// Validate validates this cluster checkin diff
+func (m *ClusterCheckinDiff) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateAdded(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateChanged(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateClusterID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateDowngraded(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateFrom(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateRemoved(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateTo(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateUpgraded(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateAdded(formats strfmt.Registry) error {
+
+	if err := validate.Required("added", "body", m.Added); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Added); i++ {
+
+		if m.Added[i] != nil {
+
+			if err := m.Added[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateChanged(formats strfmt.Registry) error {
+
+	if err := validate.Required("changed", "body", m.Changed); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Changed); i++ {
+
+		if m.Changed[i] != nil {
+
+			if err := m.Changed[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateClusterID(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("clusterID", "body", string(m.ClusterID)); err != nil {
+		return err
+	}
+
+	if err := validate.MinLength("clusterID", "body", string(m.ClusterID), 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateDowngraded(formats strfmt.Registry) error {
+
+	if err := validate.Required("downgraded", "body", m.Downgraded); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Downgraded); i++ {
+
+		if m.Downgraded[i] != nil {
+
+			if err := m.Downgraded[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateFrom(formats strfmt.Registry) error {
+
+	if m.From != nil {
+
+		if err := m.From.Validate(formats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateRemoved(formats strfmt.Registry) error {
+
+	if err := validate.Required("removed", "body", m.Removed); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Removed); i++ {
+
+		if m.Removed[i] != nil {
+
+			if err := m.Removed[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateTo(formats strfmt.Registry) error {
+
+	if m.To != nil {
+
+		if err := m.To.Validate(formats); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinDiff) validateUpgraded(formats strfmt.Registry) error {
+
+	if err := validate.Required("upgraded", "body", m.Upgraded); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Upgraded); i++ {
+
+		if m.Upgraded[i] != nil {
+
+			if err := m.Upgraded[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
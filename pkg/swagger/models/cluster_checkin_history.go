@@ -0,0 +1,63 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ClusterCheckinHistory cluster checkin history
+
+swagger:model clusterCheckinHistory
+*/
+type ClusterCheckinHistory struct {
+
+	/* data
+
+	Required: true
+	*/
+	Data []*ClusterCheckinRecord `json:"data"`
+
+	/* pass as the cursor parameter to get the next page. absent on the last page
+	 */
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Validate validates this cluster checkin history
+func (m *ClusterCheckinHistory) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateData(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ClusterCheckinHistory) validateData(formats strfmt.Registry) error {
+
+	if err := validate.Required("data", "body", m.Data); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Data); i++ {
+
+		if m.Data[i] != nil {
+
+			if err := m.Data[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
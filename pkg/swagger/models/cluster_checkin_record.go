@@ -0,0 +1,104 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ClusterCheckinRecord cluster checkin record
+
+swagger:model clusterCheckinRecord
+*/
+type ClusterCheckinRecord struct {
+
+	/* checked in at
+
+	Required: true
+	*/
+	CheckedInAt strfmt.DateTime `json:"checkedInAt"`
+
+	/* components
+
+	Required: true
+	*/
+	Components []*ComponentVersion `json:"components"`
+
+	/* id
+
+	Required: true
+	Min Length: 1
+	*/
+	ID string `json:"id"`
+}
+
+// Validate validates this cluster checkin record
+func (m *ClusterCheckinRecord) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateCheckedInAt(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateComponents(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ClusterCheckinRecord) validateCheckedInAt(formats strfmt.Registry) error {
+
+	if err := validate.Required("checkedInAt", "body", strfmt.DateTime(m.CheckedInAt)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinRecord) validateComponents(formats strfmt.Registry) error {
+
+	if err := validate.Required("components", "body", m.Components); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Components); i++ {
+
+		if m.Components[i] != nil {
+
+			if err := m.Components[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinRecord) validateID(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("id", "body", string(m.ID)); err != nil {
+		return err
+	}
+
+	if err := validate.MinLength("id", "body", string(m.ID), 1); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ClusterCheckinRef cluster checkin ref
+
+swagger:model clusterCheckinRef
+*/
+type ClusterCheckinRef struct {
+
+	/* checked in at
+
+	Required: true
+	*/
+	CheckedInAt strfmt.DateTime `json:"checkedInAt"`
+
+	/* id
+
+	Required: true
+	Min Length: 1
+	*/
+	ID string `json:"id"`
+}
+
+// Validate validates this cluster checkin ref
+func (m *ClusterCheckinRef) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateCheckedInAt(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateID(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ClusterCheckinRef) validateCheckedInAt(formats strfmt.Registry) error {
+
+	if err := validate.Required("checkedInAt", "body", strfmt.DateTime(m.CheckedInAt)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ClusterCheckinRef) validateID(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("id", "body", string(m.ID)); err != nil {
+		return err
+	}
+
+	if err := validate.MinLength("id", "body", string(m.ID), 1); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ClusterChurn cluster churn
+
+swagger:model clusterChurn
+*/
+type ClusterChurn struct {
+
+	/* one entry for each period in the requested range, oldest first
+
+	Required: true
+	*/
+	Data []*ClusterChurnPeriod `json:"data"`
+
+	/* interval
+	 */
+	Interval string `json:"interval,omitempty"`
+}
+
+// Validate validates this cluster churn
+func (m *ClusterChurn) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateData(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ClusterChurn) validateData(formats strfmt.Registry) error {
+
+	if err := validate.Required("data", "body", m.Data); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Data); i++ {
+
+		if m.Data[i] != nil {
+
+			if err := m.Data[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
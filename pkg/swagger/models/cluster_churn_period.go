@@ -0,0 +1,38 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*ClusterChurnPeriod cluster churn period
+
+swagger:model clusterChurnPeriod
+*/
+type ClusterChurnPeriod struct {
+
+	/* the number of clusters that went dormant during the period
+	 */
+	Lost *int64 `json:"lost,omitempty"`
+
+	/* the number of clusters that first checked in during the period
+	 */
+	New *int64 `json:"new,omitempty"`
+
+	/* the number of dormant clusters that checked in again during the period
+	 */
+	Reactivated *int64 `json:"reactivated,omitempty"`
+
+	/* the number of clusters that were active at the start of the period and didn't go dormant during it
+	 */
+	Retained *int64 `json:"retained,omitempty"`
+
+	/* the first UTC day of the period, formatted as YYYY-MM-DD
+	 */
+	Start string `json:"start,omitempty"`
+}
+
+// Validate validates this cluster churn period
+func (m *ClusterChurnPeriod) Validate(formats strfmt.Registry) error {
+	return nil
+}
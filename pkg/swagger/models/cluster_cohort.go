@@ -0,0 +1,30 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*ClusterCohort cluster cohort
+
+swagger:model clusterCohort
+*/
+type ClusterCohort struct {
+
+	/* the number of clusters that first checked in during the cohort's period
+	 */
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	/* the fraction of the cohort that checked in during each period, starting with the cohort's own period. the nth entry is n periods later
+	 */
+	Retention []float64 `json:"retention,omitempty"`
+
+	/* the first UTC day of the cohort's period, formatted as YYYY-MM-DD
+	 */
+	Start string `json:"start,omitempty"`
+}
+
+// Validate validates this cluster cohort
+func (m *ClusterCohort) Validate(formats strfmt.Registry) error {
+	return nil
+}
@@ -23,6 +23,10 @@ type ClustersCount struct {
 	/* data
 	 */
 	Data []*ClusterCheckin `json:"data,omitempty"`
+
+	/* pass as the cursor parameter to get the next page. absent on the last page
+	 */
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Validate validates this clusters count
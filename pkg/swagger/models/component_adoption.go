@@ -0,0 +1,71 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ComponentAdoption component adoption
+
+swagger:model componentAdoption
+*/
+type ComponentAdoption struct {
+
+	/* the number of clusters whose latest checkin reported any version of the component on the train
+	 */
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	/* component
+	 */
+	Component string `json:"component,omitempty"`
+
+	/* train
+	 */
+	Train string `json:"train,omitempty"`
+
+	/* each release of the component on the train, newest first
+
+	Required: true
+	*/
+	Versions []*VersionAdoption `json:"versions"`
+}
+
+// Validate validates this component adoption
+func (m *ComponentAdoption) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateVersions(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ComponentAdoption) validateVersions(formats strfmt.Registry) error {
+
+	if err := validate.Required("versions", "body", m.Versions); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Versions); i++ {
+
+		if m.Versions[i] != nil {
+
+			if err := m.Versions[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ComponentChange component change
+
+swagger:model componentChange
+*/
+type ComponentChange struct {
+
+	/* the component's version in the earlier checkin. absent if the component was added
+	 */
+	From *Version `json:"from,omitempty"`
+
+	/* name
+
+	Required: true
+	Min Length: 1
+	*/
+	Name string `json:"name"`
+
+	/* the component's version in the later checkin. absent if the component was removed
+	 */
+	To *Version `json:"to,omitempty"`
+}
+
+// Validate validates this component change
+func (m *ComponentChange) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateName(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ComponentChange) validateName(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("name", "body", string(m.Name)); err != nil {
+		return err
+	}
+
+	if err := validate.MinLength("name", "body", string(m.Name), 1); err != nil {
+		return err
+	}
+
+	return nil
+}
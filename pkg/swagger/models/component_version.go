@@ -15,6 +15,14 @@ type ComponentVersion struct {
 	 */
 	Component *Component `json:"component,omitempty"`
 
+	/* deprecation
+	 */
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+
+	/* base64 encoded Ed25519 signature of the canonical JSON encoding of the release
+	 */
+	Signature *string `json:"signature,omitempty"`
+
 	/* update available
 	 */
 	UpdateAvailable *string `json:"updateAvailable,omitempty"`
@@ -0,0 +1,26 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*DailyAdoption daily adoption
+
+swagger:model dailyAdoption
+*/
+type DailyAdoption struct {
+
+	/* the number of clusters that reported the release in a checkin on this day
+	 */
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	/* the UTC day, formatted as YYYY-MM-DD
+	 */
+	Date string `json:"date,omitempty"`
+}
+
+// Validate validates this daily adoption
+func (m *DailyAdoption) Validate(formats strfmt.Registry) error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*Deprecation deprecation
+
+swagger:model deprecation
+*/
+type Deprecation struct {
+
+	/* when the release was deprecated or yanked. set by the server
+	 */
+	ChangedAt *strfmt.DateTime `json:"changedAt,omitempty"`
+
+	/* why the release was deprecated or yanked
+	 */
+	Reason string `json:"reason,omitempty"`
+
+	/* deprecated releases are still advertised as the latest release of their train, yanked releases aren't. active clears the deprecation, and is only valid in requests
+
+	Required: true
+	*/
+	Status string `json:"status"`
+}
+
+// Validate validates this deprecation
+func (m *Deprecation) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateStatus(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Deprecation) validateStatus(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("status", "body", string(m.Status)); err != nil {
+		return err
+	}
+
+	return nil
+}
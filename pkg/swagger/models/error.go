@@ -5,6 +5,7 @@ package models
 
 import (
 	strfmt "github.com/go-swagger/go-swagger/strfmt"
+	"github.com/go-swagger/go-swagger/swag"
 
 	"github.com/go-swagger/go-swagger/errors"
 	"github.com/go-swagger/go-swagger/httpkit/validate"
@@ -22,11 +23,19 @@ type Error struct {
 	*/
 	Code int64 `json:"code"`
 
+	/* the fields of the request body that failed validation. only present on 422 responses
+	 */
+	Fields []*FieldError `json:"fields,omitempty"`
+
 	/* message
 
 	Required: true
 	*/
 	Message string `json:"message"`
+
+	/* a stable, machine readable code for the class of the error, like not_found or database_unavailable
+	 */
+	Reason string `json:"reason,omitempty"`
 }
 
 // Validate validates this error
@@ -38,6 +47,11 @@ func (m *Error) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateFields(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateMessage(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -58,6 +72,26 @@ func (m *Error) validateCode(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Error) validateFields(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Fields) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Fields); i++ {
+
+		if m.Fields[i] != nil {
+
+			if err := m.Fields[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *Error) validateMessage(formats strfmt.Registry) error {
 
 	if err := validate.RequiredString("message", "body", string(m.Message)); err != nil {
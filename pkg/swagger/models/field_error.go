@@ -0,0 +1,68 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*FieldError field error
+
+swagger:model fieldError
+*/
+type FieldError struct {
+
+	/* the path of the field in the request body, like components[0].component.name
+
+	Required: true
+	*/
+	Field string `json:"field"`
+
+	/* why the field is invalid
+
+	Required: true
+	*/
+	Message string `json:"message"`
+}
+
+// Validate validates this field error
+func (m *FieldError) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateField(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateMessage(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *FieldError) validateField(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("field", "body", string(m.Field)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *FieldError) validateMessage(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("message", "body", string(m.Message)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*HealthCheck health check
+
+swagger:model healthCheck
+*/
+type HealthCheck struct {
+
+	/* how long the check took, in milliseconds
+	 */
+	DurationMs *int64 `json:"durationMs,omitempty"`
+
+	/* why the check failed. empty if it passed
+	 */
+	Error string `json:"error,omitempty"`
+
+	/* the name of the check
+	 */
+	Name string `json:"name,omitempty"`
+
+	/* ok if the check passed, otherwise failed
+	 */
+	Status string `json:"status,omitempty"`
+}
+
+// Validate validates this health check
+func (m *HealthCheck) Validate(formats strfmt.Registry) error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+	"github.com/go-swagger/go-swagger/swag"
+
+	"github.com/go-swagger/go-swagger/errors"
+)
+
+/*HealthStatus health status
+
+swagger:model healthStatus
+*/
+type HealthStatus struct {
+
+	/* the result of each check, in the order they ran
+	 */
+	Checks []*HealthCheck `json:"checks,omitempty"`
+
+	/* ok if every check passed, otherwise unavailable
+	 */
+	Status string `json:"status,omitempty"`
+}
+
+// Validate validates this health status
+func (m *HealthStatus) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateChecks(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *HealthStatus) validateChecks(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Checks) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Checks); i++ {
+
+		if m.Checks[i] != nil {
+
+			if err := m.Checks[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
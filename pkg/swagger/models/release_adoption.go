@@ -0,0 +1,75 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*ReleaseAdoption release adoption
+
+swagger:model releaseAdoption
+*/
+type ReleaseAdoption struct {
+
+	/* component
+	 */
+	Component string `json:"component,omitempty"`
+
+	/* one entry for each day from the release day onward, oldest first
+
+	Required: true
+	*/
+	Data []*DailyAdoption `json:"data"`
+
+	/* released
+	 */
+	Released string `json:"released,omitempty"`
+
+	/* train
+	 */
+	Train string `json:"train,omitempty"`
+
+	/* version
+	 */
+	Version string `json:"version,omitempty"`
+}
+
+// Validate validates this release adoption
+func (m *ReleaseAdoption) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateData(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ReleaseAdoption) validateData(formats strfmt.Registry) error {
+
+	if err := validate.Required("data", "body", m.Data); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(m.Data); i++ {
+
+		if m.Data[i] != nil {
+
+			if err := m.Data[i].Validate(formats); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
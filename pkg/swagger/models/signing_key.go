@@ -0,0 +1,68 @@
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+)
+
+/*SigningKey signing key
+
+swagger:model signingKey
+*/
+type SigningKey struct {
+
+	/* algorithm
+
+	Required: true
+	*/
+	Algorithm string `json:"algorithm"`
+
+	/* base64 encoded public key
+
+	Required: true
+	*/
+	PublicKey string `json:"publicKey"`
+}
+
+// Validate validates this signing key
+func (m *SigningKey) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateAlgorithm(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validatePublicKey(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *SigningKey) validateAlgorithm(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("algorithm", "body", string(m.Algorithm)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *SigningKey) validatePublicKey(formats strfmt.Registry) error {
+
+	if err := validate.RequiredString("publicKey", "body", string(m.PublicKey)); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import "github.com/go-swagger/go-swagger/strfmt"
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+/*VersionAdoption version adoption
+
+swagger:model versionAdoption
+*/
+type VersionAdoption struct {
+
+	/* the number of clusters whose latest checkin reported this version
+	 */
+	Clusters *int64 `json:"clusters,omitempty"`
+
+	/* released
+	 */
+	Released string `json:"released,omitempty"`
+
+	/* version
+	 */
+	Version string `json:"version,omitempty"`
+}
+
+// Validate validates this version adoption
+func (m *VersionAdoption) Validate(formats strfmt.Registry) error {
+	return nil
+}
@@ -3,30 +3,70 @@ package restapi
 import (
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/deis/workflow-manager-api/config"
 	"github.com/deis/workflow-manager-api/pkg/data"
 	"github.com/deis/workflow-manager-api/pkg/handlers"
+	"github.com/deis/workflow-manager-api/pkg/logging"
+	"github.com/deis/workflow-manager-api/pkg/metrics"
+	"github.com/deis/workflow-manager-api/pkg/ratelimit"
+	"github.com/deis/workflow-manager-api/pkg/signing"
 	"github.com/deis/workflow-manager-api/pkg/swagger/restapi/operations"
 	errors "github.com/go-swagger/go-swagger/errors"
 	httpkit "github.com/go-swagger/go-swagger/httpkit"
 	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
 	"github.com/jinzhu/gorm"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// checkinCompactionInterval is how often old checkins are compacted
+const checkinCompactionInterval = time.Hour
+
+// dormancyDetectionInterval is how often clusters are checked for dormancy
+const dormancyDetectionInterval = time.Hour
+
+// the default check-in limits, used when they aren't configured. Clusters normally check in a few
+// times a day, but many clusters can share a source IP behind NAT
+const (
+	defaultCheckinClusterRatePerHour = 60
+	defaultCheckinClusterBurst       = 10
+	defaultCheckinIPRatePerHour      = 3600
+	defaultCheckinIPBurst            = 300
+	defaultCheckinMinInterval        = time.Minute
+)
+
+// readinessCheckTimeout is how long each of the readiness checks can take before it fails
+const readinessCheckTimeout = 2 * time.Second
+
+// metricsNamespace is the prefix of the names of the metrics that the server exports
+const metricsNamespace = "workflow_manager_api"
+
+// metricsPath is the path that the server's Prometheus metrics are served on
+const metricsPath = "/metrics"
+
+// activeClusterWindow is how recently a cluster must have checked in to count towards the active
+// clusters metric
+const activeClusterWindow = 24 * time.Hour
+
 type GormDb struct {
 	db *gorm.DB
 }
 
 // This file is safe to edit. Once it exists it will not be overwritten
-func getDb(api *operations.WorkflowManagerAPI) *gorm.DB {
+func getStore(api *operations.WorkflowManagerAPI) data.Store {
 	for _, optsGroup := range api.CommandLineOptionsGroups {
 		if optsGroup.ShortDescription == "deisUnitTests" {
-			gormDb, ok := optsGroup.Options.(GormDb)
-			if !ok {
-				log.Fatalf("unable to cast to gorm db\n")
+			switch opts := optsGroup.Options.(type) {
+			case GormDb:
+				setSQLLogLevel(opts.db)
+				return data.NewGormStore(opts.db)
+			case data.Store:
+				return opts
+			default:
+				log.Fatalf("unable to cast to gorm db or data store\n")
 			}
-			return gormDb.db
 		}
 	}
 	db, err := data.NewDB()
@@ -36,7 +76,157 @@ func getDb(api *operations.WorkflowManagerAPI) *gorm.DB {
 	if err := data.VerifyPersistentStorage(db); err != nil {
 		log.Fatalf("unable to verify persistent storage\n%s", err)
 	}
-	return db
+	setSQLLogLevel(db)
+	return data.NewGormStore(db)
+}
+
+// setSQLLogLevel sets which of db's queries are logged to the configured level
+func setSQLLogLevel(db *gorm.DB) {
+	if err := data.SetSQLLogLevel(db, config.Spec.SQLLogLevel); err != nil {
+		log.Fatalf("unable to set the SQL log level (%s)", err)
+	}
+}
+
+// requestStore returns store with the ID of r added to the errors that it returns
+func requestStore(store data.Store, r *http.Request) data.Store {
+	return data.NewRequestStore(store, logging.RequestID(r))
+}
+
+// getSigner returns the signer for release data, or nil if no signing key is configured
+func getSigner() *signing.Signer {
+	if config.Spec.SigningKey == "" {
+		log.Printf("no signing key configured, release data will not be signed")
+		return nil
+	}
+	key, err := signing.ParsePrivateKey(config.Spec.SigningKey)
+	if err != nil {
+		log.Fatalf("unable to parse signing key (%s)", err)
+	}
+	return signing.NewSigner(key)
+}
+
+// newCheckinLimiter returns a limiter with the given rate and burst, or their defaults if they're
+// 0. It returns nil, which doesn't limit anything, if the rate is negative
+func newCheckinLimiter(perHour, burst, defaultPerHour, defaultBurst int) *ratelimit.Limiter {
+	if perHour < 0 {
+		return nil
+	}
+	if perHour == 0 {
+		perHour = defaultPerHour
+	}
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+	return ratelimit.NewLimiter(perHour, burst)
+}
+
+// getCheckinLimits returns the configured limits on how often clusters can check in
+func getCheckinLimits() handlers.CheckinLimits {
+	minInterval := time.Duration(config.Spec.CheckinMinIntervalSeconds) * time.Second
+	if config.Spec.CheckinMinIntervalSeconds == 0 {
+		minInterval = defaultCheckinMinInterval
+	}
+	return handlers.CheckinLimits{
+		PerCluster: newCheckinLimiter(
+			config.Spec.CheckinClusterRatePerHour,
+			config.Spec.CheckinClusterBurst,
+			defaultCheckinClusterRatePerHour,
+			defaultCheckinClusterBurst,
+		),
+		PerIP: newCheckinLimiter(
+			config.Spec.CheckinIPRatePerHour,
+			config.Spec.CheckinIPBurst,
+			defaultCheckinIPRatePerHour,
+			defaultCheckinIPBurst,
+		),
+		MinInterval:       minInterval,
+		TrustForwardedFor: config.Spec.CheckinTrustForwardedFor,
+	}
+}
+
+// startCheckinCompaction starts compacting old checkins in the background, if a checkin retention
+// period is configured
+func startCheckinCompaction(store data.Store) {
+	days := config.Spec.CheckinRetentionDays
+	if days <= 0 {
+		log.Printf("no checkin retention period configured, checkins will not be compacted")
+		return
+	}
+	go data.RunCheckinCompaction(store, days, checkinCompactionInterval, nil)
+}
+
+// startDormancyDetection starts recording clusters that go dormant or are reactivated in the
+// background
+func startDormancyDetection(store data.Store) {
+	days := config.Spec.ClusterDormancyDays
+	if days <= 0 {
+		days = data.DefaultDormancyDays
+	}
+	go data.RunDormancyDetection(store, data.DormancyWindow(days), dormancyDetectionInterval, nil)
+}
+
+// instrumentStore returns store with the duration of each of its queries recorded in registry
+func instrumentStore(registry prometheus.Registerer, store data.Store) data.Store {
+	queryDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "The time taken by database queries, by data package function.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"function"})
+	registry.MustRegister(queryDuration)
+	return data.NewInstrumentedStore(store, func(function string, duration time.Duration) {
+		queryDuration.WithLabelValues(function).Observe(duration.Seconds())
+	})
+}
+
+// countSample returns a single unlabeled gauge sample of a count returned by a store
+func countSample(count int, err error) ([]metrics.Sample, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []metrics.Sample{{Value: float64(count)}}, nil
+}
+
+// registerStoreGauges registers gauges of the numbers of clusters, doctor reports and releases in
+// store. They're computed from the store each time the metrics are scraped
+func registerStoreGauges(registry prometheus.Registerer, store data.Store) {
+	registry.MustRegister(metrics.NewGaugeFunc(
+		metricsNamespace+"_clusters",
+		"The total number of clusters that have checked in.",
+		nil,
+		func() ([]metrics.Sample, error) {
+			return countSample(store.GetClusterCount())
+		},
+	), metrics.NewGaugeFunc(
+		metricsNamespace+"_active_clusters",
+		"The number of clusters that checked in during the last 24 hours.",
+		nil,
+		func() ([]metrics.Sample, error) {
+			return countSample(store.GetActiveClusterCount(time.Now().Add(-activeClusterWindow)))
+		},
+	), metrics.NewGaugeFunc(
+		metricsNamespace+"_doctor_reports",
+		"The total number of doctor reports.",
+		nil,
+		func() ([]metrics.Sample, error) {
+			return countSample(store.GetDoctorCount())
+		},
+	), metrics.NewGaugeFunc(
+		metricsNamespace+"_versions",
+		"The number of component releases, by train.",
+		[]string{"train"},
+		func() ([]metrics.Sample, error) {
+			counts, err := store.GetVersionCountsByTrain()
+			if err != nil {
+				return nil, err
+			}
+			samples := []metrics.Sample{}
+			for train, count := range counts {
+				samples = append(samples, metrics.Sample{LabelValues: []string{train}, Value: float64(count)})
+			}
+			return samples, nil
+		},
+	))
 }
 
 func configureFlags(api *operations.WorkflowManagerAPI) {
@@ -45,8 +235,13 @@ func configureFlags(api *operations.WorkflowManagerAPI) {
 
 func configureAPI(api *operations.WorkflowManagerAPI) http.Handler {
 
-	db := getDb(api)
-	db.LogMode(true)
+	registry := metrics.NewRegistry()
+	store := instrumentStore(registry, getStore(api))
+	registerStoreGauges(registry, store)
+	signer := getSigner()
+	checkinLimits := getCheckinLimits()
+	startCheckinCompaction(store)
+	startDormancyDetection(store)
 	// configure the api here
 	api.ServeError = errors.ServeError
 
@@ -54,6 +249,10 @@ func configureAPI(api *operations.WorkflowManagerAPI) http.Handler {
 
 	api.JSONProducer = httpkit.JSONProducer()
 
+	api.CSVProducer = handlers.CSVProducer()
+
+	api.NDJSONProducer = handlers.NDJSONProducer()
+
 	api.BasicAuth = func(user string, pass string) (interface{}, error) {
 		expectedUser := config.Spec.DoctorAuthUser
 		expectedPass := config.Spec.DoctorAuthPass
@@ -63,57 +262,115 @@ func configureAPI(api *operations.WorkflowManagerAPI) http.Handler {
 		return nil, errors.Unauthenticated("basic auth")
 	}
 
+	api.PublisherTokenAuth = func(token string) (interface{}, error) {
+		publisherToken, err := store.GetPublisherToken(token)
+		if err == data.ErrNotFound {
+			return nil, errors.Unauthenticated("publisher token")
+		} else if err != nil {
+			log.Printf("data.GetPublisherToken error (%s)", err)
+			return nil, errors.New(http.StatusInternalServerError, "unable to verify publisher token")
+		}
+		return publisherToken, nil
+	}
+
 	api.CreateClusterDetailsHandler = operations.CreateClusterDetailsHandlerFunc(func(params operations.CreateClusterDetailsParams) middleware.Responder {
-		return handlers.ClusterCheckin(params, db)
+		if params.Body != nil {
+			logging.SetClusterID(params.HTTPRequest, params.Body.ID)
+		}
+		return handlers.ClusterCheckin(params, requestStore(store, params.HTTPRequest), checkinLimits)
 	})
 
 	api.CreateClusterDetailsForV2Handler = operations.CreateClusterDetailsForV2HandlerFunc(func(params operations.CreateClusterDetailsForV2Params) middleware.Responder {
-		return handlers.ClusterCheckin(operations.CreateClusterDetailsParams{Body: params.Body}, db)
+		logging.SetClusterID(params.HTTPRequest, params.ID)
+		checkinParams := operations.CreateClusterDetailsParams{HTTPRequest: params.HTTPRequest, Body: params.Body}
+		return handlers.ClusterCheckin(checkinParams, requestStore(store, params.HTTPRequest), checkinLimits)
 	})
 
+	api.GetActiveClustersHandler = operations.GetActiveClustersHandlerFunc(func(params operations.GetActiveClustersParams) middleware.Responder {
+		return handlers.ActiveClusters(params, requestStore(store, params.HTTPRequest))
+	})
 	api.GetClusterByIDHandler = operations.GetClusterByIDHandlerFunc(func(params operations.GetClusterByIDParams) middleware.Responder {
-		return handlers.GetCluster(params, db)
+		logging.SetClusterID(params.HTTPRequest, params.ID)
+		return handlers.GetCluster(params, requestStore(store, params.HTTPRequest))
+	})
+	api.GetClusterChurnHandler = operations.GetClusterChurnHandlerFunc(func(params operations.GetClusterChurnParams) middleware.Responder {
+		return handlers.ClusterChurn(params, requestStore(store, params.HTTPRequest))
+	})
+	api.GetClusterCohortsHandler = operations.GetClusterCohortsHandlerFunc(func(params operations.GetClusterCohortsParams) middleware.Responder {
+		return handlers.ClusterCohorts(params, requestStore(store, params.HTTPRequest))
+	})
+	api.GetClusterCheckinDiffHandler = operations.GetClusterCheckinDiffHandlerFunc(func(params operations.GetClusterCheckinDiffParams) middleware.Responder {
+		logging.SetClusterID(params.HTTPRequest, params.ID)
+		return handlers.ClusterCheckinDiff(params, requestStore(store, params.HTTPRequest))
+	})
+	api.GetClusterCheckinHistoryHandler = operations.GetClusterCheckinHistoryHandlerFunc(func(params operations.GetClusterCheckinHistoryParams) middleware.Responder {
+		logging.SetClusterID(params.HTTPRequest, params.ID)
+		return handlers.ClusterCheckinHistory(params, requestStore(store, params.HTTPRequest))
 	})
 	api.GetClustersByAgeHandler = operations.GetClustersByAgeHandlerFunc(func(params operations.GetClustersByAgeParams) middleware.Responder {
-		return handlers.ClustersAge(params, db)
+		return handlers.ClustersAge(params, requestStore(store, params.HTTPRequest))
 	})
 	api.GetClustersCountHandler = operations.GetClustersCountHandlerFunc(func() middleware.Responder {
-		return handlers.ClustersCount(db)
+		return handlers.ClustersCount(store)
 	})
 	api.GetClusterCheckinsHandler = operations.GetClusterCheckinsHandlerFunc(func(params operations.GetClusterCheckinsParams) middleware.Responder {
-		return handlers.ClusterCheckins(params, db)
+		return handlers.ClusterCheckins(params, requestStore(store, params.HTTPRequest))
 	})
 	api.GetPersistentClustersHandler = operations.GetPersistentClustersHandlerFunc(func(params operations.GetPersistentClustersParams) middleware.Responder {
-		return handlers.PersistentClusters(params, db)
+		return handlers.PersistentClusters(params, requestStore(store, params.HTTPRequest))
+	})
+	api.GetComponentAdoptionHandler = operations.GetComponentAdoptionHandlerFunc(func(params operations.GetComponentAdoptionParams) middleware.Responder {
+		return handlers.ComponentAdoption(params, requestStore(store, params.HTTPRequest))
 	})
 	api.GetComponentByNameHandler = operations.GetComponentByNameHandlerFunc(func(params operations.GetComponentByNameParams) middleware.Responder {
-		return handlers.GetComponentTrainVersions(params, db)
+		return handlers.GetComponentTrainVersions(params, requestStore(store, params.HTTPRequest), signer)
 	})
 	api.GetComponentByReleaseHandler = operations.GetComponentByReleaseHandlerFunc(func(params operations.GetComponentByReleaseParams) middleware.Responder {
-		return handlers.GetVersion(params, db)
+		return handlers.GetVersion(params, requestStore(store, params.HTTPRequest), signer)
 	})
 	api.GetComponentsByLatestReleaseHandler = operations.GetComponentsByLatestReleaseHandlerFunc(func(params operations.GetComponentsByLatestReleaseParams) middleware.Responder {
-		return handlers.GetLatestVersions(params, db)
+		return handlers.GetLatestVersions(params, requestStore(store, params.HTTPRequest), signer)
 	})
 	api.GetComponentsByLatestReleaseForV2Handler = operations.GetComponentsByLatestReleaseForV2HandlerFunc(func(params operations.GetComponentsByLatestReleaseForV2Params) middleware.Responder {
-		return handlers.GetLatestVersionsForV2(params, db)
+		return handlers.GetLatestVersionsForV2(params, requestStore(store, params.HTTPRequest), signer)
+	})
+	api.GetReleaseAdoptionHandler = operations.GetReleaseAdoptionHandlerFunc(func(params operations.GetReleaseAdoptionParams) middleware.Responder {
+		return handlers.ReleaseAdoption(params, requestStore(store, params.HTTPRequest))
 	})
 	api.GetDoctorInfoHandler = operations.GetDoctorInfoHandlerFunc(func(params operations.GetDoctorInfoParams, principal interface{}) middleware.Responder {
-		return handlers.GetDoctor(params, db)
+		return handlers.GetDoctor(params, requestStore(store, params.HTTPRequest))
+	})
+	api.PublishComponentReleaseHandler = operations.PublishComponentReleaseHandlerFunc(func(params operations.PublishComponentReleaseParams, principal interface{}) middleware.Responder {
+		return handlers.PublishVersion(params, principal, requestStore(store, params.HTTPRequest), signer)
 	})
-	api.PublishComponentReleaseHandler = operations.PublishComponentReleaseHandlerFunc(func(params operations.PublishComponentReleaseParams) middleware.Responder {
-		return handlers.PublishVersion(params, db)
+	api.DeprecateComponentReleaseHandler = operations.DeprecateComponentReleaseHandlerFunc(func(params operations.DeprecateComponentReleaseParams, principal interface{}) middleware.Responder {
+		return handlers.DeprecateVersion(params, principal, requestStore(store, params.HTTPRequest), signer)
+	})
+	api.GetSigningKeyHandler = operations.GetSigningKeyHandlerFunc(func() middleware.Responder {
+		return handlers.GetSigningKey(signer)
 	})
 	api.PublishDoctorInfoHandler = operations.PublishDoctorInfoHandlerFunc(func(params operations.PublishDoctorInfoParams) middleware.Responder {
-		return handlers.PublishDoctor(params, db)
+		return handlers.PublishDoctor(params, requestStore(store, params.HTTPRequest))
 	})
 	api.PingHandler = operations.PingHandlerFunc(func() middleware.Responder {
 		return handlers.Ping()
 	})
+	api.CheckLivenessHandler = operations.CheckLivenessHandlerFunc(func() middleware.Responder {
+		return handlers.Liveness()
+	})
+	api.CheckReadinessHandler = operations.CheckReadinessHandlerFunc(func() middleware.Responder {
+		return handlers.Readiness(store, readinessCheckTimeout)
+	})
 
 	api.ServerShutdown = func() {}
 
-	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
+	matcher, err := metrics.NewOperationMatcher(SwaggerJSON)
+	if err != nil {
+		log.Fatalf("unable to read operations from the swagger spec (%s)", err)
+	}
+	httpMetrics := metrics.NewHTTPMetrics(registry, metricsNamespace)
+	instrumented := httpMetrics.Instrument(api.Serve(setupMiddlewares), matcher.Operation)
+	return setupGlobalMiddleware(logging.LogRequests(instrumented, matcher.Operation, os.Stdout), metrics.Handler(registry))
 }
 
 // The middleware configuration is for the handler executors. These do not apply to the swagger.json document.
@@ -124,6 +381,14 @@ func setupMiddlewares(handler http.Handler) http.Handler {
 
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
 // So this is a good place to plug in a panic handling middleware, logging and metrics
-func setupGlobalMiddleware(handler http.Handler) http.Handler {
-	return handler
+// Prometheus metrics are served from metricsHandler on metricsPath, and requests for them aren't
+// counted in the HTTP metrics
+func setupGlobalMiddleware(handler http.Handler, metricsHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == metricsPath {
+			metricsHandler.ServeHTTP(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,52 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// CheckLivenessHandlerFunc turns a function with the right signature into a check liveness handler
+type CheckLivenessHandlerFunc func() middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn CheckLivenessHandlerFunc) Handle() middleware.Responder {
+	return fn()
+}
+
+// CheckLivenessHandler interface for that can handle valid check liveness params
+type CheckLivenessHandler interface {
+	Handle() middleware.Responder
+}
+
+// NewCheckLiveness creates a new http.Handler for the check liveness operation
+func NewCheckLiveness(ctx *middleware.Context, handler CheckLivenessHandler) *CheckLiveness {
+	return &CheckLiveness{Context: ctx, Handler: handler}
+}
+
+/*CheckLiveness swagger:route GET /healthz checkLiveness
+
+check that the API server process is alive. it doesn't check the database
+
+*/
+type CheckLiveness struct {
+	Context *middleware.Context
+	Handler CheckLivenessHandler
+}
+
+func (o *CheckLiveness) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+
+	if err := o.Context.BindValidRequest(r, route, nil); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle() // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*CheckLivenessOK the server is alive
+
+swagger:response checkLivenessOK
+*/
+type CheckLivenessOK struct {
+
+	// In: body
+	Payload *models.HealthStatus `json:"body,omitempty"`
+}
+
+// NewCheckLivenessOK creates CheckLivenessOK with default headers values
+func NewCheckLivenessOK() *CheckLivenessOK {
+	return &CheckLivenessOK{}
+}
+
+// WithPayload adds the payload to the check liveness o k response
+func (o *CheckLivenessOK) WithPayload(payload *models.HealthStatus) *CheckLivenessOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the check liveness o k response
+func (o *CheckLivenessOK) SetPayload(payload *models.HealthStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CheckLivenessOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*CheckLivenessDefault unexpected error
+
+swagger:response checkLivenessDefault
+*/
+type CheckLivenessDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCheckLivenessDefault creates CheckLivenessDefault with default headers values
+func NewCheckLivenessDefault(code int) *CheckLivenessDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &CheckLivenessDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the check liveness default response
+func (o *CheckLivenessDefault) WithStatusCode(code int) *CheckLivenessDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the check liveness default response
+func (o *CheckLivenessDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the check liveness default response
+func (o *CheckLivenessDefault) WithPayload(payload *models.Error) *CheckLivenessDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the check liveness default response
+func (o *CheckLivenessDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CheckLivenessDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// CheckReadinessHandlerFunc turns a function with the right signature into a check readiness handler
+type CheckReadinessHandlerFunc func() middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn CheckReadinessHandlerFunc) Handle() middleware.Responder {
+	return fn()
+}
+
+// CheckReadinessHandler interface for that can handle valid check readiness params
+type CheckReadinessHandler interface {
+	Handle() middleware.Responder
+}
+
+// NewCheckReadiness creates a new http.Handler for the check readiness operation
+func NewCheckReadiness(ctx *middleware.Context, handler CheckReadinessHandler) *CheckReadiness {
+	return &CheckReadiness{Context: ctx, Handler: handler}
+}
+
+/*CheckReadiness swagger:route GET /readyz checkReadiness
+
+check that the API server can serve requests. the database must be reachable and its schema must be up to date
+
+*/
+type CheckReadiness struct {
+	Context *middleware.Context
+	Handler CheckReadinessHandler
+}
+
+func (o *CheckReadiness) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+
+	if err := o.Context.BindValidRequest(r, route, nil); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle() // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,141 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*CheckReadinessOK every check passed
+
+swagger:response checkReadinessOK
+*/
+type CheckReadinessOK struct {
+
+	// In: body
+	Payload *models.HealthStatus `json:"body,omitempty"`
+}
+
+// NewCheckReadinessOK creates CheckReadinessOK with default headers values
+func NewCheckReadinessOK() *CheckReadinessOK {
+	return &CheckReadinessOK{}
+}
+
+// WithPayload adds the payload to the check readiness o k response
+func (o *CheckReadinessOK) WithPayload(payload *models.HealthStatus) *CheckReadinessOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the check readiness o k response
+func (o *CheckReadinessOK) SetPayload(payload *models.HealthStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CheckReadinessOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*CheckReadinessServiceUnavailable at least one check failed
+
+swagger:response checkReadinessServiceUnavailable
+*/
+type CheckReadinessServiceUnavailable struct {
+
+	// In: body
+	Payload *models.HealthStatus `json:"body,omitempty"`
+}
+
+// NewCheckReadinessServiceUnavailable creates CheckReadinessServiceUnavailable with default headers values
+func NewCheckReadinessServiceUnavailable() *CheckReadinessServiceUnavailable {
+	return &CheckReadinessServiceUnavailable{}
+}
+
+// WithPayload adds the payload to the check readiness service unavailable response
+func (o *CheckReadinessServiceUnavailable) WithPayload(payload *models.HealthStatus) *CheckReadinessServiceUnavailable {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the check readiness service unavailable response
+func (o *CheckReadinessServiceUnavailable) SetPayload(payload *models.HealthStatus) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CheckReadinessServiceUnavailable) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(503)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*CheckReadinessDefault unexpected error
+
+swagger:response checkReadinessDefault
+*/
+type CheckReadinessDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCheckReadinessDefault creates CheckReadinessDefault with default headers values
+func NewCheckReadinessDefault(code int) *CheckReadinessDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &CheckReadinessDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the check readiness default response
+func (o *CheckReadinessDefault) WithStatusCode(code int) *CheckReadinessDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the check readiness default response
+func (o *CheckReadinessDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the check readiness default response
+func (o *CheckReadinessDefault) WithPayload(payload *models.Error) *CheckReadinessDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the check readiness default response
+func (o *CheckReadinessDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CheckReadinessDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -26,6 +26,10 @@ func NewCreateClusterDetailsForV2Params() CreateClusterDetailsForV2Params {
 //
 // swagger:parameters createClusterDetailsForV2
 type CreateClusterDetailsForV2Params struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -41,6 +45,7 @@ type CreateClusterDetailsForV2Params struct {
 // for simple values it will use straight method calls
 func (o *CreateClusterDetailsForV2Params) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body models.Cluster
@@ -4,6 +4,7 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-swagger/go-swagger/httpkit"
@@ -48,6 +49,97 @@ func (o *CreateClusterDetailsForV2OK) WriteResponse(rw http.ResponseWriter, prod
 	}
 }
 
+/*CreateClusterDetailsForV2UnprocessableEntity the cluster failed validation
+
+swagger:response createClusterDetailsForV2UnprocessableEntity
+*/
+type CreateClusterDetailsForV2UnprocessableEntity struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCreateClusterDetailsForV2UnprocessableEntity creates CreateClusterDetailsForV2UnprocessableEntity with default headers values
+func NewCreateClusterDetailsForV2UnprocessableEntity() *CreateClusterDetailsForV2UnprocessableEntity {
+	return &CreateClusterDetailsForV2UnprocessableEntity{}
+}
+
+// WithPayload adds the payload to the create cluster details for v2 unprocessable entity response
+func (o *CreateClusterDetailsForV2UnprocessableEntity) WithPayload(payload *models.Error) *CreateClusterDetailsForV2UnprocessableEntity {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the create cluster details for v2 unprocessable entity response
+func (o *CreateClusterDetailsForV2UnprocessableEntity) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CreateClusterDetailsForV2UnprocessableEntity) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(422)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*CreateClusterDetailsForV2TooManyRequests the cluster or its source IP has checked in too often
+
+swagger:response createClusterDetailsForV2TooManyRequests
+*/
+type CreateClusterDetailsForV2TooManyRequests struct {
+	/*the number of seconds to wait before checking in again
+	 */
+	RetryAfter int64 `json:"Retry-After"`
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCreateClusterDetailsForV2TooManyRequests creates CreateClusterDetailsForV2TooManyRequests with default headers values
+func NewCreateClusterDetailsForV2TooManyRequests() *CreateClusterDetailsForV2TooManyRequests {
+	return &CreateClusterDetailsForV2TooManyRequests{}
+}
+
+// WithRetryAfter adds the retryAfter to the create cluster details for v2 too many requests response
+func (o *CreateClusterDetailsForV2TooManyRequests) WithRetryAfter(retryAfter int64) *CreateClusterDetailsForV2TooManyRequests {
+	o.RetryAfter = retryAfter
+	return o
+}
+
+// SetRetryAfter sets the retryAfter to the create cluster details for v2 too many requests response
+func (o *CreateClusterDetailsForV2TooManyRequests) SetRetryAfter(retryAfter int64) {
+	o.RetryAfter = retryAfter
+}
+
+// WithPayload adds the payload to the create cluster details for v2 too many requests response
+func (o *CreateClusterDetailsForV2TooManyRequests) WithPayload(payload *models.Error) *CreateClusterDetailsForV2TooManyRequests {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the create cluster details for v2 too many requests response
+func (o *CreateClusterDetailsForV2TooManyRequests) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CreateClusterDetailsForV2TooManyRequests) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	// response header Retry-After
+	rw.Header().Set("Retry-After", fmt.Sprintf("%v", o.RetryAfter))
+
+	rw.WriteHeader(429)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
 /*CreateClusterDetailsForV2Default unexpected error
 
 swagger:response createClusterDetailsForV2Default
@@ -24,6 +24,10 @@ func NewCreateClusterDetailsParams() CreateClusterDetailsParams {
 //
 // swagger:parameters createClusterDetails
 type CreateClusterDetailsParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -34,6 +38,7 @@ type CreateClusterDetailsParams struct {
 // for simple values it will use straight method calls
 func (o *CreateClusterDetailsParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body models.Cluster
@@ -4,6 +4,7 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-swagger/go-swagger/httpkit"
@@ -48,6 +49,97 @@ func (o *CreateClusterDetailsOK) WriteResponse(rw http.ResponseWriter, producer
 	}
 }
 
+/*CreateClusterDetailsUnprocessableEntity the cluster failed validation
+
+swagger:response createClusterDetailsUnprocessableEntity
+*/
+type CreateClusterDetailsUnprocessableEntity struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCreateClusterDetailsUnprocessableEntity creates CreateClusterDetailsUnprocessableEntity with default headers values
+func NewCreateClusterDetailsUnprocessableEntity() *CreateClusterDetailsUnprocessableEntity {
+	return &CreateClusterDetailsUnprocessableEntity{}
+}
+
+// WithPayload adds the payload to the create cluster details unprocessable entity response
+func (o *CreateClusterDetailsUnprocessableEntity) WithPayload(payload *models.Error) *CreateClusterDetailsUnprocessableEntity {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the create cluster details unprocessable entity response
+func (o *CreateClusterDetailsUnprocessableEntity) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CreateClusterDetailsUnprocessableEntity) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(422)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*CreateClusterDetailsTooManyRequests the cluster or its source IP has checked in too often
+
+swagger:response createClusterDetailsTooManyRequests
+*/
+type CreateClusterDetailsTooManyRequests struct {
+	/*the number of seconds to wait before checking in again
+	 */
+	RetryAfter int64 `json:"Retry-After"`
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewCreateClusterDetailsTooManyRequests creates CreateClusterDetailsTooManyRequests with default headers values
+func NewCreateClusterDetailsTooManyRequests() *CreateClusterDetailsTooManyRequests {
+	return &CreateClusterDetailsTooManyRequests{}
+}
+
+// WithRetryAfter adds the retryAfter to the create cluster details too many requests response
+func (o *CreateClusterDetailsTooManyRequests) WithRetryAfter(retryAfter int64) *CreateClusterDetailsTooManyRequests {
+	o.RetryAfter = retryAfter
+	return o
+}
+
+// SetRetryAfter sets the retryAfter to the create cluster details too many requests response
+func (o *CreateClusterDetailsTooManyRequests) SetRetryAfter(retryAfter int64) {
+	o.RetryAfter = retryAfter
+}
+
+// WithPayload adds the payload to the create cluster details too many requests response
+func (o *CreateClusterDetailsTooManyRequests) WithPayload(payload *models.Error) *CreateClusterDetailsTooManyRequests {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the create cluster details too many requests response
+func (o *CreateClusterDetailsTooManyRequests) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *CreateClusterDetailsTooManyRequests) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	// response header Retry-After
+	rw.Header().Set("Retry-After", fmt.Sprintf("%v", o.RetryAfter))
+
+	rw.WriteHeader(429)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
 /*CreateClusterDetailsDefault unexpected error
 
 swagger:response createClusterDetailsDefault
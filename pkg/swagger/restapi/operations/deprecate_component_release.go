@@ -0,0 +1,63 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// DeprecateComponentReleaseHandlerFunc turns a function with the right signature into a deprecate component release handler
+type DeprecateComponentReleaseHandlerFunc func(DeprecateComponentReleaseParams, interface{}) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn DeprecateComponentReleaseHandlerFunc) Handle(params DeprecateComponentReleaseParams, principal interface{}) middleware.Responder {
+	return fn(params, principal)
+}
+
+// DeprecateComponentReleaseHandler interface for that can handle valid deprecate component release params
+type DeprecateComponentReleaseHandler interface {
+	Handle(DeprecateComponentReleaseParams, interface{}) middleware.Responder
+}
+
+// NewDeprecateComponentRelease creates a new http.Handler for the deprecate component release operation
+func NewDeprecateComponentRelease(ctx *middleware.Context, handler DeprecateComponentReleaseHandler) *DeprecateComponentRelease {
+	return &DeprecateComponentRelease{Context: ctx, Handler: handler}
+}
+
+/*DeprecateComponentRelease swagger:route PATCH /v3/versions/{train}/{component}/{release} deprecateComponentRelease
+
+deprecate or yank a release of the component, or make it active again
+
+*/
+type DeprecateComponentRelease struct {
+	Context *middleware.Context
+	Handler DeprecateComponentReleaseHandler
+}
+
+func (o *DeprecateComponentRelease) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewDeprecateComponentReleaseParams()
+
+	uprinc, err := o.Context.Authorize(r, route)
+	if err != nil {
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+	var principal interface{}
+	if uprinc != nil {
+		principal = uprinc
+	}
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params, principal) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,141 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*DeprecateComponentReleaseOK deprecate component release response
+
+swagger:response deprecateComponentReleaseOK
+*/
+type DeprecateComponentReleaseOK struct {
+
+	// In: body
+	Payload *models.ComponentVersion `json:"body,omitempty"`
+}
+
+// NewDeprecateComponentReleaseOK creates DeprecateComponentReleaseOK with default headers values
+func NewDeprecateComponentReleaseOK() *DeprecateComponentReleaseOK {
+	return &DeprecateComponentReleaseOK{}
+}
+
+// WithPayload adds the payload to the deprecate component release o k response
+func (o *DeprecateComponentReleaseOK) WithPayload(payload *models.ComponentVersion) *DeprecateComponentReleaseOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the deprecate component release o k response
+func (o *DeprecateComponentReleaseOK) SetPayload(payload *models.ComponentVersion) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeprecateComponentReleaseOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeprecateComponentReleaseUnprocessableEntity the deprecation failed validation
+
+swagger:response deprecateComponentReleaseUnprocessableEntity
+*/
+type DeprecateComponentReleaseUnprocessableEntity struct {
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeprecateComponentReleaseUnprocessableEntity creates DeprecateComponentReleaseUnprocessableEntity with default headers values
+func NewDeprecateComponentReleaseUnprocessableEntity() *DeprecateComponentReleaseUnprocessableEntity {
+	return &DeprecateComponentReleaseUnprocessableEntity{}
+}
+
+// WithPayload adds the payload to the deprecate component release unprocessable entity response
+func (o *DeprecateComponentReleaseUnprocessableEntity) WithPayload(payload *models.Error) *DeprecateComponentReleaseUnprocessableEntity {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the deprecate component release unprocessable entity response
+func (o *DeprecateComponentReleaseUnprocessableEntity) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeprecateComponentReleaseUnprocessableEntity) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(422)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*DeprecateComponentReleaseDefault unexpected error
+
+swagger:response deprecateComponentReleaseDefault
+*/
+type DeprecateComponentReleaseDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewDeprecateComponentReleaseDefault creates DeprecateComponentReleaseDefault with default headers values
+func NewDeprecateComponentReleaseDefault(code int) *DeprecateComponentReleaseDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &DeprecateComponentReleaseDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the deprecate component release default response
+func (o *DeprecateComponentReleaseDefault) WithStatusCode(code int) *DeprecateComponentReleaseDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the deprecate component release default response
+func (o *DeprecateComponentReleaseDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the deprecate component release default response
+func (o *DeprecateComponentReleaseDefault) WithPayload(payload *models.Error) *DeprecateComponentReleaseDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the deprecate component release default response
+func (o *DeprecateComponentReleaseDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *DeprecateComponentReleaseDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetActiveClustersHandlerFunc turns a function with the right signature into a get active clusters handler
+type GetActiveClustersHandlerFunc func(GetActiveClustersParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetActiveClustersHandlerFunc) Handle(params GetActiveClustersParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetActiveClustersHandler interface for that can handle valid get active clusters params
+type GetActiveClustersHandler interface {
+	Handle(GetActiveClustersParams) middleware.Responder
+}
+
+// NewGetActiveClusters creates a new http.Handler for the get active clusters operation
+func NewGetActiveClusters(ctx *middleware.Context, handler GetActiveClustersHandler) *GetActiveClusters {
+	return &GetActiveClusters{Context: ctx, Handler: handler}
+}
+
+/*GetActiveClusters swagger:route GET /v3/clusters/active getActiveClusters
+
+count the clusters that checked in during each day, week or month
+
+*/
+type GetActiveClusters struct {
+	Context *middleware.Context
+	Handler GetActiveClustersHandler
+}
+
+func (o *GetActiveClusters) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetActiveClustersParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,150 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+)
+
+// NewGetActiveClustersParams creates a new GetActiveClustersParams object
+// with the default values initialized.
+func NewGetActiveClustersParams() GetActiveClustersParams {
+	var (
+		intervalDefault string = string("day")
+	)
+	return GetActiveClustersParams{
+		Interval: &intervalDefault,
+	}
+}
+
+// GetActiveClustersParams contains all the bound params for the get active clusters operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getActiveClusters
+type GetActiveClustersParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*
+	  the size of each bucket. weeks start on Monday
+	  In: query
+	  Default: "day"
+	*/
+	Interval *string
+	/*
+	  count checkins at or after this time
+	  Required: true
+	  In: query
+	*/
+	Since strfmt.DateTime
+	/*
+	  count checkins before this time. defaults to now
+	  In: query
+	*/
+	Until *strfmt.DateTime
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *GetActiveClustersParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+	qs := httpkit.Values(r.URL.Query())
+
+	qInterval, qhkInterval, _ := qs.GetOK("interval")
+	if err := o.bindInterval(qInterval, qhkInterval, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qSince, qhkSince, _ := qs.GetOK("since")
+	if err := o.bindSince(qSince, qhkSince, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qUntil, qhkUntil, _ := qs.GetOK("until")
+	if err := o.bindUntil(qUntil, qhkUntil, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetActiveClustersParams) bindInterval(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		var intervalDefault string = string("day")
+		o.Interval = &intervalDefault
+		return nil
+	}
+
+	o.Interval = &raw
+
+	if err := o.validateInterval(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetActiveClustersParams) validateInterval(formats strfmt.Registry) error {
+
+	if err := validate.Enum("interval", "query", *o.Interval, []interface{}{"day", "week", "month"}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetActiveClustersParams) bindSince(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	if !hasKey {
+		return errors.Required("since", "query")
+	}
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if err := validate.RequiredString("since", "query", raw); err != nil {
+		return err
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("since", "query", "strfmt.DateTime", raw)
+	}
+	o.Since = *(value.(*strfmt.DateTime))
+
+	return nil
+}
+
+func (o *GetActiveClustersParams) bindUntil(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("until", "query", "strfmt.DateTime", raw)
+	}
+	o.Until = (value.(*strfmt.DateTime))
+
+	return nil
+}
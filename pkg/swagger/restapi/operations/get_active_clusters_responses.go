@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetActiveClustersOK active clusters response
+
+swagger:response getActiveClustersOK
+*/
+type GetActiveClustersOK struct {
+
+	// In: body
+	Payload *models.ActiveClusters `json:"body,omitempty"`
+}
+
+// NewGetActiveClustersOK creates GetActiveClustersOK with default headers values
+func NewGetActiveClustersOK() *GetActiveClustersOK {
+	return &GetActiveClustersOK{}
+}
+
+// WithPayload adds the payload to the get active clusters o k response
+func (o *GetActiveClustersOK) WithPayload(payload *models.ActiveClusters) *GetActiveClustersOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get active clusters o k response
+func (o *GetActiveClustersOK) SetPayload(payload *models.ActiveClusters) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetActiveClustersOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetActiveClustersDefault unexpected error
+
+swagger:response getActiveClustersDefault
+*/
+type GetActiveClustersDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetActiveClustersDefault creates GetActiveClustersDefault with default headers values
+func NewGetActiveClustersDefault(code int) *GetActiveClustersDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetActiveClustersDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get active clusters default response
+func (o *GetActiveClustersDefault) WithStatusCode(code int) *GetActiveClustersDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get active clusters default response
+func (o *GetActiveClustersDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get active clusters default response
+func (o *GetActiveClustersDefault) WithPayload(payload *models.Error) *GetActiveClustersDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get active clusters default response
+func (o *GetActiveClustersDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetActiveClustersDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -24,6 +24,10 @@ func NewGetClusterByIDParams() GetClusterByIDParams {
 //
 // swagger:parameters getClusterById
 type GetClusterByIDParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  Required: true
 	  In: path
@@ -35,6 +39,7 @@ type GetClusterByIDParams struct {
 // for simple values it will use straight method calls
 func (o *GetClusterByIDParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	rID, rhkID, _ := route.Params.GetOK("id")
 	if err := o.bindID(rID, rhkID, route.Formats); err != nil {
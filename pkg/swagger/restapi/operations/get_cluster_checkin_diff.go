@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetClusterCheckinDiffHandlerFunc turns a function with the right signature into a get cluster checkin diff handler
+type GetClusterCheckinDiffHandlerFunc func(GetClusterCheckinDiffParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetClusterCheckinDiffHandlerFunc) Handle(params GetClusterCheckinDiffParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetClusterCheckinDiffHandler interface for that can handle valid get cluster checkin diff params
+type GetClusterCheckinDiffHandler interface {
+	Handle(GetClusterCheckinDiffParams) middleware.Responder
+}
+
+// NewGetClusterCheckinDiff creates a new http.Handler for the get cluster checkin diff operation
+func NewGetClusterCheckinDiff(ctx *middleware.Context, handler GetClusterCheckinDiffHandler) *GetClusterCheckinDiff {
+	return &GetClusterCheckinDiff{Context: ctx, Handler: handler}
+}
+
+/*GetClusterCheckinDiff swagger:route GET /v3/clusters/{id}/checkins/diff getClusterCheckinDiff
+
+compare the components of two checkins of a cluster
+
+*/
+type GetClusterCheckinDiff struct {
+	Context *middleware.Context
+	Handler GetClusterCheckinDiffHandler
+}
+
+func (o *GetClusterCheckinDiff) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetClusterCheckinDiffParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
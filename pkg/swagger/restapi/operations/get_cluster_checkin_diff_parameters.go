@@ -0,0 +1,170 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+)
+
+// NewGetClusterCheckinDiffParams creates a new GetClusterCheckinDiffParams object
+// with the default values initialized.
+func NewGetClusterCheckinDiffParams() GetClusterCheckinDiffParams {
+	var ()
+	return GetClusterCheckinDiffParams{}
+}
+
+// GetClusterCheckinDiffParams contains all the bound params for the get cluster checkin diff operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getClusterCheckinDiff
+type GetClusterCheckinDiffParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*
+	  compare from the latest checkin at or before this time
+	  In: query
+	*/
+	From *strfmt.DateTime
+	/*
+	  compare from the checkin with this ID
+	  In: query
+	*/
+	FromCheckin *string
+	/*
+	  Required: true
+	  In: path
+	*/
+	ID string
+	/*
+	  compare to the latest checkin at or before this time
+	  In: query
+	*/
+	To *strfmt.DateTime
+	/*
+	  compare to the checkin with this ID
+	  In: query
+	*/
+	ToCheckin *string
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *GetClusterCheckinDiffParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+	qs := httpkit.Values(r.URL.Query())
+
+	qFrom, qhkFrom, _ := qs.GetOK("from")
+	if err := o.bindFrom(qFrom, qhkFrom, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qFromCheckin, qhkFromCheckin, _ := qs.GetOK("from_checkin")
+	if err := o.bindFromCheckin(qFromCheckin, qhkFromCheckin, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rID, rhkID, _ := route.Params.GetOK("id")
+	if err := o.bindID(rID, rhkID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qTo, qhkTo, _ := qs.GetOK("to")
+	if err := o.bindTo(qTo, qhkTo, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qToCheckin, qhkToCheckin, _ := qs.GetOK("to_checkin")
+	if err := o.bindToCheckin(qToCheckin, qhkToCheckin, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetClusterCheckinDiffParams) bindFrom(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("from", "query", "strfmt.DateTime", raw)
+	}
+	o.From = (value.(*strfmt.DateTime))
+
+	return nil
+}
+
+func (o *GetClusterCheckinDiffParams) bindFromCheckin(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.FromCheckin = &raw
+
+	return nil
+}
+
+func (o *GetClusterCheckinDiffParams) bindID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.ID = raw
+
+	return nil
+}
+
+func (o *GetClusterCheckinDiffParams) bindTo(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("to", "query", "strfmt.DateTime", raw)
+	}
+	o.To = (value.(*strfmt.DateTime))
+
+	return nil
+}
+
+func (o *GetClusterCheckinDiffParams) bindToCheckin(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.ToCheckin = &raw
+
+	return nil
+}
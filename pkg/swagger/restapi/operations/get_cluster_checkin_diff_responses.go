@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetClusterCheckinDiffOK cluster checkin diff response
+
+swagger:response getClusterCheckinDiffOK
+*/
+type GetClusterCheckinDiffOK struct {
+
+	// In: body
+	Payload *models.ClusterCheckinDiff `json:"body,omitempty"`
+}
+
+// NewGetClusterCheckinDiffOK creates GetClusterCheckinDiffOK with default headers values
+func NewGetClusterCheckinDiffOK() *GetClusterCheckinDiffOK {
+	return &GetClusterCheckinDiffOK{}
+}
+
+// WithPayload adds the payload to the get cluster checkin diff o k response
+func (o *GetClusterCheckinDiffOK) WithPayload(payload *models.ClusterCheckinDiff) *GetClusterCheckinDiffOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster checkin diff o k response
+func (o *GetClusterCheckinDiffOK) SetPayload(payload *models.ClusterCheckinDiff) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCheckinDiffOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetClusterCheckinDiffDefault unexpected error
+
+swagger:response getClusterCheckinDiffDefault
+*/
+type GetClusterCheckinDiffDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetClusterCheckinDiffDefault creates GetClusterCheckinDiffDefault with default headers values
+func NewGetClusterCheckinDiffDefault(code int) *GetClusterCheckinDiffDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetClusterCheckinDiffDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get cluster checkin diff default response
+func (o *GetClusterCheckinDiffDefault) WithStatusCode(code int) *GetClusterCheckinDiffDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get cluster checkin diff default response
+func (o *GetClusterCheckinDiffDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get cluster checkin diff default response
+func (o *GetClusterCheckinDiffDefault) WithPayload(payload *models.Error) *GetClusterCheckinDiffDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster checkin diff default response
+func (o *GetClusterCheckinDiffDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCheckinDiffDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
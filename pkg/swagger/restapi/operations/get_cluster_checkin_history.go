@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetClusterCheckinHistoryHandlerFunc turns a function with the right signature into a get cluster checkin history handler
+type GetClusterCheckinHistoryHandlerFunc func(GetClusterCheckinHistoryParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetClusterCheckinHistoryHandlerFunc) Handle(params GetClusterCheckinHistoryParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetClusterCheckinHistoryHandler interface for that can handle valid get cluster checkin history params
+type GetClusterCheckinHistoryHandler interface {
+	Handle(GetClusterCheckinHistoryParams) middleware.Responder
+}
+
+// NewGetClusterCheckinHistory creates a new http.Handler for the get cluster checkin history operation
+func NewGetClusterCheckinHistory(ctx *middleware.Context, handler GetClusterCheckinHistoryHandler) *GetClusterCheckinHistory {
+	return &GetClusterCheckinHistory{Context: ctx, Handler: handler}
+}
+
+/*GetClusterCheckinHistory swagger:route GET /v3/clusters/{id}/checkins getClusterCheckinHistory
+
+list the checkins of a cluster, oldest first
+
+*/
+type GetClusterCheckinHistory struct {
+	Context *middleware.Context
+	Handler GetClusterCheckinHistoryHandler
+}
+
+func (o *GetClusterCheckinHistory) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetClusterCheckinHistoryParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
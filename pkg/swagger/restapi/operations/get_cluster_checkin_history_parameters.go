@@ -0,0 +1,202 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+	"github.com/go-swagger/go-swagger/swag"
+
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+)
+
+// NewGetClusterCheckinHistoryParams creates a new GetClusterCheckinHistoryParams object
+// with the default values initialized.
+func NewGetClusterCheckinHistoryParams() GetClusterCheckinHistoryParams {
+	var (
+		limitDefault int64 = int64(100)
+	)
+	return GetClusterCheckinHistoryParams{
+		Limit: &limitDefault,
+	}
+}
+
+// GetClusterCheckinHistoryParams contains all the bound params for the get cluster checkin history operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getClusterCheckinHistory
+type GetClusterCheckinHistoryParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*
+	  the nextCursor value from the previous page
+	  In: query
+	*/
+	Cursor *string
+	/*
+	  Required: true
+	  In: path
+	*/
+	ID string
+	/*
+	  the maximum number of checkins to return
+	  Maximum: 1000
+	  Minimum: 1
+	  In: query
+	  Default: 100
+	*/
+	Limit *int64
+	/*
+	  only return checkins at or after this time
+	  In: query
+	*/
+	Since *strfmt.DateTime
+	/*
+	  only return checkins before this time
+	  In: query
+	*/
+	Until *strfmt.DateTime
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *GetClusterCheckinHistoryParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+	qs := httpkit.Values(r.URL.Query())
+
+	qCursor, qhkCursor, _ := qs.GetOK("cursor")
+	if err := o.bindCursor(qCursor, qhkCursor, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rID, rhkID, _ := route.Params.GetOK("id")
+	if err := o.bindID(rID, rhkID, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qSince, qhkSince, _ := qs.GetOK("since")
+	if err := o.bindSince(qSince, qhkSince, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qUntil, qhkUntil, _ := qs.GetOK("until")
+	if err := o.bindUntil(qUntil, qhkUntil, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) bindCursor(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Cursor = &raw
+
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) bindID(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.ID = raw
+
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		var limitDefault int64 = int64(100)
+		o.Limit = &limitDefault
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("limit", "query", int64(*o.Limit), 1000, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) bindSince(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("since", "query", "strfmt.DateTime", raw)
+	}
+	o.Since = (value.(*strfmt.DateTime))
+
+	return nil
+}
+
+func (o *GetClusterCheckinHistoryParams) bindUntil(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("until", "query", "strfmt.DateTime", raw)
+	}
+	o.Until = (value.(*strfmt.DateTime))
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetClusterCheckinHistoryOK cluster checkin history response
+
+swagger:response getClusterCheckinHistoryOK
+*/
+type GetClusterCheckinHistoryOK struct {
+
+	// In: body
+	Payload *models.ClusterCheckinHistory `json:"body,omitempty"`
+}
+
+// NewGetClusterCheckinHistoryOK creates GetClusterCheckinHistoryOK with default headers values
+func NewGetClusterCheckinHistoryOK() *GetClusterCheckinHistoryOK {
+	return &GetClusterCheckinHistoryOK{}
+}
+
+// WithPayload adds the payload to the get cluster checkin history o k response
+func (o *GetClusterCheckinHistoryOK) WithPayload(payload *models.ClusterCheckinHistory) *GetClusterCheckinHistoryOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster checkin history o k response
+func (o *GetClusterCheckinHistoryOK) SetPayload(payload *models.ClusterCheckinHistory) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCheckinHistoryOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetClusterCheckinHistoryDefault unexpected error
+
+swagger:response getClusterCheckinHistoryDefault
+*/
+type GetClusterCheckinHistoryDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetClusterCheckinHistoryDefault creates GetClusterCheckinHistoryDefault with default headers values
+func NewGetClusterCheckinHistoryDefault(code int) *GetClusterCheckinHistoryDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetClusterCheckinHistoryDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get cluster checkin history default response
+func (o *GetClusterCheckinHistoryDefault) WithStatusCode(code int) *GetClusterCheckinHistoryDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get cluster checkin history default response
+func (o *GetClusterCheckinHistoryDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get cluster checkin history default response
+func (o *GetClusterCheckinHistoryDefault) WithPayload(payload *models.Error) *GetClusterCheckinHistoryDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster checkin history default response
+func (o *GetClusterCheckinHistoryDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCheckinHistoryDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
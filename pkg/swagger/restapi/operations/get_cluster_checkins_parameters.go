@@ -9,6 +9,8 @@ import (
 	"github.com/go-swagger/go-swagger/errors"
 	"github.com/go-swagger/go-swagger/httpkit"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+	"github.com/go-swagger/go-swagger/swag"
 
 	strfmt "github.com/go-swagger/go-swagger/strfmt"
 )
@@ -16,8 +18,12 @@ import (
 // NewGetClusterCheckinsParams creates a new GetClusterCheckinsParams object
 // with the default values initialized.
 func NewGetClusterCheckinsParams() GetClusterCheckinsParams {
-	var ()
-	return GetClusterCheckinsParams{}
+	var (
+		limitDefault int64 = int64(100)
+	)
+	return GetClusterCheckinsParams{
+		Limit: &limitDefault,
+	}
 }
 
 // GetClusterCheckinsParams contains all the bound params for the get cluster checkins operation
@@ -25,6 +31,10 @@ func NewGetClusterCheckinsParams() GetClusterCheckinsParams {
 //
 // swagger:parameters getClusterCheckins
 type GetClusterCheckinsParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: query
 	*/
@@ -33,12 +43,26 @@ type GetClusterCheckinsParams struct {
 	  In: query
 	*/
 	CreatedBefore *strfmt.DateTime
+	/*
+	  the nextCursor value from the previous page
+	  In: query
+	*/
+	Cursor *string
+	/*
+	  the maximum number of clusters to return. CSV and NDJSON responses ignore it, and return every cluster after the cursor
+	  Maximum: 1000
+	  Minimum: 1
+	  In: query
+	  Default: 100
+	*/
+	Limit *int64
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
 // for simple values it will use straight method calls
 func (o *GetClusterCheckinsParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 	qs := httpkit.Values(r.URL.Query())
 
 	qCreatedAfter, qhkCreatedAfter, _ := qs.GetOK("created_after")
@@ -51,6 +75,16 @@ func (o *GetClusterCheckinsParams) BindRequest(r *http.Request, route *middlewar
 		res = append(res, err)
 	}
 
+	qCursor, qhkCursor, _ := qs.GetOK("cursor")
+	if err := o.bindCursor(qCursor, qhkCursor, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -92,3 +126,54 @@ func (o *GetClusterCheckinsParams) bindCreatedBefore(rawData []string, hasKey bo
 
 	return nil
 }
+
+func (o *GetClusterCheckinsParams) bindCursor(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Cursor = &raw
+
+	return nil
+}
+
+func (o *GetClusterCheckinsParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		var limitDefault int64 = int64(100)
+		o.Limit = &limitDefault
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetClusterCheckinsParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("limit", "query", int64(*o.Limit), 1000, false); err != nil {
+		return err
+	}
+
+	return nil
+}
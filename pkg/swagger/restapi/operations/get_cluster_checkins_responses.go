@@ -4,6 +4,7 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-swagger/go-swagger/httpkit"
@@ -16,6 +17,9 @@ import (
 swagger:response getClusterCheckinsOK
 */
 type GetClusterCheckinsOK struct {
+	/*the nextCursor value of the response body. empty on the last page, and on CSV and NDJSON responses
+	 */
+	XNextCursor string `json:"X-Next-Cursor"`
 
 	// In: body
 	Payload *models.ClustersCount `json:"body,omitempty"`
@@ -26,6 +30,17 @@ func NewGetClusterCheckinsOK() *GetClusterCheckinsOK {
 	return &GetClusterCheckinsOK{}
 }
 
+// WithXNextCursor adds the xNextCursor to the get cluster checkins o k response
+func (o *GetClusterCheckinsOK) WithXNextCursor(xNextCursor string) *GetClusterCheckinsOK {
+	o.XNextCursor = xNextCursor
+	return o
+}
+
+// SetXNextCursor sets the xNextCursor to the get cluster checkins o k response
+func (o *GetClusterCheckinsOK) SetXNextCursor(xNextCursor string) {
+	o.XNextCursor = xNextCursor
+}
+
 // WithPayload adds the payload to the get cluster checkins o k response
 func (o *GetClusterCheckinsOK) WithPayload(payload *models.ClustersCount) *GetClusterCheckinsOK {
 	o.Payload = payload
@@ -40,6 +55,9 @@ func (o *GetClusterCheckinsOK) SetPayload(payload *models.ClustersCount) {
 // WriteResponse to the client
 func (o *GetClusterCheckinsOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
 
+	// response header X-Next-Cursor
+	rw.Header().Set("X-Next-Cursor", fmt.Sprintf("%v", o.XNextCursor))
+
 	rw.WriteHeader(200)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
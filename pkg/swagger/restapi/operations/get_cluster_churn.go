@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetClusterChurnHandlerFunc turns a function with the right signature into a get cluster churn handler
+type GetClusterChurnHandlerFunc func(GetClusterChurnParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetClusterChurnHandlerFunc) Handle(params GetClusterChurnParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetClusterChurnHandler interface for that can handle valid get cluster churn params
+type GetClusterChurnHandler interface {
+	Handle(GetClusterChurnParams) middleware.Responder
+}
+
+// NewGetClusterChurn creates a new http.Handler for the get cluster churn operation
+func NewGetClusterChurn(ctx *middleware.Context, handler GetClusterChurnHandler) *GetClusterChurn {
+	return &GetClusterChurn{Context: ctx, Handler: handler}
+}
+
+/*GetClusterChurn swagger:route GET /v3/clusters/churn getClusterChurn
+
+count the new, retained, reactivated and lost clusters in each day, week or month
+
+*/
+type GetClusterChurn struct {
+	Context *middleware.Context
+	Handler GetClusterChurnHandler
+}
+
+func (o *GetClusterChurn) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetClusterChurnParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetClusterChurnOK cluster churn response
+
+swagger:response getClusterChurnOK
+*/
+type GetClusterChurnOK struct {
+
+	// In: body
+	Payload *models.ClusterChurn `json:"body,omitempty"`
+}
+
+// NewGetClusterChurnOK creates GetClusterChurnOK with default headers values
+func NewGetClusterChurnOK() *GetClusterChurnOK {
+	return &GetClusterChurnOK{}
+}
+
+// WithPayload adds the payload to the get cluster churn o k response
+func (o *GetClusterChurnOK) WithPayload(payload *models.ClusterChurn) *GetClusterChurnOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster churn o k response
+func (o *GetClusterChurnOK) SetPayload(payload *models.ClusterChurn) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterChurnOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetClusterChurnDefault unexpected error
+
+swagger:response getClusterChurnDefault
+*/
+type GetClusterChurnDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetClusterChurnDefault creates GetClusterChurnDefault with default headers values
+func NewGetClusterChurnDefault(code int) *GetClusterChurnDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetClusterChurnDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get cluster churn default response
+func (o *GetClusterChurnDefault) WithStatusCode(code int) *GetClusterChurnDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get cluster churn default response
+func (o *GetClusterChurnDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get cluster churn default response
+func (o *GetClusterChurnDefault) WithPayload(payload *models.Error) *GetClusterChurnDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster churn default response
+func (o *GetClusterChurnDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterChurnDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
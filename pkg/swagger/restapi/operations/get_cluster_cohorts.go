@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetClusterCohortsHandlerFunc turns a function with the right signature into a get cluster cohorts handler
+type GetClusterCohortsHandlerFunc func(GetClusterCohortsParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetClusterCohortsHandlerFunc) Handle(params GetClusterCohortsParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetClusterCohortsHandler interface for that can handle valid get cluster cohorts params
+type GetClusterCohortsHandler interface {
+	Handle(GetClusterCohortsParams) middleware.Responder
+}
+
+// NewGetClusterCohorts creates a new http.Handler for the get cluster cohorts operation
+func NewGetClusterCohorts(ctx *middleware.Context, handler GetClusterCohortsHandler) *GetClusterCohorts {
+	return &GetClusterCohorts{Context: ctx, Handler: handler}
+}
+
+/*GetClusterCohorts swagger:route GET /v3/clusters/cohorts getClusterCohorts
+
+report the fraction of the clusters that first checked in during each week or month that kept checking in
+
+*/
+type GetClusterCohorts struct {
+	Context *middleware.Context
+	Handler GetClusterCohortsHandler
+}
+
+func (o *GetClusterCohorts) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetClusterCohortsParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
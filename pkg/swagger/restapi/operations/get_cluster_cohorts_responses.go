@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetClusterCohortsOK cluster cohorts response
+
+swagger:response getClusterCohortsOK
+*/
+type GetClusterCohortsOK struct {
+
+	// In: body
+	Payload *models.ClusterCohorts `json:"body,omitempty"`
+}
+
+// NewGetClusterCohortsOK creates GetClusterCohortsOK with default headers values
+func NewGetClusterCohortsOK() *GetClusterCohortsOK {
+	return &GetClusterCohortsOK{}
+}
+
+// WithPayload adds the payload to the get cluster cohorts o k response
+func (o *GetClusterCohortsOK) WithPayload(payload *models.ClusterCohorts) *GetClusterCohortsOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster cohorts o k response
+func (o *GetClusterCohortsOK) SetPayload(payload *models.ClusterCohorts) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCohortsOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetClusterCohortsDefault unexpected error
+
+swagger:response getClusterCohortsDefault
+*/
+type GetClusterCohortsDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetClusterCohortsDefault creates GetClusterCohortsDefault with default headers values
+func NewGetClusterCohortsDefault(code int) *GetClusterCohortsDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetClusterCohortsDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get cluster cohorts default response
+func (o *GetClusterCohortsDefault) WithStatusCode(code int) *GetClusterCohortsDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get cluster cohorts default response
+func (o *GetClusterCohortsDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get cluster cohorts default response
+func (o *GetClusterCohortsDefault) WithPayload(payload *models.Error) *GetClusterCohortsDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get cluster cohorts default response
+func (o *GetClusterCohortsDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetClusterCohortsDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
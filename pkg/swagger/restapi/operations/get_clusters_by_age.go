@@ -67,6 +67,10 @@ type GetClustersByAgeOKBodyBody struct {
 	Required: true
 	*/
 	Data []*models.Cluster `json:"data"`
+
+	/* pass as the cursor parameter to get the next page. absent on the last page
+	 */
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Validate validates this get clusters by age o k body body
@@ -9,6 +9,8 @@ import (
 	"github.com/go-swagger/go-swagger/errors"
 	"github.com/go-swagger/go-swagger/httpkit"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+	"github.com/go-swagger/go-swagger/swag"
 
 	strfmt "github.com/go-swagger/go-swagger/strfmt"
 )
@@ -16,8 +18,12 @@ import (
 // NewGetClustersByAgeParams creates a new GetClustersByAgeParams object
 // with the default values initialized.
 func NewGetClustersByAgeParams() GetClustersByAgeParams {
-	var ()
-	return GetClustersByAgeParams{}
+	var (
+		limitDefault int64 = int64(100)
+	)
+	return GetClustersByAgeParams{
+		Limit: &limitDefault,
+	}
 }
 
 // GetClustersByAgeParams contains all the bound params for the get clusters by age operation
@@ -25,6 +31,10 @@ func NewGetClustersByAgeParams() GetClustersByAgeParams {
 //
 // swagger:parameters getClustersByAge
 type GetClustersByAgeParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: query
 	*/
@@ -41,12 +51,26 @@ type GetClustersByAgeParams struct {
 	  In: query
 	*/
 	CreatedBefore *strfmt.DateTime
+	/*
+	  the nextCursor value from the previous page
+	  In: query
+	*/
+	Cursor *string
+	/*
+	  the maximum number of clusters to return. CSV and NDJSON responses ignore it, and return every cluster after the cursor
+	  Maximum: 1000
+	  Minimum: 1
+	  In: query
+	  Default: 100
+	*/
+	Limit *int64
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
 // for simple values it will use straight method calls
 func (o *GetClustersByAgeParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 	qs := httpkit.Values(r.URL.Query())
 
 	qCheckedInAfter, qhkCheckedInAfter, _ := qs.GetOK("checked_in_after")
@@ -69,6 +93,16 @@ func (o *GetClustersByAgeParams) BindRequest(r *http.Request, route *middleware.
 		res = append(res, err)
 	}
 
+	qCursor, qhkCursor, _ := qs.GetOK("cursor")
+	if err := o.bindCursor(qCursor, qhkCursor, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -146,3 +180,54 @@ func (o *GetClustersByAgeParams) bindCreatedBefore(rawData []string, hasKey bool
 
 	return nil
 }
+
+func (o *GetClustersByAgeParams) bindCursor(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Cursor = &raw
+
+	return nil
+}
+
+func (o *GetClustersByAgeParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		var limitDefault int64 = int64(100)
+		o.Limit = &limitDefault
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetClustersByAgeParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("limit", "query", int64(*o.Limit), 1000, false); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-swagger/go-swagger/httpkit"
@@ -16,6 +17,9 @@ import (
 swagger:response getClustersByAgeOK
 */
 type GetClustersByAgeOK struct {
+	/*the nextCursor value of the response body. empty on the last page, and on CSV and NDJSON responses
+	 */
+	XNextCursor string `json:"X-Next-Cursor"`
 
 	// In: body
 	Payload GetClustersByAgeOKBodyBody `json:"body,omitempty"`
@@ -26,6 +30,17 @@ func NewGetClustersByAgeOK() *GetClustersByAgeOK {
 	return &GetClustersByAgeOK{}
 }
 
+// WithXNextCursor adds the xNextCursor to the get clusters by age o k response
+func (o *GetClustersByAgeOK) WithXNextCursor(xNextCursor string) *GetClustersByAgeOK {
+	o.XNextCursor = xNextCursor
+	return o
+}
+
+// SetXNextCursor sets the xNextCursor to the get clusters by age o k response
+func (o *GetClustersByAgeOK) SetXNextCursor(xNextCursor string) {
+	o.XNextCursor = xNextCursor
+}
+
 // WithPayload adds the payload to the get clusters by age o k response
 func (o *GetClustersByAgeOK) WithPayload(payload GetClustersByAgeOKBodyBody) *GetClustersByAgeOK {
 	o.Payload = payload
@@ -40,6 +55,9 @@ func (o *GetClustersByAgeOK) SetPayload(payload GetClustersByAgeOKBodyBody) {
 // WriteResponse to the client
 func (o *GetClustersByAgeOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
 
+	// response header X-Next-Cursor
+	rw.Header().Set("X-Next-Cursor", fmt.Sprintf("%v", o.XNextCursor))
+
 	rw.WriteHeader(200)
 	if err := producer.Produce(rw, o.Payload); err != nil {
 		panic(err) // let the recovery middleware deal with this
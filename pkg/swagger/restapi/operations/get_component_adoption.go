@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetComponentAdoptionHandlerFunc turns a function with the right signature into a get component adoption handler
+type GetComponentAdoptionHandlerFunc func(GetComponentAdoptionParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetComponentAdoptionHandlerFunc) Handle(params GetComponentAdoptionParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetComponentAdoptionHandler interface for that can handle valid get component adoption params
+type GetComponentAdoptionHandler interface {
+	Handle(GetComponentAdoptionParams) middleware.Responder
+}
+
+// NewGetComponentAdoption creates a new http.Handler for the get component adoption operation
+func NewGetComponentAdoption(ctx *middleware.Context, handler GetComponentAdoptionHandler) *GetComponentAdoption {
+	return &GetComponentAdoption{Context: ctx, Handler: handler}
+}
+
+/*GetComponentAdoption swagger:route GET /v3/stats/adoption/{train}/{component} getComponentAdoption
+
+count the clusters running each release of a component
+
+*/
+type GetComponentAdoption struct {
+	Context *middleware.Context
+	Handler GetComponentAdoptionHandler
+}
+
+func (o *GetComponentAdoption) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetComponentAdoptionParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
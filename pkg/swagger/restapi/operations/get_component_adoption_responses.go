@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetComponentAdoptionOK component adoption response
+
+swagger:response getComponentAdoptionOK
+*/
+type GetComponentAdoptionOK struct {
+
+	// In: body
+	Payload *models.ComponentAdoption `json:"body,omitempty"`
+}
+
+// NewGetComponentAdoptionOK creates GetComponentAdoptionOK with default headers values
+func NewGetComponentAdoptionOK() *GetComponentAdoptionOK {
+	return &GetComponentAdoptionOK{}
+}
+
+// WithPayload adds the payload to the get component adoption o k response
+func (o *GetComponentAdoptionOK) WithPayload(payload *models.ComponentAdoption) *GetComponentAdoptionOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get component adoption o k response
+func (o *GetComponentAdoptionOK) SetPayload(payload *models.ComponentAdoption) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetComponentAdoptionOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetComponentAdoptionDefault unexpected error
+
+swagger:response getComponentAdoptionDefault
+*/
+type GetComponentAdoptionDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetComponentAdoptionDefault creates GetComponentAdoptionDefault with default headers values
+func NewGetComponentAdoptionDefault(code int) *GetComponentAdoptionDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetComponentAdoptionDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get component adoption default response
+func (o *GetComponentAdoptionDefault) WithStatusCode(code int) *GetComponentAdoptionDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get component adoption default response
+func (o *GetComponentAdoptionDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get component adoption default response
+func (o *GetComponentAdoptionDefault) WithPayload(payload *models.Error) *GetComponentAdoptionDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get component adoption default response
+func (o *GetComponentAdoptionDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetComponentAdoptionDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -24,6 +24,10 @@ func NewGetComponentByNameParams() GetComponentByNameParams {
 //
 // swagger:parameters getComponentByName
 type GetComponentByNameParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*A component is a single deis component, e.g., deis-router
 	  Required: true
 	  In: path
@@ -40,6 +44,7 @@ type GetComponentByNameParams struct {
 // for simple values it will use straight method calls
 func (o *GetComponentByNameParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	rComponent, rhkComponent, _ := route.Params.GetOK("component")
 	if err := o.bindComponent(rComponent, rhkComponent, route.Formats); err != nil {
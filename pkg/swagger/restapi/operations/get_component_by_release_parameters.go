@@ -24,6 +24,10 @@ func NewGetComponentByReleaseParams() GetComponentByReleaseParams {
 //
 // swagger:parameters getComponentByRelease
 type GetComponentByReleaseParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*A component is a single deis component, e.g., deis-router
 	  Required: true
 	  In: path
@@ -45,6 +49,7 @@ type GetComponentByReleaseParams struct {
 // for simple values it will use straight method calls
 func (o *GetComponentByReleaseParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	rComponent, rhkComponent, _ := route.Params.GetOK("component")
 	if err := o.bindComponent(rComponent, rhkComponent, route.Formats); err != nil {
@@ -22,6 +22,10 @@ func NewGetComponentsByLatestReleaseForV2Params() GetComponentsByLatestReleaseFo
 //
 // swagger:parameters getComponentsByLatestReleaseForV2
 type GetComponentsByLatestReleaseForV2Params struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -32,6 +36,7 @@ type GetComponentsByLatestReleaseForV2Params struct {
 // for simple values it will use straight method calls
 func (o *GetComponentsByLatestReleaseForV2Params) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body GetComponentsByLatestReleaseForV2Body
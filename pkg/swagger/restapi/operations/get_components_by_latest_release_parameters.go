@@ -22,6 +22,10 @@ func NewGetComponentsByLatestReleaseParams() GetComponentsByLatestReleaseParams
 //
 // swagger:parameters getComponentsByLatestRelease
 type GetComponentsByLatestReleaseParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -32,6 +36,7 @@ type GetComponentsByLatestReleaseParams struct {
 // for simple values it will use straight method calls
 func (o *GetComponentsByLatestReleaseParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body GetComponentsByLatestReleaseBody
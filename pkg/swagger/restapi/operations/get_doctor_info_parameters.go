@@ -24,6 +24,10 @@ func NewGetDoctorInfoParams() GetDoctorInfoParams {
 //
 // swagger:parameters getDoctorInfo
 type GetDoctorInfoParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*A universal Id to represent a sepcific request or report
 	  Required: true
 	  In: path
@@ -35,6 +39,7 @@ type GetDoctorInfoParams struct {
 // for simple values it will use straight method calls
 func (o *GetDoctorInfoParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	rUUID, rhkUUID, _ := route.Params.GetOK("uuid")
 	if err := o.bindUUID(rUUID, rhkUUID, route.Formats); err != nil {
@@ -9,6 +9,8 @@ import (
 	"github.com/go-swagger/go-swagger/errors"
 	"github.com/go-swagger/go-swagger/httpkit"
 	"github.com/go-swagger/go-swagger/httpkit/middleware"
+	"github.com/go-swagger/go-swagger/httpkit/validate"
+	"github.com/go-swagger/go-swagger/swag"
 
 	strfmt "github.com/go-swagger/go-swagger/strfmt"
 )
@@ -16,8 +18,12 @@ import (
 // NewGetPersistentClustersParams creates a new GetPersistentClustersParams object
 // with the default values initialized.
 func NewGetPersistentClustersParams() GetPersistentClustersParams {
-	var ()
-	return GetPersistentClustersParams{}
+	var (
+		limitDefault int64 = int64(100)
+	)
+	return GetPersistentClustersParams{
+		Limit: &limitDefault,
+	}
 }
 
 // GetPersistentClustersParams contains all the bound params for the get persistent clusters operation
@@ -25,10 +31,27 @@ func NewGetPersistentClustersParams() GetPersistentClustersParams {
 //
 // swagger:parameters getPersistentClusters
 type GetPersistentClustersParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*
+	  the nextCursor value from the previous page
+	  In: query
+	*/
+	Cursor *string
 	/*
 	  In: query
 	*/
 	Epoch *strfmt.DateTime
+	/*
+	  the maximum number of clusters to return. CSV and NDJSON responses ignore it, and return every cluster after the cursor
+	  Maximum: 1000
+	  Minimum: 1
+	  In: query
+	  Default: 100
+	*/
+	Limit *int64
 	/*
 	  In: query
 	*/
@@ -39,13 +62,24 @@ type GetPersistentClustersParams struct {
 // for simple values it will use straight method calls
 func (o *GetPersistentClustersParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 	qs := httpkit.Values(r.URL.Query())
 
+	qCursor, qhkCursor, _ := qs.GetOK("cursor")
+	if err := o.bindCursor(qCursor, qhkCursor, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qEpoch, qhkEpoch, _ := qs.GetOK("epoch")
 	if err := o.bindEpoch(qEpoch, qhkEpoch, route.Formats); err != nil {
 		res = append(res, err)
 	}
 
+	qLimit, qhkLimit, _ := qs.GetOK("limit")
+	if err := o.bindLimit(qLimit, qhkLimit, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	qTimestamp, qhkTimestamp, _ := qs.GetOK("timestamp")
 	if err := o.bindTimestamp(qTimestamp, qhkTimestamp, route.Formats); err != nil {
 		res = append(res, err)
@@ -57,6 +91,20 @@ func (o *GetPersistentClustersParams) BindRequest(r *http.Request, route *middle
 	return nil
 }
 
+func (o *GetPersistentClustersParams) bindCursor(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Cursor = &raw
+
+	return nil
+}
+
 func (o *GetPersistentClustersParams) bindEpoch(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
 	if len(rawData) > 0 {
@@ -75,6 +123,43 @@ func (o *GetPersistentClustersParams) bindEpoch(rawData []string, hasKey bool, f
 	return nil
 }
 
+func (o *GetPersistentClustersParams) bindLimit(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		var limitDefault int64 = int64(100)
+		o.Limit = &limitDefault
+		return nil
+	}
+
+	value, err := swag.ConvertInt64(raw)
+	if err != nil {
+		return errors.InvalidType("limit", "query", "int64", raw)
+	}
+	o.Limit = &value
+
+	if err := o.validateLimit(formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetPersistentClustersParams) validateLimit(formats strfmt.Registry) error {
+
+	if err := validate.MinimumInt("limit", "query", int64(*o.Limit), 1, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("limit", "query", int64(*o.Limit), 1000, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (o *GetPersistentClustersParams) bindTimestamp(rawData []string, hasKey bool, formats strfmt.Registry) error {
 	var raw string
 	if len(rawData) > 0 {
@@ -4,6 +4,7 @@ package operations
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-swagger/go-swagger/httpkit"
@@ -16,6 +17,9 @@ import (
 swagger:response getPersistentClustersOK
 */
 type GetPersistentClustersOK struct {
+	/*the nextCursor value of the response body. empty on the last page, and on CSV and NDJSON responses
+	 */
+	XNextCursor string `json:"X-Next-Cursor"`
 
 	// In: body
 	Payload *models.ClustersCount `json:"body,omitempty"`
@@ -26,6 +30,17 @@ func NewGetPersistentClustersOK() *GetPersistentClustersOK {
 	return &GetPersistentClustersOK{}
 }
 
+// WithXNextCursor adds the xNextCursor to the get persistent clusters o k response
+func (o *GetPersistentClustersOK) WithXNextCursor(xNextCursor string) *GetPersistentClustersOK {
+	o.XNextCursor = xNextCursor
+	return o
+}
+
+// SetXNextCursor sets the xNextCursor to the get persistent clusters o k response
+func (o *GetPersistentClustersOK) SetXNextCursor(xNextCursor string) {
+	o.XNextCursor = xNextCursor
+}
+
 // WithPayload adds the payload to the get persistent clusters o k response
 func (o *GetPersistentClustersOK) WithPayload(payload *models.ClustersCount) *GetPersistentClustersOK {
 	o.Payload = payload
@@ -40,6 +55,9 @@ func (o *GetPersistentClustersOK) SetPayload(payload *models.ClustersCount) {
 // WriteResponse to the client
 func (o *GetPersistentClustersOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
 
+	// response header X-Next-Cursor
+	rw.Header().Set("X-Next-Cursor", fmt.Sprintf("%v", o.XNextCursor))
+
 	rw.WriteHeader(200)
 	if o.Payload != nil {
 		if err := producer.Produce(rw, o.Payload); err != nil {
@@ -0,0 +1,53 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetReleaseAdoptionHandlerFunc turns a function with the right signature into a get release adoption handler
+type GetReleaseAdoptionHandlerFunc func(GetReleaseAdoptionParams) middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetReleaseAdoptionHandlerFunc) Handle(params GetReleaseAdoptionParams) middleware.Responder {
+	return fn(params)
+}
+
+// GetReleaseAdoptionHandler interface for that can handle valid get release adoption params
+type GetReleaseAdoptionHandler interface {
+	Handle(GetReleaseAdoptionParams) middleware.Responder
+}
+
+// NewGetReleaseAdoption creates a new http.Handler for the get release adoption operation
+func NewGetReleaseAdoption(ctx *middleware.Context, handler GetReleaseAdoptionHandler) *GetReleaseAdoption {
+	return &GetReleaseAdoption{Context: ctx, Handler: handler}
+}
+
+/*GetReleaseAdoption swagger:route GET /v3/stats/adoption/{train}/{component}/{release} getReleaseAdoption
+
+count the clusters running a release on each day since it was published
+
+*/
+type GetReleaseAdoption struct {
+	Context *middleware.Context
+	Handler GetReleaseAdoptionHandler
+}
+
+func (o *GetReleaseAdoption) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+	var Params = NewGetReleaseAdoptionParams()
+
+	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle(Params) // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
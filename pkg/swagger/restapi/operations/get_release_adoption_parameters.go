@@ -0,0 +1,136 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/errors"
+	"github.com/go-swagger/go-swagger/httpkit"
+	"github.com/go-swagger/go-swagger/httpkit/middleware"
+
+	strfmt "github.com/go-swagger/go-swagger/strfmt"
+)
+
+// NewGetReleaseAdoptionParams creates a new GetReleaseAdoptionParams object
+// with the default values initialized.
+func NewGetReleaseAdoptionParams() GetReleaseAdoptionParams {
+	var ()
+	return GetReleaseAdoptionParams{}
+}
+
+// GetReleaseAdoptionParams contains all the bound params for the get release adoption operation
+// typically these are obtained from a http.Request
+//
+// swagger:parameters getReleaseAdoption
+type GetReleaseAdoptionParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
+	/*A component is a single deis component, e.g., deis-router
+	  Required: true
+	  In: path
+	*/
+	Component string
+	/*The release version of the deis component, eg., 2.0.0-beta2
+	  Required: true
+	  In: path
+	*/
+	Release string
+	/*A train is a release cadence type, e.g., "beta" or "stable"
+	  Required: true
+	  In: path
+	*/
+	Train string
+	/*
+	  only count checkins before this time. defaults to now
+	  In: query
+	*/
+	Until *strfmt.DateTime
+}
+
+// BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
+// for simple values it will use straight method calls
+func (o *GetReleaseAdoptionParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
+	var res []error
+	o.HTTPRequest = r
+	qs := httpkit.Values(r.URL.Query())
+
+	rComponent, rhkComponent, _ := route.Params.GetOK("component")
+	if err := o.bindComponent(rComponent, rhkComponent, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rRelease, rhkRelease, _ := route.Params.GetOK("release")
+	if err := o.bindRelease(rRelease, rhkRelease, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	rTrain, rhkTrain, _ := route.Params.GetOK("train")
+	if err := o.bindTrain(rTrain, rhkTrain, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qUntil, qhkUntil, _ := qs.GetOK("until")
+	if err := o.bindUntil(qUntil, qhkUntil, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (o *GetReleaseAdoptionParams) bindComponent(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Component = raw
+
+	return nil
+}
+
+func (o *GetReleaseAdoptionParams) bindRelease(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Release = raw
+
+	return nil
+}
+
+func (o *GetReleaseAdoptionParams) bindTrain(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+
+	o.Train = raw
+
+	return nil
+}
+
+func (o *GetReleaseAdoptionParams) bindUntil(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	value, err := formats.Parse("date-time", raw)
+	if err != nil {
+		return errors.InvalidType("until", "query", "strfmt.DateTime", raw)
+	}
+	o.Until = (value.(*strfmt.DateTime))
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetReleaseAdoptionOK release adoption response
+
+swagger:response getReleaseAdoptionOK
+*/
+type GetReleaseAdoptionOK struct {
+
+	// In: body
+	Payload *models.ReleaseAdoption `json:"body,omitempty"`
+}
+
+// NewGetReleaseAdoptionOK creates GetReleaseAdoptionOK with default headers values
+func NewGetReleaseAdoptionOK() *GetReleaseAdoptionOK {
+	return &GetReleaseAdoptionOK{}
+}
+
+// WithPayload adds the payload to the get release adoption o k response
+func (o *GetReleaseAdoptionOK) WithPayload(payload *models.ReleaseAdoption) *GetReleaseAdoptionOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get release adoption o k response
+func (o *GetReleaseAdoptionOK) SetPayload(payload *models.ReleaseAdoption) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetReleaseAdoptionOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetReleaseAdoptionDefault unexpected error
+
+swagger:response getReleaseAdoptionDefault
+*/
+type GetReleaseAdoptionDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetReleaseAdoptionDefault creates GetReleaseAdoptionDefault with default headers values
+func NewGetReleaseAdoptionDefault(code int) *GetReleaseAdoptionDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetReleaseAdoptionDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get release adoption default response
+func (o *GetReleaseAdoptionDefault) WithStatusCode(code int) *GetReleaseAdoptionDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get release adoption default response
+func (o *GetReleaseAdoptionDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get release adoption default response
+func (o *GetReleaseAdoptionDefault) WithPayload(payload *models.Error) *GetReleaseAdoptionDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get release adoption default response
+func (o *GetReleaseAdoptionDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetReleaseAdoptionDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the generate command
+
+import (
+	"net/http"
+
+	middleware "github.com/go-swagger/go-swagger/httpkit/middleware"
+)
+
+// GetSigningKeyHandlerFunc turns a function with the right signature into a get signing key handler
+type GetSigningKeyHandlerFunc func() middleware.Responder
+
+// Handle executing the request and returning a response
+func (fn GetSigningKeyHandlerFunc) Handle() middleware.Responder {
+	return fn()
+}
+
+// GetSigningKeyHandler interface for that can handle valid get signing key params
+type GetSigningKeyHandler interface {
+	Handle() middleware.Responder
+}
+
+// NewGetSigningKey creates a new http.Handler for the get signing key operation
+func NewGetSigningKey(ctx *middleware.Context, handler GetSigningKeyHandler) *GetSigningKey {
+	return &GetSigningKey{Context: ctx, Handler: handler}
+}
+
+/*GetSigningKey swagger:route GET /v3/signing-key getSigningKey
+
+read the public key that verifies release signatures
+
+*/
+type GetSigningKey struct {
+	Context *middleware.Context
+	Handler GetSigningKeyHandler
+}
+
+func (o *GetSigningKey) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	route, _ := o.Context.RouteInfo(r)
+
+	if err := o.Context.BindValidRequest(r, route, nil); err != nil { // bind params
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+
+	res := o.Handler.Handle() // actually handle the request
+
+	o.Context.Respond(rw, r, route.Produces, route, res)
+
+}
@@ -0,0 +1,104 @@
+package operations
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"net/http"
+
+	"github.com/go-swagger/go-swagger/httpkit"
+
+	"github.com/deis/workflow-manager-api/pkg/swagger/models"
+)
+
+/*GetSigningKeyOK signing key response
+
+swagger:response getSigningKeyOK
+*/
+type GetSigningKeyOK struct {
+
+	// In: body
+	Payload *models.SigningKey `json:"body,omitempty"`
+}
+
+// NewGetSigningKeyOK creates GetSigningKeyOK with default headers values
+func NewGetSigningKeyOK() *GetSigningKeyOK {
+	return &GetSigningKeyOK{}
+}
+
+// WithPayload adds the payload to the get signing key o k response
+func (o *GetSigningKeyOK) WithPayload(payload *models.SigningKey) *GetSigningKeyOK {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get signing key o k response
+func (o *GetSigningKeyOK) SetPayload(payload *models.SigningKey) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSigningKeyOK) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(200)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
+
+/*GetSigningKeyDefault unexpected error
+
+swagger:response getSigningKeyDefault
+*/
+type GetSigningKeyDefault struct {
+	_statusCode int
+
+	// In: body
+	Payload *models.Error `json:"body,omitempty"`
+}
+
+// NewGetSigningKeyDefault creates GetSigningKeyDefault with default headers values
+func NewGetSigningKeyDefault(code int) *GetSigningKeyDefault {
+	if code <= 0 {
+		code = 500
+	}
+
+	return &GetSigningKeyDefault{
+		_statusCode: code,
+	}
+}
+
+// WithStatusCode adds the status to the get signing key default response
+func (o *GetSigningKeyDefault) WithStatusCode(code int) *GetSigningKeyDefault {
+	o._statusCode = code
+	return o
+}
+
+// SetStatusCode sets the status to the get signing key default response
+func (o *GetSigningKeyDefault) SetStatusCode(code int) {
+	o._statusCode = code
+}
+
+// WithPayload adds the payload to the get signing key default response
+func (o *GetSigningKeyDefault) WithPayload(payload *models.Error) *GetSigningKeyDefault {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the get signing key default response
+func (o *GetSigningKeyDefault) SetPayload(payload *models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *GetSigningKeyDefault) WriteResponse(rw http.ResponseWriter, producer httpkit.Producer) {
+
+	rw.WriteHeader(o._statusCode)
+	if o.Payload != nil {
+		if err := producer.Produce(rw, o.Payload); err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+	}
+}
@@ -10,16 +10,16 @@ import (
 )
 
 // PublishComponentReleaseHandlerFunc turns a function with the right signature into a publish component release handler
-type PublishComponentReleaseHandlerFunc func(PublishComponentReleaseParams) middleware.Responder
+type PublishComponentReleaseHandlerFunc func(PublishComponentReleaseParams, interface{}) middleware.Responder
 
 // Handle executing the request and returning a response
-func (fn PublishComponentReleaseHandlerFunc) Handle(params PublishComponentReleaseParams) middleware.Responder {
-	return fn(params)
+func (fn PublishComponentReleaseHandlerFunc) Handle(params PublishComponentReleaseParams, principal interface{}) middleware.Responder {
+	return fn(params, principal)
 }
 
 // PublishComponentReleaseHandler interface for that can handle valid publish component release params
 type PublishComponentReleaseHandler interface {
-	Handle(PublishComponentReleaseParams) middleware.Responder
+	Handle(PublishComponentReleaseParams, interface{}) middleware.Responder
 }
 
 // NewPublishComponentRelease creates a new http.Handler for the publish component release operation
@@ -41,12 +41,22 @@ func (o *PublishComponentRelease) ServeHTTP(rw http.ResponseWriter, r *http.Requ
 	route, _ := o.Context.RouteInfo(r)
 	var Params = NewPublishComponentReleaseParams()
 
+	uprinc, err := o.Context.Authorize(r, route)
+	if err != nil {
+		o.Context.Respond(rw, r, route.Produces, route, err)
+		return
+	}
+	var principal interface{}
+	if uprinc != nil {
+		principal = uprinc
+	}
+
 	if err := o.Context.BindValidRequest(r, route, &Params); err != nil { // bind params
 		o.Context.Respond(rw, r, route.Produces, route, err)
 		return
 	}
 
-	res := o.Handler.Handle(Params) // actually handle the request
+	res := o.Handler.Handle(Params, principal) // actually handle the request
 
 	o.Context.Respond(rw, r, route.Produces, route, res)
 
@@ -26,6 +26,10 @@ func NewPublishComponentReleaseParams() PublishComponentReleaseParams {
 //
 // swagger:parameters publishComponentRelease
 type PublishComponentReleaseParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -51,6 +55,7 @@ type PublishComponentReleaseParams struct {
 // for simple values it will use straight method calls
 func (o *PublishComponentReleaseParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body models.ComponentVersion
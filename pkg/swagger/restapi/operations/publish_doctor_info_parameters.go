@@ -26,6 +26,10 @@ func NewPublishDoctorInfoParams() PublishDoctorInfoParams {
 //
 // swagger:parameters publishDoctorInfo
 type PublishDoctorInfoParams struct {
+
+	// HTTP Request Object
+	HTTPRequest *http.Request
+
 	/*
 	  In: body
 	*/
@@ -41,6 +45,7 @@ type PublishDoctorInfoParams struct {
 // for simple values it will use straight method calls
 func (o *PublishDoctorInfoParams) BindRequest(r *http.Request, route *middleware.MatchedRoute) error {
 	var res []error
+	o.HTTPRequest = r
 
 	defer r.Body.Close()
 	var body models.DoctorInfo
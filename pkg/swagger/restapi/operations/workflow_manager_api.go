@@ -41,25 +41,51 @@ type WorkflowManagerAPI struct {
 	// JSONConsumer registers a consumer for a "application/json" mime type
 	JSONConsumer httpkit.Consumer
 
+	// CSVProducer registers a producer for a "text/csv" mime type
+	CSVProducer httpkit.Producer
 	// JSONProducer registers a producer for a "application/json" mime type
 	JSONProducer httpkit.Producer
+	// NDJSONProducer registers a producer for a "application/x-ndjson" mime type
+	NDJSONProducer httpkit.Producer
 
 	// BasicAuth registers a function that takes username and password and returns a principal
 	// it performs authentication with basic auth
 	BasicAuth func(string, string) (interface{}, error)
 
+	// PublisherTokenAuth registers a function that takes a token and returns a principal
+	// it performs authentication based on an api key X-Publisher-Token provided in the header
+	PublisherTokenAuth func(string) (interface{}, error)
+
+	// CheckLivenessHandler sets the operation handler for the check liveness operation
+	CheckLivenessHandler CheckLivenessHandler
 	// CreateClusterDetailsHandler sets the operation handler for the create cluster details operation
 	CreateClusterDetailsHandler CreateClusterDetailsHandler
+	// CheckReadinessHandler sets the operation handler for the check readiness operation
+	CheckReadinessHandler CheckReadinessHandler
 	// CreateClusterDetailsForV2Handler sets the operation handler for the create cluster details for v2 operation
 	CreateClusterDetailsForV2Handler CreateClusterDetailsForV2Handler
+	// DeprecateComponentReleaseHandler sets the operation handler for the deprecate component release operation
+	DeprecateComponentReleaseHandler DeprecateComponentReleaseHandler
+	// GetActiveClustersHandler sets the operation handler for the get active clusters operation
+	GetActiveClustersHandler GetActiveClustersHandler
 	// GetClusterByIDHandler sets the operation handler for the get cluster by id operation
 	GetClusterByIDHandler GetClusterByIDHandler
 	// GetClusterCheckinsHandler sets the operation handler for the get cluster checkins operation
 	GetClusterCheckinsHandler GetClusterCheckinsHandler
+	// GetClusterCheckinDiffHandler sets the operation handler for the get cluster checkin diff operation
+	GetClusterCheckinDiffHandler GetClusterCheckinDiffHandler
+	// GetClusterCheckinHistoryHandler sets the operation handler for the get cluster checkin history operation
+	GetClusterCheckinHistoryHandler GetClusterCheckinHistoryHandler
 	// GetClustersByAgeHandler sets the operation handler for the get clusters by age operation
 	GetClustersByAgeHandler GetClustersByAgeHandler
+	// GetClusterChurnHandler sets the operation handler for the get cluster churn operation
+	GetClusterChurnHandler GetClusterChurnHandler
 	// GetClustersCountHandler sets the operation handler for the get clusters count operation
 	GetClustersCountHandler GetClustersCountHandler
+	// GetClusterCohortsHandler sets the operation handler for the get cluster cohorts operation
+	GetClusterCohortsHandler GetClusterCohortsHandler
+	// GetComponentAdoptionHandler sets the operation handler for the get component adoption operation
+	GetComponentAdoptionHandler GetComponentAdoptionHandler
 	// GetComponentByNameHandler sets the operation handler for the get component by name operation
 	GetComponentByNameHandler GetComponentByNameHandler
 	// GetComponentByReleaseHandler sets the operation handler for the get component by release operation
@@ -72,6 +98,10 @@ type WorkflowManagerAPI struct {
 	GetDoctorInfoHandler GetDoctorInfoHandler
 	// GetPersistentClustersHandler sets the operation handler for the get persistent clusters operation
 	GetPersistentClustersHandler GetPersistentClustersHandler
+	// GetSigningKeyHandler sets the operation handler for the get signing key operation
+	GetSigningKeyHandler GetSigningKeyHandler
+	// GetReleaseAdoptionHandler sets the operation handler for the get release adoption operation
+	GetReleaseAdoptionHandler GetReleaseAdoptionHandler
 	// PingHandler sets the operation handler for the ping operation
 	PingHandler PingHandler
 	// PublishComponentReleaseHandler sets the operation handler for the publish component release operation
@@ -129,14 +159,34 @@ func (o *WorkflowManagerAPI) Validate() error {
 		unregistered = append(unregistered, "JSONConsumer")
 	}
 
+	if o.CSVProducer == nil {
+		unregistered = append(unregistered, "CSVProducer")
+	}
+
 	if o.JSONProducer == nil {
 		unregistered = append(unregistered, "JSONProducer")
 	}
 
+	if o.NDJSONProducer == nil {
+		unregistered = append(unregistered, "NDJSONProducer")
+	}
+
 	if o.BasicAuth == nil {
 		unregistered = append(unregistered, "Auth")
 	}
 
+	if o.PublisherTokenAuth == nil {
+		unregistered = append(unregistered, "XPublisherTokenAuth")
+	}
+
+	if o.CheckLivenessHandler == nil {
+		unregistered = append(unregistered, "CheckLivenessHandler")
+	}
+
+	if o.CheckReadinessHandler == nil {
+		unregistered = append(unregistered, "CheckReadinessHandler")
+	}
+
 	if o.CreateClusterDetailsHandler == nil {
 		unregistered = append(unregistered, "CreateClusterDetailsHandler")
 	}
@@ -145,14 +195,38 @@ func (o *WorkflowManagerAPI) Validate() error {
 		unregistered = append(unregistered, "CreateClusterDetailsForV2Handler")
 	}
 
+	if o.DeprecateComponentReleaseHandler == nil {
+		unregistered = append(unregistered, "DeprecateComponentReleaseHandler")
+	}
+
+	if o.GetActiveClustersHandler == nil {
+		unregistered = append(unregistered, "GetActiveClustersHandler")
+	}
+
 	if o.GetClusterByIDHandler == nil {
 		unregistered = append(unregistered, "GetClusterByIDHandler")
 	}
 
+	if o.GetClusterCheckinDiffHandler == nil {
+		unregistered = append(unregistered, "GetClusterCheckinDiffHandler")
+	}
+
+	if o.GetClusterCheckinHistoryHandler == nil {
+		unregistered = append(unregistered, "GetClusterCheckinHistoryHandler")
+	}
+
 	if o.GetClusterCheckinsHandler == nil {
 		unregistered = append(unregistered, "GetClusterCheckinsHandler")
 	}
 
+	if o.GetClusterChurnHandler == nil {
+		unregistered = append(unregistered, "GetClusterChurnHandler")
+	}
+
+	if o.GetClusterCohortsHandler == nil {
+		unregistered = append(unregistered, "GetClusterCohortsHandler")
+	}
+
 	if o.GetClustersByAgeHandler == nil {
 		unregistered = append(unregistered, "GetClustersByAgeHandler")
 	}
@@ -161,6 +235,10 @@ func (o *WorkflowManagerAPI) Validate() error {
 		unregistered = append(unregistered, "GetClustersCountHandler")
 	}
 
+	if o.GetComponentAdoptionHandler == nil {
+		unregistered = append(unregistered, "GetComponentAdoptionHandler")
+	}
+
 	if o.GetComponentByNameHandler == nil {
 		unregistered = append(unregistered, "GetComponentByNameHandler")
 	}
@@ -185,6 +263,14 @@ func (o *WorkflowManagerAPI) Validate() error {
 		unregistered = append(unregistered, "GetPersistentClustersHandler")
 	}
 
+	if o.GetReleaseAdoptionHandler == nil {
+		unregistered = append(unregistered, "GetReleaseAdoptionHandler")
+	}
+
+	if o.GetSigningKeyHandler == nil {
+		unregistered = append(unregistered, "GetSigningKeyHandler")
+	}
+
 	if o.PingHandler == nil {
 		unregistered = append(unregistered, "PingHandler")
 	}
@@ -220,6 +306,10 @@ func (o *WorkflowManagerAPI) AuthenticatorsFor(schemes map[string]spec.SecurityS
 			_ = scheme
 			result[name] = security.BasicAuth(func(u, p string) (interface{}, error) { return o.BasicAuth(u, p) })
 
+		case "publisherToken":
+
+			result[name] = security.APIKeyAuth(scheme.Name, scheme.In, func(tok string) (interface{}, error) { return o.PublisherTokenAuth(tok) })
+
 		}
 	}
 	return result
@@ -252,6 +342,12 @@ func (o *WorkflowManagerAPI) ProducersFor(mediaTypes []string) map[string]httpki
 		case "application/json":
 			result["application/json"] = o.JSONProducer
 
+		case "application/x-ndjson":
+			result["application/x-ndjson"] = o.NDJSONProducer
+
+		case "text/csv":
+			result["text/csv"] = o.CSVProducer
+
 		}
 	}
 	return result
@@ -280,6 +376,16 @@ func (o *WorkflowManagerAPI) initHandlerCache() {
 		o.handlers = make(map[string]map[string]http.Handler)
 	}
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/healthz"] = NewCheckLiveness(o.context, o.CheckLivenessHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/readyz"] = NewCheckReadiness(o.context, o.CheckReadinessHandler)
+
 	if o.handlers["POST"] == nil {
 		o.handlers[strings.ToUpper("POST")] = make(map[string]http.Handler)
 	}
@@ -290,16 +396,46 @@ func (o *WorkflowManagerAPI) initHandlerCache() {
 	}
 	o.handlers["POST"]["/v2/clusters/{id}"] = NewCreateClusterDetailsForV2(o.context, o.CreateClusterDetailsForV2Handler)
 
+	if o.handlers["PATCH"] == nil {
+		o.handlers[strings.ToUpper("PATCH")] = make(map[string]http.Handler)
+	}
+	o.handlers["PATCH"]["/v3/versions/{train}/{component}/{release}"] = NewDeprecateComponentRelease(o.context, o.DeprecateComponentReleaseHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/clusters/active"] = NewGetActiveClusters(o.context, o.GetActiveClustersHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/v3/clusters/{id}"] = NewGetClusterByID(o.context, o.GetClusterByIDHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/clusters/{id}/checkins/diff"] = NewGetClusterCheckinDiff(o.context, o.GetClusterCheckinDiffHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/clusters/{id}/checkins"] = NewGetClusterCheckinHistory(o.context, o.GetClusterCheckinHistoryHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/v3/clusters/checkins"] = NewGetClusterCheckins(o.context, o.GetClusterCheckinsHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/clusters/churn"] = NewGetClusterChurn(o.context, o.GetClusterChurnHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/clusters/cohorts"] = NewGetClusterCohorts(o.context, o.GetClusterCohortsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
@@ -310,6 +446,11 @@ func (o *WorkflowManagerAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/v3/clusters/count"] = NewGetClustersCount(o.context, o.GetClustersCountHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/stats/adoption/{train}/{component}"] = NewGetComponentAdoption(o.context, o.GetComponentAdoptionHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
@@ -340,6 +481,16 @@ func (o *WorkflowManagerAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/v3/clusters/persistent"] = NewGetPersistentClusters(o.context, o.GetPersistentClustersHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/stats/adoption/{train}/{component}/{release}"] = NewGetReleaseAdoption(o.context, o.GetReleaseAdoptionHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/v3/signing-key"] = NewGetSigningKey(o.context, o.GetSigningKeyHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers[strings.ToUpper("GET")] = make(map[string]http.Handler)
 	}
@@ -25,7 +25,7 @@ import (
 
 const (
 	componentName     = "testcomponent"
-	clusterID         = "testcluster"
+	clusterID         = "3c6b9a52-1b7e-4d2f-8f4e-0a9d7c5e2b13"
 	releaseTimeFormat = "2006-01-02T15:04:05Z"
 )
 
@@ -191,7 +191,27 @@ func TestPostVersions(t *testing.T) {
 	}
 	body := new(bytes.Buffer)
 	assert.NoErr(t, json.NewEncoder(body).Encode(componentVer))
-	resp, err := httpPost(srv, urlPath("v3", "versions", train, componentName, version), string(body.Bytes()))
+	route := urlPath("v3", "versions", train, componentName, version)
+	token, _, err := data.CreatePublisherToken(memDB, "ci", []string{componentName}, []string{train})
+	assert.NoErr(t, err)
+	otherToken, _, err := data.CreatePublisherToken(memDB, "other", []string{"othercomponent"}, nil)
+	assert.NoErr(t, err)
+
+	// no token, an unknown token, and a token scoped to a different component
+	resp, err := httpPost(srv, route, string(body.Bytes()))
+	assert.NoErr(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusUnauthorized, "response code")
+	resp, err = httpPostPublisherToken(srv, route, string(body.Bytes()), "notatoken")
+	assert.NoErr(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusUnauthorized, "response code")
+	resp, err = httpPostPublisherToken(srv, route, string(body.Bytes()), otherToken)
+	assert.NoErr(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusForbidden, "response code")
+
+	resp, err = httpPostPublisherToken(srv, route, string(body.Bytes()), token)
 	assert.NoErr(t, err)
 	defer resp.Body.Close()
 	assert.Equal(t, resp.StatusCode, http.StatusOK, "response code")
@@ -246,7 +266,7 @@ func TestGetClusterByID(t *testing.T) {
 
 // tests the POST {apiVersion}/clusters/{id} endpoint
 func TestPostClusters(t *testing.T) {
-	jsonData := `{"Components": [{"Component": {"Name": "component-a"}, "Version": {"Version": "1.0"}}], "ID": "testcluster"}`
+	jsonData := fmt.Sprintf(`{"Components": [{"Component": {"Name": "component-a"}, "Version": {"Version": "1.0"}}], "ID": "%s"}`, clusterID)
 	memDB, err := data.NewMemDB()
 	assert.NoErr(t, err)
 	assert.NoErr(t, data.VerifyPersistentStorage(memDB))
@@ -285,6 +305,31 @@ func TestPostClusters(t *testing.T) {
 	}
 }
 
+// tests that the POST {apiVersion}/clusters endpoint reports components with available updates
+func TestPostClustersUpdateAvailable(t *testing.T) {
+	memDB, err := data.NewMemDB()
+	assert.NoErr(t, err)
+	assert.NoErr(t, data.VerifyPersistentStorage(memDB))
+	srv, err := newServer(memDB)
+	assert.NoErr(t, err)
+	defer srv.Close()
+	_, err = data.UpsertVersion(memDB, models.ComponentVersion{
+		Component: &models.Component{Name: "component-a"},
+		Version:   &models.Version{Train: "stable", Version: "1.1.0", Released: "2016-03-31T23:54:39Z", Data: &models.VersionData{}},
+	})
+	assert.NoErr(t, err)
+	jsonData := fmt.Sprintf(`{"components": [{"component": {"name": "component-a"}, "version": {"train": "stable", "version": "1.0.0"}}], "id": "%s"}`, clusterID)
+	resp, err := httpPost(srv, urlPath("v3", "clusters"), jsonData)
+	assert.NoErr(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK, "response code")
+	cluster := new(models.Cluster)
+	assert.NoErr(t, json.NewDecoder(resp.Body).Decode(cluster))
+	assert.Equal(t, len(cluster.Components), 1, "number of components")
+	assert.True(t, cluster.Components[0].UpdateAvailable != nil, "update available was nil")
+	assert.Equal(t, *cluster.Components[0].UpdateAvailable, "1.1.0", "update available")
+}
+
 func TestGetLatestVersions(t *testing.T) {
 	memDB, err := data.NewMemDB()
 	assert.NoErr(t, err)
@@ -436,6 +481,36 @@ func TestGetDoctor(t *testing.T) {
 	assert.Equal(t, doctorInfoResponse.Workflow.ID, clusterID, "cluster ID")
 }
 
+// tests the GET /metrics endpoint
+func TestMetrics(t *testing.T) {
+	db, err := data.NewMemDB()
+	assert.NoErr(t, err)
+	assert.NoErr(t, data.VerifyPersistentStorage(db))
+	srv, err := newServer(db)
+	assert.NoErr(t, err)
+	defer srv.Close()
+	resp, err := httpGet(srv, urlPath("v3", "clusters", "count"))
+	assert.NoErr(t, err)
+	resp.Body.Close()
+	resp, err = httpGet(srv, "metrics")
+	assert.NoErr(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK, "response code")
+	body := new(bytes.Buffer)
+	_, err = body.ReadFrom(resp.Body)
+	assert.NoErr(t, err)
+	for _, expected := range []string{
+		`workflow_manager_api_http_requests_total{code="200",operation="getClustersCount"} 1`,
+		`workflow_manager_api_db_query_duration_seconds_count{function="GetClusterCount"}`,
+		"workflow_manager_api_clusters 0",
+		"workflow_manager_api_active_clusters 0",
+		"workflow_manager_api_doctor_reports 0",
+		"# TYPE go_goroutines gauge",
+	} {
+		assert.True(t, strings.Contains(body.String(), expected), "metrics don't contain %s", expected)
+	}
+}
+
 func timeFuture() time.Time {
 	return futureTime
 }
@@ -477,3 +552,14 @@ func httpPostBasicAuth(s *httptest.Server, route, json, user, pass string) (*htt
 	req.SetBasicAuth(user, pass)
 	return client.Do(req)
 }
+
+func httpPostPublisherToken(s *httptest.Server, route, json, token string) (*http.Response, error) {
+	client := &http.Client{}
+	req, err := http.NewRequest("POST", s.URL+"/"+route, bytes.NewBuffer([]byte(json)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Publisher-Token", token)
+	return client.Do(req)
+}
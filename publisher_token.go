@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/deis/workflow-manager-api/pkg/data"
+)
+
+const (
+	publisherTokenCommand = "publisher-token"
+	publisherTokenUsage   = `usage: %s publisher-token <create|list|revoke> [options]
+
+  create  create a new token and print it. the token can't be retrieved again
+            -name <name>           the unique name of the token (required)
+            -components <a,b,...>  the components the token can publish (default all)
+            -trains <a,b,...>      the trains the token can publish to (default all)
+  list    list the name and scope of every token
+  revoke  delete a token so it can no longer be used
+            -name <name>           the name of the token (required)
+`
+)
+
+// runPublisherToken runs the publisher-token command with the given args (not including
+// "publisher-token" itself), and returns the process exit code
+func runPublisherToken(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, publisherTokenUsage, os.Args[0])
+		return 2
+	}
+	subcommand := args[0]
+	flags := flag.NewFlagSet(publisherTokenCommand+" "+subcommand, flag.ContinueOnError)
+	name := flags.String("name", "", "the name of the token")
+	components := flags.String("components", "", "comma separated list of components the token can publish")
+	trains := flags.String("trains", "", "comma separated list of trains the token can publish to")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if (subcommand == "create" || subcommand == "revoke") && *name == "" {
+		fmt.Fprintf(os.Stderr, publisherTokenUsage, os.Args[0])
+		return 2
+	}
+
+	db, err := data.NewDB()
+	if err != nil {
+		log.Printf("unable to create connection to DB (%s)", err)
+		return 1
+	}
+	defer db.Close()
+	if err := data.VerifyPersistentStorage(db); err != nil {
+		log.Printf("unable to verify persistent storage (%s)", err)
+		return 1
+	}
+	store := data.NewGormStore(db)
+
+	switch subcommand {
+	case "create":
+		var token string
+		token, _, err = store.CreatePublisherToken(*name, splitList(*components), splitList(*trains))
+		if err == nil {
+			fmt.Println(token)
+		}
+	case "list":
+		err = printPublisherTokens(store, os.Stdout)
+	case "revoke":
+		err = store.RevokePublisherToken(*name)
+	default:
+		fmt.Fprintf(os.Stderr, publisherTokenUsage, os.Args[0])
+		return 2
+	}
+	if err != nil {
+		log.Printf("publisher-token %s failed (%s)", subcommand, err)
+		return 1
+	}
+	return 0
+}
+
+// splitList splits a comma separated list, ignoring empty elements
+func splitList(str string) []string {
+	ret := []string{}
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+func printPublisherTokens(store data.PublisherTokenStore, out io.Writer) error {
+	tokens, err := store.ListPublisherTokens()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCOMPONENTS\tTRAINS\tCREATED AT")
+	for _, token := range tokens {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			token.Name,
+			scopeString(token.Components),
+			scopeString(token.Trains),
+			data.Timestamp{Time: token.CreatedAt},
+		)
+	}
+	return w.Flush()
+}
+
+func scopeString(scope []string) string {
+	if len(scope) == 0 {
+		return "(all)"
+	}
+	return strings.Join(scope, ",")
+}
@@ -0,0 +1,7 @@
+package rest
+
+const (
+	// IntervalQueryStringKey is the query string key for the size of the buckets that clusters are
+	// counted in
+	IntervalQueryStringKey = "interval"
+)
@@ -0,0 +1,14 @@
+package rest
+
+const (
+	// FromQueryStringKey is the query string key to select the earlier checkin of a diff by time
+	FromQueryStringKey = "from"
+	// ToQueryStringKey is the query string key to select the later checkin of a diff by time
+	ToQueryStringKey = "to"
+	// FromCheckinQueryStringKey is the query string key to select the earlier checkin of a diff by
+	// checkin ID
+	FromCheckinQueryStringKey = "from_checkin"
+	// ToCheckinQueryStringKey is the query string key to select the later checkin of a diff by
+	// checkin ID
+	ToCheckinQueryStringKey = "to_checkin"
+)
@@ -0,0 +1,12 @@
+package rest
+
+const (
+	// SinceQueryStringKey is the query string key to only return checkins at or after a specified
+	// time
+	SinceQueryStringKey = "since"
+	// UntilQueryStringKey is the query string key to only return checkins before a specified time
+	UntilQueryStringKey = "until"
+	// CursorQueryStringKey is the query string key for the opaque cursor that selects the next page
+	// of a paginated response
+	CursorQueryStringKey = "cursor"
+)